@@ -1,3 +1,9 @@
 module github.com/greymass/go-eosio
 
 go 1.17
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.2.0
+	github.com/gorilla/websocket v1.5.0
+	golang.org/x/crypto v0.14.0
+)
@@ -56,6 +56,20 @@ func TestStruct(t *testing.T) {
 	assert.Equal(t, s.Response.Answer, uint64(42))
 }
 
+func TestStructExtension(t *testing.T) {
+	var s struct {
+		Answer uint64
+		Other  []uint16 `eosio:"extension"`
+	}
+	err := unmarshal(structData[4:], &s)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Answer, uint64(42))
+	assert.True(t, s.Other == nil)
+	err = unmarshal(append(structData[4:], 0x01, 0x2a, 0x00), &s)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Other, []uint16{42})
+}
+
 func TestStructRecursive(t *testing.T) {
 	var s testRecursiveStruct
 	err := unmarshal(recursiveStructData, &s)
@@ -0,0 +1,147 @@
+// Package api contains a client for the nodeos chain API.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Client for the chain API of a nodeos instance.
+type Client struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewClient(url string) *Client {
+	return &Client{
+		URL:    strings.TrimSuffix(url, "/"),
+		Client: http.DefaultClient,
+	}
+}
+
+// Error returned by the API.
+type APIError struct {
+	StatusCode int            `json:"code"`
+	Message    string         `json:"message"`
+	Details    APIErrorDetail `json:"error"`
+}
+
+type APIErrorDetail struct {
+	Code    int64  `json:"code"`
+	Name    string `json:"name"`
+	What    string `json:"what"`
+	Details []struct {
+		Message string `json:"message"`
+	} `json:"details"`
+}
+
+func (e *APIError) Error() string {
+	msg := e.Details.What
+	if len(e.Details.Details) > 0 {
+		msg = e.Details.Details[0].Message
+	}
+	if msg == "" {
+		msg = e.Message
+	}
+	if e.Details.Name != "" {
+		msg = e.Details.Name + ": " + msg
+	}
+	return fmt.Sprintf("api: request failed (%d): %s", e.StatusCode, msg)
+}
+
+type GetAbiResponse struct {
+	AccountName chain.Name `json:"account_name"`
+	Abi         *chain.Abi `json:"abi,omitempty"`
+}
+
+type GetTableRowsRequest struct {
+	Code          chain.Name `json:"code"`
+	Scope         string     `json:"scope"`
+	Table         chain.Name `json:"table"`
+	JSON          bool       `json:"json"`
+	IndexPosition string     `json:"index_position,omitempty"`
+	KeyType       string     `json:"key_type,omitempty"`
+	EncodeType    string     `json:"encode_type,omitempty"`
+	LowerBound    string     `json:"lower_bound,omitempty"`
+	UpperBound    string     `json:"upper_bound,omitempty"`
+	Limit         int        `json:"limit,omitempty"`
+	Reverse       bool       `json:"reverse,omitempty"`
+	ShowPayer     bool       `json:"show_payer,omitempty"`
+}
+
+type GetTableRowsResponse struct {
+	// Rows as JSON objects, or hex encoded strings if the request had json set to false.
+	Rows    []json.RawMessage `json:"rows"`
+	More    bool              `json:"more"`
+	NextKey string            `json:"next_key"`
+}
+
+func (c *Client) GetInfo(ctx context.Context) (*chain.ChainInfo, error) {
+	var res chain.ChainInfo
+	err := c.Call(ctx, "/v1/chain/get_info", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Return the ABI of the account, nil if the account has no ABI set.
+func (c *Client) GetAbi(ctx context.Context, account chain.Name) (*chain.Abi, error) {
+	var res GetAbiResponse
+	err := c.Call(ctx, "/v1/chain/get_abi", map[string]interface{}{"account_name": account}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Abi, nil
+}
+
+// Return a single page of table rows, see Rows for iterating over all rows.
+func (c *Client) GetTableRows(ctx context.Context, req GetTableRowsRequest) (*GetTableRowsResponse, error) {
+	var res GetTableRowsResponse
+	err := c.Call(ctx, "/v1/chain/get_table_rows", req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Call an API endpoint, body is encoded as the JSON request and the response decoded into v.
+func (c *Client) Call(ctx context.Context, path string, body interface{}, v interface{}) error {
+	var b bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&b).Encode(body)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+path, &b)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		aerr := &APIError{}
+		err = json.NewDecoder(res.Body).Decode(aerr)
+		if err != nil || aerr.Message == "" {
+			aerr.Message = http.StatusText(res.StatusCode)
+		}
+		aerr.StatusCode = res.StatusCode
+		return aerr
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
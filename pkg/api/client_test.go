@@ -0,0 +1,81 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/api"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func newTestServer(handlers map[string]http.HandlerFunc) (*httptest.Server, *api.Client) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := handlers[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 404, "message": "Not Found", "error": {"code": 0, "name": "exception", "what": "unspecified", "details": [{"message": "Unknown Endpoint"}]}}`))
+			return
+		}
+		handler(w, r)
+	}))
+	return ts, api.NewClient(ts.URL + "/")
+}
+
+func TestGetInfo(t *testing.T) {
+	ts, client := newTestServer(map[string]http.HandlerFunc{
+		"/v1/chain/get_info": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{
+				"chain_id": "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906",
+				"head_block_num": 194827440,
+				"head_block_time": "2021-06-28T14:31:35.500",
+				"head_block_producer": "eosnationftw"
+			}`))
+		},
+	})
+	defer ts.Close()
+
+	info, err := client.GetInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, info.HeadBlockNum, chain.BlockNum(194827440))
+	assert.Equal(t, info.HeadBlockProducer, chain.N("eosnationftw"))
+}
+
+func TestGetAbi(t *testing.T) {
+	ts, client := newTestServer(map[string]http.HandlerFunc{
+		"/v1/chain/get_abi": func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				AccountName chain.Name `json:"account_name"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.AccountName == chain.N("eosio") {
+				w.Write([]byte(`{"account_name": "eosio"}`))
+				return
+			}
+			w.Write([]byte(`{"account_name": "eosio.token", "abi": {"version": "eosio::abi/1.1", "types": [], "structs": [], "actions": [], "tables": [], "ricardian_clauses": []}}`))
+		},
+	})
+	defer ts.Close()
+
+	abi, err := client.GetAbi(context.Background(), chain.N("eosio.token"))
+	assert.NoError(t, err)
+	assert.Equal(t, abi.Version, "eosio::abi/1.1")
+	abi, err = client.GetAbi(context.Background(), chain.N("eosio"))
+	assert.NoError(t, err)
+	assert.True(t, abi == nil)
+}
+
+func TestAPIError(t *testing.T) {
+	ts, client := newTestServer(nil)
+	defer ts.Close()
+
+	_, err := client.GetInfo(context.Background())
+	var aerr *api.APIError
+	assert.True(t, errors.As(err, &aerr))
+	assert.Equal(t, aerr.StatusCode, 404)
+	assert.Equal(t, aerr.Error(), "api: request failed (404): exception: Unknown Endpoint")
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Default number of rows fetched per request.
+const DefaultPageSize = 100
+
+var (
+	ErrNoNextKey = errors.New("api: more rows available but no next_key returned, nodeos too old?")
+	// Returned when the next_key equals the current bound, which happens when more rows than
+	// the page size share the same secondary key, use a larger PageSize to read past them.
+	ErrNoProgress = errors.New("api: next_key does not advance past the current bound")
+)
+
+// Query for the rows of a contract table.
+type TableQuery struct {
+	Code  chain.Name
+	Scope string
+	Table chain.Name
+	// Index to query, 1 or 0 is the primary index, 2 the first secondary index and so on.
+	Index int
+	// Key type of the index, defaults to the type of the bounds or i64 if there are none.
+	KeyType string
+	// Inclusive bounds, nil for no bound.
+	LowerBound chain.TableKey
+	UpperBound chain.TableKey
+	Reverse    bool
+	// Number of rows fetched per request, defaults to DefaultPageSize.
+	PageSize int
+	// Maximum number of rows to return, zero for no limit.
+	MaxRows int
+}
+
+// Iterator over the rows of a table, fetching pages of rows as needed, e.g.
+//
+//	rows := client.Rows(ctx, api.TableQuery{Code: contract, Scope: "alice", Table: chain.N("accounts")})
+//	for rows.Next() {
+//		var account Account
+//		err := rows.Decode(&account)
+//		...
+//	}
+//	if err := rows.Err(); err != nil {
+//		...
+//	}
+type RowIterator struct {
+	ctx   context.Context
+	c     *Client
+	req   GetTableRowsRequest
+	max   int
+	count int
+	page  []json.RawMessage
+	more  bool
+	row   chain.Bytes
+	err   error
+}
+
+// Iterate over the table rows matching the query, the context is used for all requests.
+func (c *Client) Rows(ctx context.Context, q TableQuery) *RowIterator {
+	req := GetTableRowsRequest{
+		Code:    q.Code,
+		Scope:   q.Scope,
+		Table:   q.Table,
+		KeyType: q.KeyType,
+		Limit:   q.PageSize,
+		Reverse: q.Reverse,
+	}
+	if q.Index > 1 {
+		req.IndexPosition = chain.TableIndexPosition(q.Index)
+	}
+	for _, key := range []chain.TableKey{q.LowerBound, q.UpperBound} {
+		if key != nil && req.KeyType == "" {
+			req.KeyType = key.KeyType()
+			req.EncodeType = key.EncodeType()
+		}
+	}
+	if req.KeyType == "" {
+		req.KeyType = "i64"
+	}
+	if q.LowerBound != nil {
+		req.LowerBound = q.LowerBound.Bound()
+	}
+	if q.UpperBound != nil {
+		req.UpperBound = q.UpperBound.Bound()
+	}
+	if req.Limit <= 0 {
+		req.Limit = DefaultPageSize
+	}
+	return &RowIterator{ctx: ctx, c: c, req: req, max: q.MaxRows, more: true}
+}
+
+// Advance to the next row, returns false when there are no more rows or an error occurred.
+func (it *RowIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.err = it.ctx.Err(); it.err != nil {
+		return false
+	}
+	if it.max > 0 && it.count >= it.max {
+		return false
+	}
+	for len(it.page) == 0 {
+		if !it.more {
+			return false
+		}
+		it.err = it.fetch()
+		if it.err != nil {
+			return false
+		}
+	}
+	it.row = nil
+	it.err = json.Unmarshal(it.page[0], &it.row)
+	if it.err != nil {
+		it.err = fmt.Errorf("api: unable to decode row: %w", it.err)
+		return false
+	}
+	it.page = it.page[1:]
+	it.count++
+	return true
+}
+
+// Binary data of the current row.
+func (it *RowIterator) Row() chain.Bytes {
+	return it.row
+}
+
+// Decode the current row into v, either an abi.Unmarshaler or a struct with fields matching the table type.
+func (it *RowIterator) Decode(v interface{}) error {
+	return chain.NewDecoder(bytes.NewReader(it.row)).Decode(v)
+}
+
+// Decode the current row using the contract ABI.
+func (it *RowIterator) DecodeABI(abi *chain.Abi) (map[string]interface{}, error) {
+	table := abi.GetTable(it.req.Table)
+	if table == nil {
+		return nil, fmt.Errorf("api: unknown table %v", it.req.Table)
+	}
+	decoded, err := abi.Decode(bytes.NewReader(it.row), table.Type)
+	if err != nil {
+		return nil, err
+	}
+	rv, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("api: row is not a map[string]interface{}")
+	}
+	return rv, nil
+}
+
+// Error that stopped the iteration, nil if all rows were read.
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// helpers
+
+func (it *RowIterator) fetch() error {
+	req := it.req
+	if it.max > 0 && it.max-it.count < req.Limit {
+		req.Limit = it.max - it.count
+	}
+	res, err := it.c.GetTableRows(it.ctx, req)
+	if err != nil {
+		return err
+	}
+	it.page = res.Rows
+	it.more = res.More
+	if !it.more {
+		return nil
+	}
+	if res.NextKey == "" {
+		return ErrNoNextKey
+	}
+	// the next_key is not always in the bound format, e.g. for i256 keys
+	next, err := chain.ParseTableKey(it.req.KeyType, res.NextKey)
+	if err != nil {
+		return err
+	}
+	bound := &it.req.LowerBound
+	if it.req.Reverse {
+		bound = &it.req.UpperBound
+	}
+	if next.Bound() == *bound {
+		return fmt.Errorf("%w: %s", ErrNoProgress, res.NextKey)
+	}
+	it.req.EncodeType = next.EncodeType()
+	*bound = next.Bound()
+	return nil
+}
@@ -0,0 +1,225 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/api"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+type testRow struct {
+	ID    uint64
+	Owner chain.Name
+}
+
+// serves a table with 25 rows with ids 0-24, emulating the nodeos paging behavior
+func tableHandler(requests *[]api.GetTableRowsRequest) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req api.GetTableRowsRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		*requests = append(*requests, req)
+		lower, upper := uint64(0), uint64(24)
+		if req.LowerBound != "" {
+			lower, _ = strconv.ParseUint(req.LowerBound, 10, 64)
+		}
+		if req.UpperBound != "" {
+			upper, _ = strconv.ParseUint(req.UpperBound, 10, 64)
+		}
+		var ids []uint64
+		for id := lower; id <= upper; id++ {
+			ids = append(ids, id)
+		}
+		if req.Reverse {
+			for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+				ids[i], ids[j] = ids[j], ids[i]
+			}
+		}
+		res := api.GetTableRowsResponse{Rows: []json.RawMessage{}}
+		for i, id := range ids {
+			if i == req.Limit {
+				res.More = true
+				res.NextKey = strconv.FormatUint(id, 10)
+				break
+			}
+			b := bytes.NewBuffer(nil)
+			chain.NewEncoder(b).Encode(testRow{id, chain.N("foo")})
+			row, _ := json.Marshal(chain.Bytes(b.Bytes()))
+			res.Rows = append(res.Rows, row)
+		}
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+func readRows(t *testing.T, rows *api.RowIterator) []uint64 {
+	var ids []uint64
+	for rows.Next() {
+		var row testRow
+		assert.NoError(t, rows.Decode(&row))
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+func TestRows(t *testing.T) {
+	var requests []api.GetTableRowsRequest
+	ts, client := newTestServer(map[string]http.HandlerFunc{"/v1/chain/get_table_rows": tableHandler(&requests)})
+	defer ts.Close()
+	ctx := context.Background()
+	query := api.TableQuery{Code: chain.N("foo"), Scope: "foo", Table: chain.N("bar"), PageSize: 10}
+
+	rows := client.Rows(ctx, query)
+	ids := readRows(t, rows)
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, len(ids), 25)
+	for i, id := range ids {
+		assert.Equal(t, id, uint64(i))
+	}
+	assert.Equal(t, len(requests), 3)
+	assert.Equal(t, requests[0].JSON, false)
+	assert.Equal(t, requests[0].KeyType, "i64")
+	assert.Equal(t, requests[1].LowerBound, "10")
+	assert.Equal(t, requests[2].LowerBound, "20")
+
+	// max row budget
+	requests = nil
+	query.MaxRows = 15
+	rows = client.Rows(ctx, query)
+	ids = readRows(t, rows)
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, len(ids), 15)
+	assert.Equal(t, len(requests), 2)
+	assert.Equal(t, requests[1].Limit, 5)
+
+	// reverse with bounds
+	requests = nil
+	query.MaxRows = 0
+	query.Reverse = true
+	query.LowerBound = chain.I64Key(5)
+	rows = client.Rows(ctx, query)
+	ids = readRows(t, rows)
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, len(ids), 20)
+	assert.Equal(t, ids[0], uint64(24))
+	assert.Equal(t, ids[19], uint64(5))
+	assert.Equal(t, requests[1].LowerBound, "5")
+	assert.Equal(t, requests[1].UpperBound, "14")
+}
+
+func TestRowsDecodeABI(t *testing.T) {
+	var requests []api.GetTableRowsRequest
+	ts, client := newTestServer(map[string]http.HandlerFunc{"/v1/chain/get_table_rows": tableHandler(&requests)})
+	defer ts.Close()
+	var abi chain.Abi
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"version": "eosio::abi/1.1",
+		"structs": [{"name": "row", "base": "", "fields": [{"name": "id", "type": "uint64"}, {"name": "owner", "type": "name"}]}],
+		"tables": [{"name": "bar", "index_type": "i64", "key_names": [], "key_types": [], "type": "row"}]
+	}`), &abi))
+
+	rows := client.Rows(context.Background(), api.TableQuery{Code: chain.N("foo"), Scope: "foo", Table: chain.N("bar"), MaxRows: 1})
+	assert.True(t, rows.Next())
+	row, err := rows.DecodeABI(&abi)
+	assert.NoError(t, err)
+	assert.Equal(t, row, map[string]interface{}{"id": chain.Uint64(0), "owner": chain.N("foo")})
+	assert.True(t, !rows.Next())
+	assert.NoError(t, rows.Err())
+
+	abi.Tables = nil
+	_, err = rows.DecodeABI(&abi)
+	assert.NotNil(t, err)
+}
+
+func TestRowsI256(t *testing.T) {
+	var requests []api.GetTableRowsRequest
+	ts, client := newTestServer(map[string]http.HandlerFunc{
+		"/v1/chain/get_table_rows": func(w http.ResponseWriter, r *http.Request) {
+			var req api.GetTableRowsRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			requests = append(requests, req)
+			if len(requests) == 1 {
+				w.Write([]byte(`{"rows": ["00"], "more": true, "next_key": "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"}`))
+			} else {
+				w.Write([]byte(`{"rows": ["01"], "more": false, "next_key": ""}`))
+			}
+		},
+	})
+	defer ts.Close()
+
+	rows := client.Rows(context.Background(), api.TableQuery{Code: chain.N("foo"), Scope: "foo", Table: chain.N("bar"), Index: 2, KeyType: "i256"})
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Row(), chain.Bytes{0x00})
+	assert.True(t, rows.Next())
+	assert.Equal(t, rows.Row(), chain.Bytes{0x01})
+	assert.True(t, !rows.Next())
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, requests[0].IndexPosition, "secondary")
+	assert.Equal(t, requests[1].EncodeType, "hex")
+	// the next_key is sent back unchanged
+	assert.Equal(t, requests[1].LowerBound, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+}
+
+func TestRowsErrors(t *testing.T) {
+	var requests []api.GetTableRowsRequest
+	ts, client := newTestServer(map[string]http.HandlerFunc{"/v1/chain/get_table_rows": tableHandler(&requests)})
+	defer ts.Close()
+	query := api.TableQuery{Code: chain.N("foo"), Scope: "foo", Table: chain.N("bar"), PageSize: 10}
+
+	// context cancelled while iterating
+	ctx, cancel := context.WithCancel(context.Background())
+	rows := client.Rows(ctx, query)
+	assert.True(t, rows.Next())
+	cancel()
+	assert.True(t, !rows.Next())
+	assert.True(t, errors.Is(rows.Err(), context.Canceled))
+
+	// api error
+	rows = api.NewClient(ts.URL+"/v1/chain/nope").Rows(context.Background(), query)
+	assert.True(t, !rows.Next())
+	var aerr *api.APIError
+	assert.True(t, errors.As(rows.Err(), &aerr))
+
+	// more rows without a next key
+	ts2, client := newTestServer(map[string]http.HandlerFunc{
+		"/v1/chain/get_table_rows": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"rows": ["00"], "more": true}`))
+		},
+	})
+	defer ts2.Close()
+	rows = client.Rows(context.Background(), query)
+	assert.True(t, !rows.Next())
+	assert.True(t, errors.Is(rows.Err(), api.ErrNoNextKey))
+
+	// more rows sharing a secondary key than fit in a page
+	requests = nil
+	ts3, client := newTestServer(map[string]http.HandlerFunc{
+		"/v1/chain/get_table_rows": func(w http.ResponseWriter, r *http.Request) {
+			var req api.GetTableRowsRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			requests = append(requests, req)
+			res := api.GetTableRowsResponse{More: true, NextKey: "7"}
+			for i := 0; i < req.Limit; i++ {
+				res.Rows = append(res.Rows, json.RawMessage(`"00"`))
+			}
+			json.NewEncoder(w).Encode(res)
+		},
+	})
+	defer ts3.Close()
+	query.Index = 2
+	query.PageSize = 5
+	rows = client.Rows(context.Background(), query)
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	assert.Equal(t, count, 5)
+	assert.True(t, errors.Is(rows.Err(), api.ErrNoProgress))
+	assert.Equal(t, len(requests), 2)
+	assert.Equal(t, requests[1].LowerBound, "7")
+}
@@ -1,9 +1,11 @@
 package chain
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 
 	"github.com/greymass/go-eosio/pkg/abi"
 )
@@ -19,8 +21,12 @@ type Abi struct {
 	ErrorMessages    []AbiErrorMessage `json:"error_messages,omitempty"`
 	Extensions       []*AbiExtension   `json:"abi_extensions,omitempty"`
 	Variants         []AbiVariant      `json:"variants,omitempty" eosio:"extension"`
+	ActionResults    []AbiActionResult `json:"action_results,omitempty" eosio:"extension"`
+	KvTables         AbiKvTables       `json:"kv_tables,omitempty" eosio:"extension"`
 }
 
+var ErrInvalidAbi = errors.New("invalid abi")
+
 type AbiType struct {
 	NewTypeName string `json:"new_type_name"`
 	Type        string `json:"type"`
@@ -31,6 +37,12 @@ type AbiVariant struct {
 	Types []string `json:"types"`
 }
 
+// Type of the value returned by an action, added in eosio::abi/1.2.
+type AbiActionResult struct {
+	Name       Name   `json:"name"`
+	ResultType string `json:"result_type"`
+}
+
 type AbiStruct struct {
 	Name   string     `json:"name"`
 	Base   string     `json:"base"`
@@ -116,6 +128,138 @@ func (a Abi) GetVariant(name string) *AbiVariant {
 	return nil
 }
 
+func (a Abi) GetActionResult(name Name) *AbiActionResult {
+	for _, t := range a.ActionResults {
+		if t.Name == name {
+			return &t
+		}
+	}
+	return nil
+}
+
+// Validate the ABI the same way nodeos does when it is set, type names must be unique, all referenced
+// types must be defined and type aliases and struct inheritance can not be circular.
+func (a Abi) Validate() error {
+	if !strings.HasPrefix(a.Version, "eosio::abi/1.") {
+		return fmt.Errorf("%w: unsupported version %q", ErrInvalidAbi, a.Version)
+	}
+	defined := make(map[string]bool)
+	define := func(name string) error {
+		if defined[name] || abiBuiltins[name] {
+			return fmt.Errorf("%w: type %s already exists", ErrInvalidAbi, name)
+		}
+		defined[name] = true
+		return nil
+	}
+	for _, t := range a.Types {
+		if err := define(t.NewTypeName); err != nil {
+			return err
+		}
+	}
+	for _, s := range a.Structs {
+		if err := define(s.Name); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Variants {
+		if err := define(v.Name); err != nil {
+			return err
+		}
+	}
+	isType := func(name string) bool {
+		name = strings.TrimSuffix(name, "?")
+		name = strings.TrimSuffix(name, "$")
+		name = strings.TrimSuffix(name, "[]")
+		return abiBuiltins[name] || defined[name]
+	}
+	for _, t := range a.Types {
+		seen := map[string]bool{t.NewTypeName: true}
+		for alias := &t; alias != nil; alias = a.GetType(alias.Type) {
+			if !isType(alias.Type) {
+				return fmt.Errorf("%w: invalid type %s used in type %s", ErrInvalidAbi, alias.Type, alias.NewTypeName)
+			}
+			if seen[alias.Type] {
+				return fmt.Errorf("%w: circular type reference in type %s", ErrInvalidAbi, t.NewTypeName)
+			}
+			seen[alias.Type] = true
+		}
+	}
+	for _, s := range a.Structs {
+		seen := map[string]bool{s.Name: true}
+		for base := a.GetStruct(s.Base); base != nil; base = a.GetStruct(base.Base) {
+			if seen[base.Name] {
+				return fmt.Errorf("%w: circular reference in struct %s", ErrInvalidAbi, s.Name)
+			}
+			seen[base.Name] = true
+		}
+		if s.Base != "" && a.GetStruct(s.Base) == nil {
+			return fmt.Errorf("%w: invalid base %s of struct %s", ErrInvalidAbi, s.Base, s.Name)
+		}
+		for _, f := range s.Fields {
+			if !isType(f.Type) {
+				return fmt.Errorf("%w: invalid type %s used in field %s.%s", ErrInvalidAbi, f.Type, s.Name, f.Name)
+			}
+		}
+	}
+	for _, v := range a.Variants {
+		for _, t := range v.Types {
+			if !isType(t) {
+				return fmt.Errorf("%w: invalid type %s used in variant %s", ErrInvalidAbi, t, v.Name)
+			}
+		}
+	}
+	seen := make(map[Name]bool)
+	for _, act := range a.Actions {
+		if seen[act.Name] {
+			return fmt.Errorf("%w: duplicate action %s", ErrInvalidAbi, act.Name)
+		}
+		seen[act.Name] = true
+		if !isType(act.Type) {
+			return fmt.Errorf("%w: invalid type %s used in action %s", ErrInvalidAbi, act.Type, act.Name)
+		}
+	}
+	seen = make(map[Name]bool)
+	for _, t := range a.Tables {
+		if seen[t.Name] {
+			return fmt.Errorf("%w: duplicate table %s", ErrInvalidAbi, t.Name)
+		}
+		seen[t.Name] = true
+		if !isType(t.Type) {
+			return fmt.Errorf("%w: invalid type %s used in table %s", ErrInvalidAbi, t.Type, t.Name)
+		}
+	}
+	seen = make(map[Name]bool)
+	for _, r := range a.ActionResults {
+		if seen[r.Name] {
+			return fmt.Errorf("%w: duplicate action result %s", ErrInvalidAbi, r.Name)
+		}
+		seen[r.Name] = true
+		if !isType(r.ResultType) {
+			return fmt.Errorf("%w: invalid type %s used in action result %s", ErrInvalidAbi, r.ResultType, r.Name)
+		}
+	}
+	for name, t := range a.KvTables {
+		if !isType(t.Type) {
+			return fmt.Errorf("%w: invalid type %s used in kv table %s", ErrInvalidAbi, t.Type, name)
+		}
+		if t.PrimaryIndex.Name == 0 {
+			return fmt.Errorf("%w: kv table %s has no primary index", ErrInvalidAbi, name)
+		}
+		if !isType(t.PrimaryIndex.Type) {
+			return fmt.Errorf("%w: invalid type %s used in primary index of kv table %s", ErrInvalidAbi, t.PrimaryIndex.Type, name)
+		}
+		for index, si := range t.SecondaryIndices {
+			if index == t.PrimaryIndex.Name {
+				return fmt.Errorf("%w: duplicate index %s in kv table %s", ErrInvalidAbi, index, name)
+			}
+			if !isType(si.Type) {
+				return fmt.Errorf("%w: invalid type %s used in index %s of kv table %s", ErrInvalidAbi, si.Type, index, name)
+			}
+		}
+	}
+	return nil
+}
+
 func (a Abi) DecodeAction(r io.Reader, name Name) (interface{}, error) {
 	act := a.GetAction(name)
 	if act == nil {
@@ -132,6 +276,24 @@ func (a Abi) EncodeAction(w io.Writer, name Name, v interface{}) error {
 	return a.Encode(w, act.Type, v)
 }
 
+// Decode the return value of an action.
+func (a Abi) DecodeActionResult(r io.Reader, name Name) (interface{}, error) {
+	res := a.GetActionResult(name)
+	if res == nil {
+		return nil, fmt.Errorf("unknown action result %v", name)
+	}
+	return a.Decode(r, res.ResultType)
+}
+
+// Encode the return value of an action.
+func (a Abi) EncodeActionResult(w io.Writer, name Name, v interface{}) error {
+	res := a.GetActionResult(name)
+	if res == nil {
+		return fmt.Errorf("unknown action result %v", name)
+	}
+	return a.Encode(w, res.ResultType, v)
+}
+
 func (a Abi) Decode(r io.Reader, name string) (interface{}, error) {
 	res := resolver{&a, make(map[string]*resolvedType)}
 	t := res.resolve(name)
@@ -356,6 +518,11 @@ func (a Abi) encodeInner(enc *abi.Encoder, t *resolvedType, v interface{}) error
 			if vv, ok = v.(ExtendedAsset); ok {
 				err = vv.MarshalABI(enc)
 			}
+		case "extended_symbol":
+			var vv ExtendedSymbol
+			if vv, ok = v.(ExtendedSymbol); ok {
+				err = vv.MarshalABI(enc)
+			}
 		case "name":
 			var vv Name
 			if vv, ok = v.(Name); ok {
@@ -539,6 +706,10 @@ func (a Abi) decodeInner(dec *abi.Decoder, t *resolvedType, v *interface{}) erro
 			var rv ExtendedAsset
 			err = rv.UnmarshalABI(dec)
 			*v = rv
+		case "extended_symbol":
+			var rv ExtendedSymbol
+			err = rv.UnmarshalABI(dec)
+			*v = rv
 		case "name":
 			var rv Name
 			err = rv.UnmarshalABI(dec)
@@ -574,6 +745,17 @@ func (a Abi) decodeInner(dec *abi.Decoder, t *resolvedType, v *interface{}) erro
 	return err
 }
 
+// types that are built into the encoder and decoder
+var abiBuiltins = map[string]bool{
+	"bool": true, "string": true, "bytes": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "uint128": true,
+	"int8": true, "int16": true, "int32": true, "int64": true, "int128": true,
+	"float32": true, "float64": true, "float128": true, "varuint32": true, "varint32": true,
+	"asset": true, "block_timestamp_type": true, "checksum160": true, "checksum256": true,
+	"checksum512": true, "extended_asset": true, "extended_symbol": true, "name": true, "public_key": true,
+	"signature": true, "symbol_code": true, "symbol": true, "time_point_sec": true, "time_point": true,
+}
+
 // create a tree of types that's easier to traverse
 
 type resolver struct {
@@ -3,6 +3,7 @@ package chain_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/greymass/go-eosio/internal/assert"
@@ -249,3 +250,89 @@ func TestAbiEncode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, buf.Bytes(), transferData)
 }
+
+var sumAbi = loadAbi(`
+{
+    "version": "eosio::abi/1.2",
+    "types": [],
+    "structs": [
+        {
+            "name": "sum",
+            "base": "",
+            "fields": [
+                {"name": "a", "type": "uint32"},
+                {"name": "b", "type": "uint32"}
+            ]
+        }
+    ],
+    "actions": [
+        {"name": "sum", "type": "sum", "ricardian_contract": ""}
+    ],
+    "tables": [],
+    "ricardian_clauses": [],
+    "action_results": [
+        {"name": "sum", "result_type": "uint32"}
+    ]
+}
+`)
+
+func TestAbiActionResults(t *testing.T) {
+	assert.Equal(t, sumAbi.ActionResults, []chain.AbiActionResult{{Name: chain.N("sum"), ResultType: "uint32"}})
+	assert.Equal(t, *sumAbi.GetActionResult(chain.N("sum")), sumAbi.ActionResults[0])
+	assert.True(t, sumAbi.GetActionResult(chain.N("foo")) == nil)
+
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, chain.NewEncoder(buf).Encode(sumAbi))
+	// empty variants followed by the action results and empty kv tables
+	assert.Equal(t, buf.Bytes()[buf.Len()-18:], mustDecodeHex("0001000000000000a4c60675696e74333200"))
+	var decoded chain.Abi
+	assert.NoError(t, chain.NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&decoded))
+	assert.Equal(t, decoded.ActionResults, sumAbi.ActionResults)
+
+	// abi without the extension
+	decoded = chain.Abi{}
+	assert.NoError(t, chain.NewDecoder(bytes.NewReader(buf.Bytes()[:buf.Len()-17])).Decode(&decoded))
+	assert.True(t, decoded.ActionResults == nil)
+
+	buf.Reset()
+	assert.NoError(t, sumAbi.EncodeActionResult(buf, chain.N("sum"), uint32(7)))
+	assert.Equal(t, buf.Bytes(), []byte{0x07, 0x00, 0x00, 0x00})
+	rv, err := sumAbi.DecodeActionResult(bytes.NewReader(buf.Bytes()), chain.N("sum"))
+	assert.NoError(t, err)
+	assert.Equal(t, rv, uint32(7))
+
+	_, err = tokenAbi.DecodeActionResult(bytes.NewReader(buf.Bytes()), chain.N("transfer"))
+	assert.NotNil(t, err)
+	assert.NotNil(t, tokenAbi.EncodeActionResult(buf, chain.N("transfer"), uint32(7)))
+}
+
+func TestAbiValidate(t *testing.T) {
+	assert.NoError(t, sumAbi.Validate())
+	// partial abi, the close action has no struct definition
+	assert.True(t, errors.Is(tokenAbi.Validate(), chain.ErrInvalidAbi))
+
+	invalid := []string{
+		`{"version": "eosio::abi/2.0"}`,
+		`{"version": "eosio::abi/1.1", "types": [{"new_type_name": "name", "type": "uint64"}]}`,
+		`{"version": "eosio::abi/1.1", "types": [{"new_type_name": "foo", "type": "bar"}]}`,
+		`{"version": "eosio::abi/1.1", "types": [{"new_type_name": "foo", "type": "bar"}, {"new_type_name": "bar", "type": "foo"}]}`,
+		`{"version": "eosio::abi/1.1", "structs": [{"name": "foo", "base": "", "fields": []}, {"name": "foo", "base": "", "fields": []}]}`,
+		`{"version": "eosio::abi/1.1", "structs": [{"name": "foo", "base": "bar", "fields": []}]}`,
+		`{"version": "eosio::abi/1.1", "structs": [{"name": "foo", "base": "bar", "fields": []}, {"name": "bar", "base": "foo", "fields": []}]}`,
+		`{"version": "eosio::abi/1.1", "structs": [{"name": "foo", "base": "", "fields": [{"name": "a", "type": "bar[]"}]}]}`,
+		`{"version": "eosio::abi/1.1", "variants": [{"name": "foo", "types": ["uint8", "bar"]}]}`,
+		`{"version": "eosio::abi/1.1", "actions": [{"name": "foo", "type": "bar"}]}`,
+		`{"version": "eosio::abi/1.1", "actions": [{"name": "foo", "type": "name"}, {"name": "foo", "type": "name"}]}`,
+		`{"version": "eosio::abi/1.1", "tables": [{"name": "foo", "type": "bar"}]}`,
+		`{"version": "eosio::abi/1.2", "action_results": [{"name": "foo", "result_type": "bar"}]}`,
+		`{"version": "eosio::abi/1.2", "kv_tables": {"foo": {"type": "bar", "primary_index": {"name": "id", "type": "uint64"}}}}`,
+		`{"version": "eosio::abi/1.2", "kv_tables": {"foo": {"type": "name", "primary_index": {"name": "", "type": "uint64"}}}}`,
+		`{"version": "eosio::abi/1.2", "kv_tables": {"foo": {"type": "name", "primary_index": {"name": "id", "type": "bar"}}}}`,
+		`{"version": "eosio::abi/1.2", "kv_tables": {"foo": {"type": "name", "primary_index": {"name": "id", "type": "uint64"}, "secondary_indices": {"id": {"type": "uint64"}}}}}`,
+		`{"version": "eosio::abi/1.2", "kv_tables": {"foo": {"type": "name", "primary_index": {"name": "id", "type": "uint64"}, "secondary_indices": {"bar": {"type": "bar"}}}}}`,
+	}
+	for _, v := range invalid {
+		err := loadAbi(v).Validate()
+		assert.True(t, errors.Is(err, chain.ErrInvalidAbi))
+	}
+}
@@ -3,13 +3,23 @@ package chain
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/greymass/go-eosio/pkg/abi"
 )
 
-var ErrInvalidAssetString = errors.New("invalid asset string")
+// Largest amount of an asset allowed by nodeos, 2^62 - 1.
+const MaxAssetAmount = 1<<62 - 1
+
+var (
+	ErrInvalidAssetString = errors.New("invalid asset string")
+	ErrSymbolMismatch     = errors.New("asset symbol mismatch")
+	ErrContractMismatch   = errors.New("asset contract mismatch")
+	ErrAssetOverflow      = errors.New("asset amount out of range")
+	ErrDivisionByZero     = errors.New("asset division by zero")
+)
 
 type Asset struct {
 	Value int64
@@ -79,38 +89,183 @@ func A(s string) *Asset {
 	return a
 }
 
+// Create a new asset from a decimal string such as "1.23456", rounding it to the symbol precision using mode.
+func NewAssetFromDecimal(s string, symbol Symbol, mode big.RoundingMode) (Asset, error) {
+	if !isDecimal(s) {
+		return Asset{}, fmt.Errorf("%w: invalid decimal %q", ErrInvalidAssetString, s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Asset{}, fmt.Errorf("%w: invalid decimal %q", ErrInvalidAssetString, s)
+	}
+	return NewAssetFromRat(r, symbol, mode)
+}
+
 // String representation of asset, e.g. "1.0000 EOS"
 func (a *Asset) String() string {
-	s := ""
+	return a.Decimal() + " " + a.Symbol.Name()
+}
+
+// Exact decimal representation of the amount without symbol name, e.g. "1.0000"
+func (a Asset) Decimal() string {
+	u := uint64(a.Value)
 	if a.Value < 0 {
-		s = "-"
-	}
-	v := int(a.Value)
-	if v < 0 {
-		v = -v
-	}
-	s += fmt.Sprint(v / a.Symbol.Precision())
-	if a.Symbol.Decimals() > 0 {
-		f := v % a.Symbol.Precision()
-		fs := strconv.Itoa(f)
-		for len(fs) < a.Symbol.Decimals() {
-			fs = "0" + fs
+		u = -u
+	}
+	s := strconv.FormatUint(u, 10)
+	if d := a.Symbol.Decimals(); d > 0 {
+		if len(s) <= d {
+			s = strings.Repeat("0", d-len(s)+1) + s
 		}
-		s += "." + fs
+		s = s[:len(s)-d] + "." + s[len(s)-d:]
+	}
+	if a.Value < 0 {
+		s = "-" + s
 	}
-	return s + " " + a.Symbol.Name()
+	return s
 }
 
+// Amount as the nearest float64, note that precision is lost for large amounts.
 func (a *Asset) FloatValue() float64 {
-	// TODO: do this without the string round-trip
-	// the naive version of float64(Value) / float64(Precision()) leads to rounding errors
-	rv, err := strconv.ParseFloat(strings.Split(a.String(), " ")[0], 64)
-	if err != nil {
-		panic(err)
-	}
+	rv, _ := a.Rat().Float64()
 	return rv
 }
 
+// Returns true if the amount is within the range allowed by nodeos and the symbol is valid.
+func (a Asset) IsValid() bool {
+	return isAssetAmountValid(a.Value) && a.Symbol.IsValid()
+}
+
+func (a Asset) IsZero() bool {
+	return a.Value == 0
+}
+
+// Compare the amount of two assets with the same symbol, returns -1 if a < b, 0 if a == b and 1 if a > b.
+func (a Asset) Cmp(b Asset) (int, error) {
+	if a.Symbol != b.Symbol {
+		return 0, fmt.Errorf("%w: %s and %s", ErrSymbolMismatch, a.Symbol, b.Symbol)
+	}
+	switch {
+	case a.Value < b.Value:
+		return -1, nil
+	case a.Value > b.Value:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (a Asset) Add(b Asset) (Asset, error) {
+	if a.Symbol != b.Symbol {
+		return Asset{}, fmt.Errorf("%w: %s and %s", ErrSymbolMismatch, a.Symbol, b.Symbol)
+	}
+	if !isAssetAmountValid(a.Value) || !isAssetAmountValid(b.Value) {
+		return Asset{}, ErrAssetOverflow
+	}
+	return a.withValue(a.Value + b.Value)
+}
+
+func (a Asset) Sub(b Asset) (Asset, error) {
+	if a.Symbol != b.Symbol {
+		return Asset{}, fmt.Errorf("%w: %s and %s", ErrSymbolMismatch, a.Symbol, b.Symbol)
+	}
+	if !isAssetAmountValid(a.Value) || !isAssetAmountValid(b.Value) {
+		return Asset{}, ErrAssetOverflow
+	}
+	return a.withValue(a.Value - b.Value)
+}
+
+// Multiply the amount by n.
+func (a Asset) Mul(n int64) (Asset, error) {
+	v := new(big.Int).Mul(big.NewInt(a.Value), big.NewInt(n))
+	if !v.IsInt64() {
+		return Asset{}, ErrAssetOverflow
+	}
+	return a.withValue(v.Int64())
+}
+
+// Multiply the amount by r, rounding the result to the symbol precision using mode.
+func (a Asset) MulRat(r *big.Rat, mode big.RoundingMode) (Asset, error) {
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(a.Value), r)
+	return a.withUnits(v, mode)
+}
+
+// Divide the amount by n, rounding the result to the symbol precision using mode.
+func (a Asset) Div(n int64, mode big.RoundingMode) (Asset, error) {
+	if n == 0 {
+		return Asset{}, ErrDivisionByZero
+	}
+	return a.withUnits(big.NewRat(a.Value, n), mode)
+}
+
+// Return the asset with the amount negated.
+func (a Asset) Neg() Asset {
+	return Asset{-a.Value, a.Symbol}
+}
+
+// Exact amount of the asset, e.g. 10001/10000 for "1.0001 EOS".
+func (a Asset) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(a.Value), pow10(a.Symbol.Decimals()))
+}
+
+// Create a new asset from an exact amount, rounding it to the symbol precision using mode.
+func NewAssetFromRat(r *big.Rat, symbol Symbol, mode big.RoundingMode) (Asset, error) {
+	units := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(symbol.Decimals())))
+	return Asset{Symbol: symbol}.withUnits(units, mode)
+}
+
+// Returns true if the amount is within the range allowed by nodeos and the symbol is valid.
+func (ea ExtendedAsset) IsValid() bool {
+	return ea.Quantity.IsValid()
+}
+
+func (ea ExtendedAsset) IsZero() bool {
+	return ea.Quantity.IsZero()
+}
+
+// Compare the amount of two assets with the same symbol and contract.
+func (ea ExtendedAsset) Cmp(b ExtendedAsset) (int, error) {
+	if ea.Contract != b.Contract {
+		return 0, fmt.Errorf("%w: %s and %s", ErrContractMismatch, ea.Contract, b.Contract)
+	}
+	return ea.Quantity.Cmp(b.Quantity)
+}
+
+func (ea ExtendedAsset) Add(b ExtendedAsset) (ExtendedAsset, error) {
+	if ea.Contract != b.Contract {
+		return ExtendedAsset{}, fmt.Errorf("%w: %s and %s", ErrContractMismatch, ea.Contract, b.Contract)
+	}
+	q, err := ea.Quantity.Add(b.Quantity)
+	return ExtendedAsset{q, ea.Contract}, err
+}
+
+func (ea ExtendedAsset) Sub(b ExtendedAsset) (ExtendedAsset, error) {
+	if ea.Contract != b.Contract {
+		return ExtendedAsset{}, fmt.Errorf("%w: %s and %s", ErrContractMismatch, ea.Contract, b.Contract)
+	}
+	q, err := ea.Quantity.Sub(b.Quantity)
+	return ExtendedAsset{q, ea.Contract}, err
+}
+
+func (ea ExtendedAsset) Mul(n int64) (ExtendedAsset, error) {
+	q, err := ea.Quantity.Mul(n)
+	return ExtendedAsset{q, ea.Contract}, err
+}
+
+func (ea ExtendedAsset) MulRat(r *big.Rat, mode big.RoundingMode) (ExtendedAsset, error) {
+	q, err := ea.Quantity.MulRat(r, mode)
+	return ExtendedAsset{q, ea.Contract}, err
+}
+
+func (ea ExtendedAsset) Div(n int64, mode big.RoundingMode) (ExtendedAsset, error) {
+	q, err := ea.Quantity.Div(n, mode)
+	return ExtendedAsset{q, ea.Contract}, err
+}
+
+func (ea ExtendedAsset) Neg() ExtendedAsset {
+	return ExtendedAsset{ea.Quantity.Neg(), ea.Contract}
+}
+
 // abi.Marshaler conformance
 
 func (a *Asset) MarshalABI(e *abi.Encoder) error {
@@ -165,3 +320,73 @@ func (a *Asset) UnmarshalText(text []byte) error {
 	}
 	return err
 }
+
+// helpers
+
+func isAssetAmountValid(v int64) bool {
+	return v >= -MaxAssetAmount && v <= MaxAssetAmount
+}
+
+func (a Asset) withValue(v int64) (Asset, error) {
+	if !isAssetAmountValid(v) {
+		return Asset{}, ErrAssetOverflow
+	}
+	return Asset{v, a.Symbol}, nil
+}
+
+// round units to an integer amount and return it with the symbol of a
+func (a Asset) withUnits(units *big.Rat, mode big.RoundingMode) (Asset, error) {
+	v := roundRat(units, mode)
+	if !v.IsInt64() {
+		return Asset{}, ErrAssetOverflow
+	}
+	return a.withValue(v.Int64())
+}
+
+func roundRat(r *big.Rat, mode big.RoundingMode) *big.Int {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+	away := false
+	switch mode {
+	case big.ToZero:
+	case big.AwayFromZero:
+		away = true
+	case big.ToNegativeInf:
+		away = r.Sign() < 0
+	case big.ToPositiveInf:
+		away = r.Sign() > 0
+	case big.ToNearestEven, big.ToNearestAway:
+		c := new(big.Int).Lsh(new(big.Int).Abs(rem), 1).Cmp(r.Denom())
+		away = c > 0 || (c == 0 && (mode == big.ToNearestAway || q.Bit(0) == 1))
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+	return q
+}
+
+// returns true if s is an optionally signed decimal number without exponent, e.g. "-1.5"
+func isDecimal(s string) bool {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	digits := 0
+	point := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			digits++
+		case s[i] == '.' && !point:
+			point = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -1,6 +1,10 @@
 package chain_test
 
 import (
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
 	"strconv"
 	"strings"
 	"testing"
@@ -65,6 +69,243 @@ func TestAsset(t *testing.T) {
 	assert.Equal(t, asset5.String(), "1.123456789012345678 ABCDEFG")
 }
 
+func TestAssetArithmetic(t *testing.T) {
+	a := *chain.A("1.0000 EOS")
+	b := *chain.A("0.2500 EOS")
+	other := *chain.A("1.0000 FOO")
+
+	v, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, v.String(), "1.2500 EOS")
+	v, err = b.Sub(a)
+	assert.NoError(t, err)
+	assert.Equal(t, v.String(), "-0.7500 EOS")
+	v, err = a.Mul(3)
+	assert.NoError(t, err)
+	assert.Equal(t, v.String(), "3.0000 EOS")
+	v = a.Neg()
+	assert.Equal(t, v.String(), "-1.0000 EOS")
+
+	c, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, c, 1)
+	c, err = b.Cmp(a)
+	assert.NoError(t, err)
+	assert.Equal(t, c, -1)
+	c, err = a.Cmp(a)
+	assert.NoError(t, err)
+	assert.Equal(t, c, 0)
+
+	assert.True(t, !a.IsZero())
+	assert.True(t, chain.A("0.0000 EOS").IsZero())
+	assert.True(t, a.IsValid())
+
+	_, err = a.Add(other)
+	assert.True(t, errors.Is(err, chain.ErrSymbolMismatch))
+	_, err = a.Sub(other)
+	assert.True(t, errors.Is(err, chain.ErrSymbolMismatch))
+	_, err = a.Cmp(other)
+	assert.True(t, errors.Is(err, chain.ErrSymbolMismatch))
+	_, err = a.Add(*chain.A("1.000 EOS"))
+	assert.True(t, errors.Is(err, chain.ErrSymbolMismatch))
+
+	max := chain.Asset{Value: chain.MaxAssetAmount, Symbol: a.Symbol}
+	assert.True(t, max.IsValid())
+	_, err = max.Add(*chain.A("0.0001 EOS"))
+	assert.True(t, errors.Is(err, chain.ErrAssetOverflow))
+	_, err = max.Neg().Sub(*chain.A("0.0001 EOS"))
+	assert.True(t, errors.Is(err, chain.ErrAssetOverflow))
+	_, err = max.Mul(2)
+	assert.True(t, errors.Is(err, chain.ErrAssetOverflow))
+	_, err = max.Mul(math.MaxInt64)
+	assert.True(t, errors.Is(err, chain.ErrAssetOverflow))
+	invalid := chain.Asset{Value: math.MaxInt64, Symbol: a.Symbol}
+	assert.True(t, !invalid.IsValid())
+	_, err = invalid.Add(a.Neg())
+	assert.True(t, errors.Is(err, chain.ErrAssetOverflow))
+	assert.True(t, !chain.NewAsset(1, chain.Symbol(19)).IsValid())
+	assert.True(t, !chain.NewAsset(1, chain.Symbol(4)).IsValid())
+}
+
+func TestAssetRounding(t *testing.T) {
+	vectors := []struct {
+		asset  string
+		div    int64
+		mode   big.RoundingMode
+		result string
+	}{
+		{"1.0000 EOS", 3, big.ToZero, "0.3333 EOS"},
+		{"1.0000 EOS", 3, big.AwayFromZero, "0.3334 EOS"},
+		{"-1.0000 EOS", 3, big.ToZero, "-0.3333 EOS"},
+		{"-1.0000 EOS", 3, big.AwayFromZero, "-0.3334 EOS"},
+		{"-1.0000 EOS", 3, big.ToNegativeInf, "-0.3334 EOS"},
+		{"-1.0000 EOS", 3, big.ToPositiveInf, "-0.3333 EOS"},
+		{"1.0000 EOS", 3, big.ToPositiveInf, "0.3334 EOS"},
+		{"2.0000 EOS", 3, big.ToNearestEven, "0.6667 EOS"},
+		{"0.0005 EOS", 2, big.ToNearestEven, "0.0002 EOS"},
+		{"0.0007 EOS", 2, big.ToNearestEven, "0.0004 EOS"},
+		{"0.0005 EOS", 2, big.ToNearestAway, "0.0003 EOS"},
+		{"-0.0005 EOS", 2, big.ToNearestAway, "-0.0003 EOS"},
+		{"-0.0005 EOS", 2, big.ToNearestEven, "-0.0002 EOS"},
+		{"1.0000 EOS", -2, big.ToZero, "-0.5000 EOS"},
+	}
+	for _, v := range vectors {
+		rv, err := chain.A(v.asset).Div(v.div, v.mode)
+		assert.NoError(t, err)
+		assert.Equal(t, rv.String(), v.result)
+	}
+	_, err := chain.A("1.0000 EOS").Div(0, big.ToZero)
+	assert.True(t, errors.Is(err, chain.ErrDivisionByZero))
+
+	// 0.3% fee
+	fee, err := chain.A("10.0001 EOS").MulRat(big.NewRat(3, 1000), big.ToPositiveInf)
+	assert.NoError(t, err)
+	assert.Equal(t, fee.String(), "0.0301 EOS")
+}
+
+func TestAssetRat(t *testing.T) {
+	assert.Equal(t, chain.A("1.0001 EOS").Rat().String(), "10001/10000")
+	assert.Equal(t, chain.A("-2.50 FOO").Rat().String(), "-5/2")
+	assert.Equal(t, chain.A("7 FOO").Rat().String(), "7/1")
+
+	sym := chain.A("1.0000 EOS").Symbol
+	a, err := chain.NewAssetFromRat(big.NewRat(1, 3), sym, big.ToNearestEven)
+	assert.NoError(t, err)
+	assert.Equal(t, a.String(), "0.3333 EOS")
+	a, err = chain.NewAssetFromRat(big.NewRat(-1, 3), sym, big.AwayFromZero)
+	assert.NoError(t, err)
+	assert.Equal(t, a.String(), "-0.3334 EOS")
+	_, err = chain.NewAssetFromRat(big.NewRat(1<<62, 1), sym, big.ToZero)
+	assert.True(t, errors.Is(err, chain.ErrAssetOverflow))
+}
+
+func TestAssetDecimal(t *testing.T) {
+	eos := chain.A("1.0000 EOS").Symbol
+	sym18, err := chain.NewSymbol(18, "ABCDEFG")
+	assert.NoError(t, err)
+	sym0, err := chain.NewSymbol(0, "FOO")
+	assert.NoError(t, err)
+
+	assert.Equal(t, chain.NewAsset(math.MinInt64, sym0).String(), "-9223372036854775808 FOO")
+	assert.Equal(t, chain.NewAsset(math.MinInt64, eos).String(), "-922337203685477.5808 EOS")
+	assert.Equal(t, chain.NewAsset(math.MaxInt64, sym18).String(), "9.223372036854775807 ABCDEFG")
+	assert.Equal(t, chain.NewAsset(-1, sym18).String(), "-0.000000000000000001 ABCDEFG")
+	assert.Equal(t, chain.NewAsset(0, eos).Decimal(), "0.0000")
+	assert.Equal(t, chain.NewAsset(1, eos).FloatValue(), 0.0001)
+	assert.Equal(t, chain.NewAsset(math.MaxInt64, sym18).FloatValue(), 9.223372036854775807)
+
+	vectors := []struct {
+		decimal string
+		mode    big.RoundingMode
+		result  string
+	}{
+		{"1", big.ToZero, "1.0000 EOS"},
+		{"-1.5", big.ToZero, "-1.5000 EOS"},
+		{"+.5", big.ToZero, "0.5000 EOS"},
+		{"1.", big.ToZero, "1.0000 EOS"},
+		{"0.00005", big.ToNearestEven, "0.0000 EOS"},
+		{"0.00005", big.ToNearestAway, "0.0001 EOS"},
+		{"-0.00015", big.ToNearestEven, "-0.0002 EOS"},
+		{"1.23456789", big.ToZero, "1.2345 EOS"},
+		{"1.23456789", big.AwayFromZero, "1.2346 EOS"},
+		{"-1.23456789", big.ToNegativeInf, "-1.2346 EOS"},
+		{"-1.23456789", big.ToPositiveInf, "-1.2345 EOS"},
+	}
+	for _, v := range vectors {
+		a, err := chain.NewAssetFromDecimal(v.decimal, eos, v.mode)
+		assert.NoError(t, err)
+		assert.Equal(t, a.String(), v.result)
+	}
+	for _, s := range []string{"", "-", ".", "1e3", "1/3", "0x10", "1.2.3", " 1", "1,0", "Inf", "NaN"} {
+		_, err := chain.NewAssetFromDecimal(s, eos, big.ToZero)
+		assert.True(t, errors.Is(err, chain.ErrInvalidAssetString))
+	}
+	_, err = chain.NewAssetFromDecimal("461168601842738.7904", eos, big.ToZero)
+	assert.True(t, errors.Is(err, chain.ErrAssetOverflow))
+}
+
+func TestAssetDecimalPrecisions(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for decimals := uint8(0); decimals <= 18; decimals++ {
+		symbol, err := chain.NewSymbol(decimals, "TEST")
+		assert.NoError(t, err)
+		values := []int64{0, 1, -1, chain.MaxAssetAmount, -chain.MaxAssetAmount}
+		for i := 0; i < 200; i++ {
+			v := rng.Int63n(chain.MaxAssetAmount) >> rng.Intn(62)
+			if rng.Intn(2) == 0 {
+				v = -v
+			}
+			values = append(values, v)
+		}
+		for _, v := range values {
+			a := *chain.NewAsset(v, symbol)
+			s := a.Decimal()
+			// exact
+			r, ok := new(big.Rat).SetString(s)
+			assert.True(t, ok)
+			assert.Equal(t, r.Cmp(a.Rat()), 0)
+			// round trips
+			for _, mode := range []big.RoundingMode{big.ToZero, big.AwayFromZero, big.ToNearestEven} {
+				a2, err := chain.NewAssetFromDecimal(s, symbol, mode)
+				assert.NoError(t, err)
+				assert.Equal(t, a2, a)
+			}
+			a3, err := chain.NewAssetFromRat(a.Rat(), symbol, big.ToZero)
+			assert.NoError(t, err)
+			assert.Equal(t, a3, a)
+			a4, err := chain.NewAssetFromString(a.String())
+			assert.NoError(t, err)
+			assert.Equal(t, *a4, a)
+			// float is the nearest float64
+			f, err := strconv.ParseFloat(s, 64)
+			assert.NoError(t, err)
+			assert.Equal(t, a.FloatValue(), f)
+			// one more digit rounds to the symbol precision
+			if decimals > 0 {
+				a5, err := chain.NewAssetFromDecimal(s+"5", symbol, big.ToZero)
+				assert.NoError(t, err)
+				assert.Equal(t, a5, a)
+			}
+		}
+	}
+}
+
+func TestExtendedAssetArithmetic(t *testing.T) {
+	a := chain.ExtendedAsset{Quantity: *chain.A("1.0000 EOS"), Contract: chain.N("eosio.token")}
+	b := chain.ExtendedAsset{Quantity: *chain.A("0.5000 EOS"), Contract: chain.N("eosio.token")}
+	fake := chain.ExtendedAsset{Quantity: *chain.A("0.5000 EOS"), Contract: chain.N("fake.token")}
+
+	v, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, v, chain.ExtendedAsset{Quantity: *chain.A("1.5000 EOS"), Contract: chain.N("eosio.token")})
+	v, err = a.Sub(b)
+	assert.NoError(t, err)
+	assert.Equal(t, v.Quantity.String(), "0.5000 EOS")
+	v, err = a.Mul(2)
+	assert.NoError(t, err)
+	assert.Equal(t, v.Quantity.String(), "2.0000 EOS")
+	v, err = a.Div(3, big.ToZero)
+	assert.NoError(t, err)
+	assert.Equal(t, v.Quantity.String(), "0.3333 EOS")
+	v, err = a.MulRat(big.NewRat(1, 2), big.ToZero)
+	assert.NoError(t, err)
+	assert.Equal(t, v.Quantity.String(), "0.5000 EOS")
+	v = a.Neg()
+	assert.Equal(t, v.Quantity.String(), "-1.0000 EOS")
+	c, err := a.Cmp(b)
+	assert.NoError(t, err)
+	assert.Equal(t, c, 1)
+	assert.True(t, a.IsValid())
+	assert.True(t, !a.IsZero())
+
+	_, err = a.Add(fake)
+	assert.True(t, errors.Is(err, chain.ErrContractMismatch))
+	_, err = a.Sub(fake)
+	assert.True(t, errors.Is(err, chain.ErrContractMismatch))
+	_, err = a.Cmp(fake)
+	assert.True(t, errors.Is(err, chain.ErrContractMismatch))
+}
+
 func FuzzAsset(f *testing.F) {
 	f.Add("1.0000 EOS")
 	f.Add("34.0303 EOS")
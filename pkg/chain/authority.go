@@ -0,0 +1,168 @@
+package chain
+
+import (
+	"bytes"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+// Authority of an account permission.
+type Authority struct {
+	Threshold uint32                  `json:"threshold"`
+	Keys      []KeyWeight             `json:"keys"`
+	Accounts  []PermissionLevelWeight `json:"accounts"`
+	Waits     []WaitWeight            `json:"waits"`
+}
+
+type PermissionLevelWeight struct {
+	Permission PermissionLevel `json:"permission"`
+	Weight     uint16          `json:"weight"`
+}
+
+type WaitWeight struct {
+	WaitSec uint32 `json:"wait_sec"`
+	Weight  uint16 `json:"weight"`
+}
+
+// Weight of given key in the authority, zero if the key is not part of it.
+func (a Authority) KeyWeight(key PublicKey) uint16 {
+	for _, kw := range a.Keys {
+		if kw.Key.Type == key.Type && bytes.Equal(kw.Key.Data, key.Data) {
+			return kw.Weight
+		}
+	}
+	return 0
+}
+
+// Check if given key alone satisfies the authority, account and wait weights are not considered.
+// Keys that are not part of the authority and authorities with a zero threshold never satisfy it.
+func (a Authority) HasPermission(key PublicKey) bool {
+	if a.Threshold == 0 {
+		return false
+	}
+	for _, kw := range a.Keys {
+		if kw.Key.Type == key.Type && bytes.Equal(kw.Key.Data, key.Data) {
+			return uint32(kw.Weight) >= a.Threshold
+		}
+	}
+	return false
+}
+
+// abi.Marshaler conformance
+
+func (a Authority) MarshalABI(e *abi.Encoder) error {
+	err := e.WriteUint32(a.Threshold)
+	if err != nil {
+		return err
+	}
+	err = e.WriteVaruint(uint(len(a.Keys)))
+	if err != nil {
+		return err
+	}
+	for _, kw := range a.Keys {
+		err = kw.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.WriteVaruint(uint(len(a.Accounts)))
+	if err != nil {
+		return err
+	}
+	for _, plw := range a.Accounts {
+		err = plw.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.WriteVaruint(uint(len(a.Waits)))
+	if err != nil {
+		return err
+	}
+	for _, ww := range a.Waits {
+		err = ww.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (plw PermissionLevelWeight) MarshalABI(e *abi.Encoder) error {
+	err := plw.Permission.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return e.WriteUint16(plw.Weight)
+}
+
+func (ww WaitWeight) MarshalABI(e *abi.Encoder) error {
+	err := e.WriteUint32(ww.WaitSec)
+	if err != nil {
+		return err
+	}
+	return e.WriteUint16(ww.Weight)
+}
+
+// abi.Unmarshaler conformance
+
+func (a *Authority) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	a.Threshold, err = d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	a.Keys = make([]KeyWeight, l)
+	for i := range a.Keys {
+		err = a.Keys[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	l, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	a.Accounts = make([]PermissionLevelWeight, l)
+	for i := range a.Accounts {
+		err = a.Accounts[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	l, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	a.Waits = make([]WaitWeight, l)
+	for i := range a.Waits {
+		err = a.Waits[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (plw *PermissionLevelWeight) UnmarshalABI(d *abi.Decoder) error {
+	err := plw.Permission.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	plw.Weight, err = d.ReadUint16()
+	return err
+}
+
+func (ww *WaitWeight) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	ww.WaitSec, err = d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	ww.Weight, err = d.ReadUint16()
+	return err
+}
@@ -0,0 +1,51 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestAuthority(t *testing.T) {
+	_, key1 := testSigningKey("key 1")
+	_, key2 := testSigningKey("key 2")
+	_, key3 := testSigningKey("key 3")
+	auth := chain.Authority{
+		Threshold: 2,
+		Keys: []chain.KeyWeight{
+			{Key: key1, Weight: 2},
+			{Key: key2, Weight: 1},
+		},
+		Accounts: []chain.PermissionLevelWeight{
+			{Permission: chain.PermissionLevel{Actor: chain.N("foo"), Permission: chain.N("active")}, Weight: 1},
+		},
+		Waits: []chain.WaitWeight{{WaitSec: 3600, Weight: 1}},
+	}
+	assert.True(t, auth.HasPermission(key1))
+	assert.True(t, !auth.HasPermission(key2))
+	assert.True(t, !auth.HasPermission(key3))
+	assert.Equal(t, auth.KeyWeight(key2), uint16(1))
+
+	// zero authority and zero threshold never grant permission
+	assert.True(t, !chain.Authority{}.HasPermission(key1))
+	assert.True(t, !chain.Authority{Keys: []chain.KeyWeight{{Key: key1, Weight: 1}}}.HasPermission(key1))
+	assert.True(t, !chain.Authority{Keys: []chain.KeyWeight{{Key: key1, Weight: 1}}}.HasPermission(key3))
+	// keys not listed in the authority never grant permission
+	assert.True(t, !chain.Authority{Threshold: 1, Keys: []chain.KeyWeight{{Key: key1, Weight: 1}}}.HasPermission(key3))
+
+	data := []byte{0x02, 0x00, 0x00, 0x00, 0x02, 0x00}
+	data = append(data, key1.Data...)
+	data = append(data, 0x02, 0x00, 0x00)
+	data = append(data, key2.Data...)
+	data = append(data, 0x01, 0x00)
+	data = append(data, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x28, 0x5d, 0x00, 0x00, 0x00, 0x00, 0xa8, 0xed, 0x32, 0x32, 0x01, 0x00)
+	data = append(data, 0x01, 0x10, 0x0e, 0x00, 0x00, 0x01, 0x00)
+	assert.ABICoding(t, auth, data)
+	assert.JSONCoding(t, chain.Authority{
+		Threshold: 1,
+		Keys:      []chain.KeyWeight{},
+		Accounts:  []chain.PermissionLevelWeight{},
+		Waits:     []chain.WaitWeight{{WaitSec: 10, Weight: 1}},
+	}, `{"threshold": 1, "keys": [], "accounts": [], "waits": [{"wait_sec": 10, "weight": 1}]}`)
+}
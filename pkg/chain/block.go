@@ -0,0 +1,814 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+// Block id, a sha256 digest of the block header with the block number embedded in the first four bytes.
+type BlockID Checksum256
+
+// Known block header and block extension ids.
+const (
+	ProtocolFeatureActivationExtensionID uint16 = 0 // header extension
+	ProducerScheduleChangeExtensionID    uint16 = 1 // header extension
+	AdditionalBlockSignaturesExtensionID uint16 = 2 // block extension
+)
+
+// Header and block extension.
+type Extension struct {
+	Type uint16 `json:"type"`
+	Data Bytes  `json:"data"`
+}
+
+type BlockHeader struct {
+	Timestamp        BlockTimestamp    `json:"timestamp"`
+	Producer         Name              `json:"producer"`
+	Confirmed        uint16            `json:"confirmed"`
+	Previous         BlockID           `json:"previous"`
+	TransactionMroot Checksum256       `json:"transaction_mroot"`
+	ActionMroot      Checksum256       `json:"action_mroot"`
+	ScheduleVersion  uint32            `json:"schedule_version"`
+	NewProducers     *ProducerSchedule `json:"new_producers"` // legacy, replaced by the producer schedule change extension
+	HeaderExtensions []Extension       `json:"header_extensions"`
+}
+
+type SignedBlockHeader struct {
+	BlockHeader
+	ProducerSignature Signature `json:"producer_signature"`
+}
+
+type SignedBlock struct {
+	SignedBlockHeader
+	Transactions    []TransactionReceipt `json:"transactions"`
+	BlockExtensions []Extension          `json:"block_extensions"`
+}
+
+// Legacy producer schedule entry.
+type ProducerKey struct {
+	ProducerName    Name      `json:"producer_name"`
+	BlockSigningKey PublicKey `json:"block_signing_key"`
+}
+
+// Legacy producer schedule, used by the new_producers field of the block header.
+type ProducerSchedule struct {
+	Version   uint32        `json:"version"`
+	Producers []ProducerKey `json:"producers"`
+}
+
+type KeyWeight struct {
+	Key    PublicKey `json:"key"`
+	Weight uint16    `json:"weight"`
+}
+
+// The block_signing_authority_v0 variant, currently the only block signing authority type.
+type BlockSigningAuthority struct {
+	Threshold uint32      `json:"threshold"`
+	Keys      []KeyWeight `json:"keys"`
+}
+
+type ProducerAuthority struct {
+	ProducerName Name                  `json:"producer_name"`
+	Authority    BlockSigningAuthority `json:"authority"`
+}
+
+// Producer schedule with weighted signing authorities, introduced by the WTMSIG_BLOCK_SIGNATURES protocol feature.
+type ProducerAuthoritySchedule struct {
+	Version   uint32              `json:"version"`
+	Producers []ProducerAuthority `json:"producers"`
+}
+
+type TransactionStatus uint8
+
+const (
+	TransactionStatusExecuted TransactionStatus = 0 // succeed, no error handler executed
+	TransactionStatusSoftFail TransactionStatus = 1 // objectively failed (not executed), error handler executed
+	TransactionStatusHardFail TransactionStatus = 2 // objectively failed and error handler objectively failed thus no state change
+	TransactionStatusDelayed  TransactionStatus = 3 // transaction delayed/deferred/scheduled for future execution
+	TransactionStatusExpired  TransactionStatus = 4 // transaction expired and storage space refunded to user
+)
+
+type TransactionReceiptHeader struct {
+	Status        TransactionStatus `json:"status"`
+	CpuUsageUs    uint32            `json:"cpu_usage_us"`
+	NetUsageWords uint              `json:"net_usage_words"`
+}
+
+type TransactionReceipt struct {
+	TransactionReceiptHeader
+	Trx TransactionReceiptTrx `json:"trx"`
+}
+
+// Variant of a transaction id (deferred transactions) or a packed transaction, only one of the fields is set.
+type TransactionReceiptTrx struct {
+	ID     *Checksum256
+	Packed *PackedTransaction
+}
+
+// Block number of the block, taken from the previous block id.
+func (bh BlockHeader) BlockNum() BlockNum {
+	return bh.Previous.Num() + 1
+}
+
+// The sha256 digest of the serialized block header.
+func (bh BlockHeader) Digest() Checksum256 {
+	b := bytes.NewBuffer(nil)
+	err := bh.MarshalABI(NewEncoder(b))
+	if err != nil {
+		panic(err)
+	}
+	return Checksum256Digest(b.Bytes())
+}
+
+// Block id of the block, computed by replacing the first four bytes of the header digest with the block number.
+func (bh BlockHeader) ID() BlockID {
+	id := BlockID(bh.Digest())
+	binary.BigEndian.PutUint32(id[:4], uint32(bh.BlockNum()))
+	return id
+}
+
+// Protocol features activated by the block, nil if none.
+func (bh BlockHeader) ProtocolFeatureActivation() ([]Checksum256, error) {
+	var rv []Checksum256
+	_, err := decodeExtension(bh.HeaderExtensions, ProtocolFeatureActivationExtensionID, &rv)
+	return rv, err
+}
+
+// Proposed producer schedule in the producer schedule change extension, nil if none.
+func (bh BlockHeader) NewProducerSchedule() (*ProducerAuthoritySchedule, error) {
+	var rv ProducerAuthoritySchedule
+	found, err := decodeExtension(bh.HeaderExtensions, ProducerScheduleChangeExtensionID, &rv)
+	if !found || err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// Additional producer signatures of the block, nil if none.
+func (b SignedBlock) AdditionalSignatures() ([]Signature, error) {
+	var rv []Signature
+	_, err := decodeExtension(b.BlockExtensions, AdditionalBlockSignaturesExtensionID, &rv)
+	return rv, err
+}
+
+// Digest of the transaction receipt, the leaves of the transaction_mroot merkle tree.
+func (tr TransactionReceipt) Digest() Checksum256 {
+	b := bytes.NewBuffer(nil)
+	e := NewEncoder(b)
+	err := tr.TransactionReceiptHeader.MarshalABI(e)
+	if err == nil {
+		if tr.Trx.ID != nil {
+			err = tr.Trx.ID.MarshalABI(e)
+		} else if tr.Trx.Packed != nil {
+			digest := tr.Trx.Packed.PackedDigest()
+			err = digest.MarshalABI(e)
+		}
+	}
+	if err != nil {
+		panic(err)
+	}
+	return Checksum256Digest(b.Bytes())
+}
+
+// Block number encoded in the block id.
+func (id BlockID) Num() BlockNum {
+	return BlockNum(binary.BigEndian.Uint32(id[:4]))
+}
+
+// Block prefix used as ref_block_prefix in transaction headers referencing the block, the
+// second 32-bit word of the block id read as little endian.
+func (id BlockID) RefBlockPrefix() uint32 {
+	return binary.LittleEndian.Uint32(id[8:12])
+}
+
+func (id BlockID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (ts TransactionStatus) String() string {
+	switch ts {
+	case TransactionStatusExecuted:
+		return "executed"
+	case TransactionStatusSoftFail:
+		return "soft_fail"
+	case TransactionStatusHardFail:
+		return "hard_fail"
+	case TransactionStatusDelayed:
+		return "delayed"
+	case TransactionStatusExpired:
+		return "expired"
+	default:
+		return "unknown(" + strconv.Itoa(int(ts)) + ")"
+	}
+}
+
+// abi.Marshaler conformance
+
+func (id BlockID) MarshalABI(e *abi.Encoder) error {
+	return e.WriteBytes(id[:])
+}
+
+func (ts TransactionStatus) MarshalABI(e *abi.Encoder) error {
+	return e.WriteUint8(uint8(ts))
+}
+
+func (ext Extension) MarshalABI(e *abi.Encoder) error {
+	err := e.WriteUint16(ext.Type)
+	if err != nil {
+		return err
+	}
+	return ext.Data.MarshalABI(e)
+}
+
+func (bh BlockHeader) MarshalABI(e *abi.Encoder) error {
+	var err error
+	err = bh.Timestamp.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = bh.Producer.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint16(bh.Confirmed)
+	if err != nil {
+		return err
+	}
+	err = bh.Previous.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = bh.TransactionMroot.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = bh.ActionMroot.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint32(bh.ScheduleVersion)
+	if err != nil {
+		return err
+	}
+	err = e.WriteBool(bh.NewProducers != nil)
+	if err != nil {
+		return err
+	}
+	if bh.NewProducers != nil {
+		err = bh.NewProducers.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return marshalExtensions(e, bh.HeaderExtensions)
+}
+
+func (sbh SignedBlockHeader) MarshalABI(e *abi.Encoder) error {
+	err := sbh.BlockHeader.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return sbh.ProducerSignature.MarshalABI(e)
+}
+
+func (b SignedBlock) MarshalABI(e *abi.Encoder) error {
+	var err error
+	err = b.SignedBlockHeader.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	l := uint(len(b.Transactions))
+	err = e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = b.Transactions[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return marshalExtensions(e, b.BlockExtensions)
+}
+
+func (pk ProducerKey) MarshalABI(e *abi.Encoder) error {
+	err := pk.ProducerName.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return pk.BlockSigningKey.MarshalABI(e)
+}
+
+func (ps ProducerSchedule) MarshalABI(e *abi.Encoder) error {
+	var err error
+	err = e.WriteUint32(ps.Version)
+	if err != nil {
+		return err
+	}
+	l := uint(len(ps.Producers))
+	err = e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = ps.Producers[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (kw KeyWeight) MarshalABI(e *abi.Encoder) error {
+	err := kw.Key.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return e.WriteUint16(kw.Weight)
+}
+
+func (bsa BlockSigningAuthority) MarshalABI(e *abi.Encoder) error {
+	var err error
+	err = e.WriteVaruint(0) // variant index of block_signing_authority_v0
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint32(bsa.Threshold)
+	if err != nil {
+		return err
+	}
+	l := uint(len(bsa.Keys))
+	err = e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = bsa.Keys[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (pa ProducerAuthority) MarshalABI(e *abi.Encoder) error {
+	err := pa.ProducerName.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return pa.Authority.MarshalABI(e)
+}
+
+func (pas ProducerAuthoritySchedule) MarshalABI(e *abi.Encoder) error {
+	var err error
+	err = e.WriteUint32(pas.Version)
+	if err != nil {
+		return err
+	}
+	l := uint(len(pas.Producers))
+	err = e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = pas.Producers[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (trh TransactionReceiptHeader) MarshalABI(e *abi.Encoder) error {
+	var err error
+	err = e.WriteUint8(uint8(trh.Status))
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint32(trh.CpuUsageUs)
+	if err != nil {
+		return err
+	}
+	return e.WriteVaruint(trh.NetUsageWords)
+}
+
+func (tr TransactionReceipt) MarshalABI(e *abi.Encoder) error {
+	err := tr.TransactionReceiptHeader.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return tr.Trx.MarshalABI(e)
+}
+
+func (trx TransactionReceiptTrx) MarshalABI(e *abi.Encoder) error {
+	var err error
+	switch {
+	case trx.ID != nil && trx.Packed == nil:
+		err = e.WriteVaruint(0)
+		if err == nil {
+			err = trx.ID.MarshalABI(e)
+		}
+	case trx.Packed != nil && trx.ID == nil:
+		err = e.WriteVaruint(1)
+		if err == nil {
+			err = trx.Packed.MarshalABI(e)
+		}
+	default:
+		err = errors.New("transaction receipt must have exactly one of id or packed transaction")
+	}
+	return err
+}
+
+// abi.Unmarshaler conformance
+
+func (id *BlockID) UnmarshalABI(d *abi.Decoder) error {
+	_, data, err := d.ReadBytes(32)
+	if err == nil {
+		copy((*id)[:], data[:32])
+	}
+	return err
+}
+
+func (ts *TransactionStatus) UnmarshalABI(d *abi.Decoder) error {
+	v, err := d.ReadUint8()
+	*ts = TransactionStatus(v)
+	return err
+}
+
+func (ext *Extension) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	ext.Type, err = d.ReadUint16()
+	if err != nil {
+		return err
+	}
+	return ext.Data.UnmarshalABI(d)
+}
+
+func (bh *BlockHeader) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	err = bh.Timestamp.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = bh.Producer.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	bh.Confirmed, err = d.ReadUint16()
+	if err != nil {
+		return err
+	}
+	err = bh.Previous.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = bh.TransactionMroot.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = bh.ActionMroot.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	bh.ScheduleVersion, err = d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	var exists bool
+	exists, err = d.ReadBool()
+	if err != nil {
+		return err
+	}
+	bh.NewProducers = nil
+	if exists {
+		bh.NewProducers = &ProducerSchedule{}
+		err = bh.NewProducers.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	bh.HeaderExtensions, err = unmarshalExtensions(d)
+	return err
+}
+
+func (sbh *SignedBlockHeader) UnmarshalABI(d *abi.Decoder) error {
+	err := sbh.BlockHeader.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return sbh.ProducerSignature.UnmarshalABI(d)
+}
+
+func (b *SignedBlock) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	err = b.SignedBlockHeader.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	var len uint
+	len, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	b.Transactions = make([]TransactionReceipt, len)
+	for i := 0; i < int(len); i++ {
+		err = b.Transactions[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	b.BlockExtensions, err = unmarshalExtensions(d)
+	return err
+}
+
+func (pk *ProducerKey) UnmarshalABI(d *abi.Decoder) error {
+	err := pk.ProducerName.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return pk.BlockSigningKey.UnmarshalABI(d)
+}
+
+func (ps *ProducerSchedule) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	ps.Version, err = d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	var len uint
+	len, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	ps.Producers = make([]ProducerKey, len)
+	for i := 0; i < int(len); i++ {
+		err = ps.Producers[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (kw *KeyWeight) UnmarshalABI(d *abi.Decoder) error {
+	err := kw.Key.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	kw.Weight, err = d.ReadUint16()
+	return err
+}
+
+func (bsa *BlockSigningAuthority) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	var idx uint
+	idx, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	if idx != 0 {
+		return fmt.Errorf("unknown block signing authority variant %d", idx)
+	}
+	bsa.Threshold, err = d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	var len uint
+	len, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	bsa.Keys = make([]KeyWeight, len)
+	for i := 0; i < int(len); i++ {
+		err = bsa.Keys[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (pa *ProducerAuthority) UnmarshalABI(d *abi.Decoder) error {
+	err := pa.ProducerName.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return pa.Authority.UnmarshalABI(d)
+}
+
+func (pas *ProducerAuthoritySchedule) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	pas.Version, err = d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	var len uint
+	len, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	pas.Producers = make([]ProducerAuthority, len)
+	for i := 0; i < int(len); i++ {
+		err = pas.Producers[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (trh *TransactionReceiptHeader) UnmarshalABI(d *abi.Decoder) error {
+	status, err := d.ReadUint8()
+	if err != nil {
+		return err
+	}
+	trh.Status = TransactionStatus(status)
+	trh.CpuUsageUs, err = d.ReadUint32()
+	if err != nil {
+		return err
+	}
+	trh.NetUsageWords, err = d.ReadVaruint()
+	return err
+}
+
+func (tr *TransactionReceipt) UnmarshalABI(d *abi.Decoder) error {
+	err := tr.TransactionReceiptHeader.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return tr.Trx.UnmarshalABI(d)
+}
+
+func (trx *TransactionReceiptTrx) UnmarshalABI(d *abi.Decoder) error {
+	idx, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	*trx = TransactionReceiptTrx{}
+	switch idx {
+	case 0:
+		trx.ID = &Checksum256{}
+		err = trx.ID.UnmarshalABI(d)
+	case 1:
+		trx.Packed = &PackedTransaction{}
+		err = trx.Packed.UnmarshalABI(d)
+	default:
+		err = fmt.Errorf("invalid transaction receipt variant %d", idx)
+	}
+	return err
+}
+
+// encoding.TextMarshaler conformance
+
+func (id BlockID) MarshalText() (text []byte, err error) {
+	return []byte(id.String()), nil
+}
+
+func (ts TransactionStatus) MarshalText() (text []byte, err error) {
+	return []byte(ts.String()), nil
+}
+
+// encoding.TextUnmarshaler conformance
+
+func (id *BlockID) UnmarshalText(text []byte) error {
+	return (*Checksum256)(id).UnmarshalText(text)
+}
+
+func (ts *TransactionStatus) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "executed":
+		*ts = TransactionStatusExecuted
+	case "soft_fail":
+		*ts = TransactionStatusSoftFail
+	case "hard_fail":
+		*ts = TransactionStatusHardFail
+	case "delayed":
+		*ts = TransactionStatusDelayed
+	case "expired":
+		*ts = TransactionStatusExpired
+	default:
+		return fmt.Errorf("unknown transaction status: %s", text)
+	}
+	return nil
+}
+
+// json.Marshaler conformance
+
+// Encoded as [0, {...}] like nodeos does for static variants.
+func (bsa BlockSigningAuthority) MarshalJSON() ([]byte, error) {
+	type v0 BlockSigningAuthority
+	return json.Marshal([]interface{}{0, v0(bsa)})
+}
+
+// Encoded as the id string or the packed transaction object like nodeos does.
+func (trx TransactionReceiptTrx) MarshalJSON() ([]byte, error) {
+	if trx.ID != nil {
+		return json.Marshal(trx.ID)
+	}
+	return json.Marshal(trx.Packed)
+}
+
+// json.Unmarshaler conformance
+
+// Accepts both [0, {...}] and ["block_signing_authority_v0", {...}].
+func (bsa *BlockSigningAuthority) UnmarshalJSON(b []byte) error {
+	type v0 BlockSigningAuthority
+	var pair []json.RawMessage
+	err := json.Unmarshal(b, &pair)
+	if err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return errors.New("block signing authority must be a variant pair")
+	}
+	if string(pair[0]) != "0" && string(pair[0]) != `"block_signing_authority_v0"` {
+		return fmt.Errorf("unknown block signing authority variant %s", pair[0])
+	}
+	return json.Unmarshal(pair[1], (*v0)(bsa))
+}
+
+// Accepts the nodeos representation as well as ["transaction_id", ...] and ["packed_transaction", {...}].
+func (trx *TransactionReceiptTrx) UnmarshalJSON(b []byte) error {
+	*trx = TransactionReceiptTrx{}
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case string:
+		trx.ID = &Checksum256{}
+		return json.Unmarshal(b, trx.ID)
+	case map[string]interface{}:
+		trx.Packed = &PackedTransaction{}
+		return json.Unmarshal(b, trx.Packed)
+	case []interface{}:
+		var pair []json.RawMessage
+		err = json.Unmarshal(b, &pair)
+		if err != nil {
+			return err
+		}
+		if len(pair) != 2 {
+			return errors.New("transaction receipt trx must be a variant pair")
+		}
+		switch string(pair[0]) {
+		case "0", `"transaction_id"`:
+			trx.ID = &Checksum256{}
+			return json.Unmarshal(pair[1], trx.ID)
+		case "1", `"packed_transaction"`:
+			trx.Packed = &PackedTransaction{}
+			return json.Unmarshal(pair[1], trx.Packed)
+		}
+		return fmt.Errorf("unknown transaction receipt variant %s", pair[0])
+	default:
+		return fmt.Errorf("unexpected transaction receipt trx %v", v)
+	}
+}
+
+// helpers
+
+func marshalExtensions(e *abi.Encoder, exts []Extension) error {
+	l := uint(len(exts))
+	err := e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = exts[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func unmarshalExtensions(d *abi.Decoder) ([]Extension, error) {
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return nil, err
+	}
+	rv := make([]Extension, l)
+	for i := 0; i < int(l); i++ {
+		err = rv[i].UnmarshalABI(d)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rv, nil
+}
+
+func decodeExtension(exts []Extension, id uint16, v interface{}) (found bool, err error) {
+	for _, ext := range exts {
+		if ext.Type == id {
+			return true, NewDecoder(bytes.NewReader(ext.Data)).Decode(v)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,133 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Number of consecutive blocks each producer in the schedule produces.
+const ProducerRepetitions = 12
+
+var (
+	ErrUnscheduledProducer = errors.New("block producer is not scheduled to produce at the block timestamp")
+	ErrWrongSigningKey     = errors.New("block is not signed by the authority of the scheduled producer")
+)
+
+// Values from the block header state of the block that are needed to compute the digest signed by the producer.
+type BlockSigningDigestInputs struct {
+	// Root of the incremental merkle tree of all block ids up to and including the previous block.
+	BlockrootMerkleRoot Checksum256
+	// Hash of the pending producer schedule, see ProducerSchedule.Hash and ProducerAuthoritySchedule.Hash.
+	PendingScheduleHash Checksum256
+}
+
+// The digest signed by the block producer.
+func (bh BlockHeader) SigDigest(inputs BlockSigningDigestInputs) Checksum256 {
+	headerDigest := bh.Digest()
+	headerBmroot := digestPair(headerDigest, inputs.BlockrootMerkleRoot)
+	return digestPair(headerBmroot, inputs.PendingScheduleHash)
+}
+
+// Verify that the block is signed by the producer scheduled at the block timestamp and that the
+// recovered keys satisfy its block signing authority. The additional signatures are found in
+// the additional block signatures extension of the signed block, see SignedBlock.AdditionalSignatures.
+func (sbh SignedBlockHeader) VerifySignature(inputs BlockSigningDigestInputs, schedule ProducerAuthoritySchedule, additional ...Signature) error {
+	producer := schedule.ScheduledProducer(sbh.Timestamp)
+	if producer == nil || producer.ProducerName != sbh.Producer {
+		return ErrUnscheduledProducer
+	}
+	if 1+len(additional) > len(producer.Authority.Keys) {
+		return fmt.Errorf("number of block signatures (%d) exceeds number of keys in block signing authority (%d)", 1+len(additional), len(producer.Authority.Keys))
+	}
+	digest := sbh.SigDigest(inputs)
+	keys := make([]PublicKey, 0, 1+len(additional))
+	for _, sig := range append([]Signature{sbh.ProducerSignature}, additional...) {
+		key, err := sig.RecoverDigest(digest)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if k.Type == key.Type && bytes.Equal(k.Data, key.Data) {
+				return errors.New("block signed by same key twice")
+			}
+		}
+		keys = append(keys, *key)
+	}
+	satisfied, relevant := producer.Authority.KeysSatisfy(keys)
+	if !satisfied || relevant != len(keys) {
+		return ErrWrongSigningKey
+	}
+	return nil
+}
+
+// Check if given keys satisfy the authority, also returns the number of keys that are part of the authority.
+func (bsa BlockSigningAuthority) KeysSatisfy(keys []PublicKey) (satisfied bool, relevant int) {
+	var weight uint32
+	for _, key := range keys {
+		for _, kw := range bsa.Keys {
+			if kw.Key.Type == key.Type && bytes.Equal(kw.Key.Data, key.Data) {
+				weight += uint32(kw.Weight)
+				relevant++
+				break
+			}
+		}
+	}
+	return weight >= bsa.Threshold, relevant
+}
+
+// Producer scheduled to produce the block at given timestamp, nil if the schedule is empty.
+func (pas ProducerAuthoritySchedule) ScheduledProducer(t BlockTimestamp) *ProducerAuthority {
+	if len(pas.Producers) == 0 {
+		return nil
+	}
+	idx := uint64(t) % uint64(len(pas.Producers)*ProducerRepetitions) / ProducerRepetitions
+	return &pas.Producers[idx]
+}
+
+// The sha256 digest of the serialized schedule.
+func (pas ProducerAuthoritySchedule) Hash() Checksum256 {
+	b := bytes.NewBuffer(nil)
+	err := pas.MarshalABI(NewEncoder(b))
+	if err != nil {
+		panic(err)
+	}
+	return Checksum256Digest(b.Bytes())
+}
+
+// The sha256 digest of the serialized schedule.
+func (ps ProducerSchedule) Hash() Checksum256 {
+	b := bytes.NewBuffer(nil)
+	err := ps.MarshalABI(NewEncoder(b))
+	if err != nil {
+		panic(err)
+	}
+	return Checksum256Digest(b.Bytes())
+}
+
+// Convert a legacy schedule to one with single key authorities.
+func (ps ProducerSchedule) AuthoritySchedule() ProducerAuthoritySchedule {
+	rv := ProducerAuthoritySchedule{
+		Version:   ps.Version,
+		Producers: make([]ProducerAuthority, len(ps.Producers)),
+	}
+	for i, p := range ps.Producers {
+		rv.Producers[i] = ProducerAuthority{
+			ProducerName: p.ProducerName,
+			Authority: BlockSigningAuthority{
+				Threshold: 1,
+				Keys:      []KeyWeight{{Key: p.BlockSigningKey, Weight: 1}},
+			},
+		}
+	}
+	return rv
+}
+
+// helpers
+
+func digestPair(a, b Checksum256) Checksum256 {
+	var buf [64]byte
+	copy(buf[:32], a[:])
+	copy(buf[32:], b[:])
+	return Checksum256Digest(buf[:])
+}
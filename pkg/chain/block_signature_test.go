@@ -0,0 +1,113 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func testSigningKey(seed string) (*secp256k1.PrivateKey, chain.PublicKey) {
+	d := chain.Checksum256Digest([]byte(seed))
+	priv := secp256k1.PrivKeyFromBytes(d[:])
+	return priv, *chain.NewPublicKey(chain.K1, priv.PubKey().SerializeCompressed())
+}
+
+func testSign(priv *secp256k1.PrivateKey, digest chain.Checksum256) chain.Signature {
+	return *chain.NewSignature(chain.K1, ecdsa.SignCompact(priv, digest[:], true))
+}
+
+func TestSignatureRecover(t *testing.T) {
+	priv, pub := testSigningKey("alice")
+	digest := chain.Checksum256Digest([]byte("hello world"))
+	sig := testSign(priv, digest)
+	recovered, err := sig.RecoverDigest(digest)
+	assert.NoError(t, err)
+	assert.Equal(t, *recovered, pub)
+
+	recovered, err = sig.RecoverDigest(chain.Checksum256Digest([]byte("goodbye world")))
+	assert.True(t, err != nil || recovered.String() != pub.String())
+
+	_, err = chain.NewSignature(chain.WA, sig.Data).RecoverDigest(digest)
+	assert.NotNil(t, err)
+}
+
+func TestBlockSignature(t *testing.T) {
+	var prev chain.BlockID
+	copy(prev[:], mustDecodeHex("000004d2abababababababababababababababababababababababababababab"))
+	header := chain.BlockHeader{
+		Timestamp:        chain.BlockTimestamp(1145145361),
+		Producer:         chain.N("teamgreymass"),
+		Previous:         prev,
+		TransactionMroot: chain.Checksum256Digest([]byte("trx")),
+		ActionMroot:      chain.Checksum256Digest([]byte("act")),
+		ScheduleVersion:  1,
+		HeaderExtensions: []chain.Extension{
+			{
+				Type: chain.ProducerScheduleChangeExtensionID,
+				Data: mustDecodeHex("020000000180b1915e5d268dca000100000001000223e0ae8aacb41b06dc74af1a56b2eb69133f07f7f75bd1d5e53316bff195edf40100"),
+			},
+		},
+	}
+	schedule, err := header.NewProducerSchedule()
+	assert.NoError(t, err)
+	assert.Equal(t, schedule.Hash().String(), "79758d7945244e6fa5e65a5d763010391c155ba9554975895e92f77c29bc7df4")
+
+	inputs := chain.BlockSigningDigestInputs{
+		BlockrootMerkleRoot: chain.Checksum256Digest([]byte("blockroot")),
+		PendingScheduleHash: schedule.Hash(),
+	}
+	digest := header.SigDigest(inputs)
+	assert.Equal(t, digest.String(), "0804a8e7739a5c0c33e24c54a37563653fdbc8ef3e8d29ba270e257080764541")
+
+	priv1, pub1 := testSigningKey("producer key 1")
+	priv2, pub2 := testSigningKey("producer key 2")
+	priv3, _ := testSigningKey("not a producer key")
+
+	active := chain.ProducerSchedule{
+		Version: 1,
+		Producers: []chain.ProducerKey{
+			{ProducerName: chain.N("teamgreymass"), BlockSigningKey: pub1},
+			{ProducerName: chain.N("alice"), BlockSigningKey: pub2},
+		},
+	}.AuthoritySchedule()
+	assert.Equal(t, active.ScheduledProducer(header.Timestamp).ProducerName, chain.N("teamgreymass"))
+	assert.Equal(t, active.ScheduledProducer(header.Timestamp+12).ProducerName, chain.N("alice"))
+
+	signed := chain.SignedBlockHeader{BlockHeader: header, ProducerSignature: testSign(priv1, digest)}
+	assert.NoError(t, signed.VerifySignature(inputs, active))
+
+	// wrong digest inputs
+	err = signed.VerifySignature(chain.BlockSigningDigestInputs{}, active)
+	assert.Equal(t, err, chain.ErrWrongSigningKey)
+
+	// signed by another key
+	signed.ProducerSignature = testSign(priv3, digest)
+	err = signed.VerifySignature(inputs, active)
+	assert.Equal(t, err, chain.ErrWrongSigningKey)
+
+	// producer not scheduled at this time
+	signed.ProducerSignature = testSign(priv1, digest)
+	signed.Timestamp += 12
+	err = signed.VerifySignature(inputs, active)
+	assert.Equal(t, err, chain.ErrUnscheduledProducer)
+	signed.Timestamp -= 12
+
+	// weighted multi key authority
+	active.Producers[0].Authority = chain.BlockSigningAuthority{
+		Threshold: 2,
+		Keys: []chain.KeyWeight{
+			{Key: pub1, Weight: 1},
+			{Key: pub2, Weight: 1},
+		},
+	}
+	err = signed.VerifySignature(inputs, active)
+	assert.Equal(t, err, chain.ErrWrongSigningKey)
+	assert.NoError(t, signed.VerifySignature(inputs, active, testSign(priv2, digest)))
+	err = signed.VerifySignature(inputs, active, testSign(priv1, digest))
+	assert.NotNil(t, err)
+	err = signed.VerifySignature(inputs, active, testSign(priv2, digest), testSign(priv3, digest))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,267 @@
+package chain_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestBlockID(t *testing.T) {
+	var id chain.BlockID
+	assert.NoError(t, id.UnmarshalText([]byte("0b9cd4a7d3d0c7c6ba1d4f2aa3b8fd6d8a73b1e3ab54dd01d4d54f7e9a04ad30")))
+	assert.Equal(t, id.Num(), chain.BlockNum(194827431))
+	assert.Equal(t, id.RefBlockPrefix(), uint32(709828026))
+	assert.JSONCoding(t, id, `"0b9cd4a7d3d0c7c6ba1d4f2aa3b8fd6d8a73b1e3ab54dd01d4d54f7e9a04ad30"`)
+	assert.ABICoding(t, id, mustDecodeHex("0b9cd4a7d3d0c7c6ba1d4f2aa3b8fd6d8a73b1e3ab54dd01d4d54f7e9a04ad30"))
+}
+
+func TestBlockHeader(t *testing.T) {
+	key := chain.NewPublicKey(chain.K1, mustDecodeHex("0223e0ae8aacb41b06dc74af1a56b2eb69133f07f7f75bd1d5e53316bff195edf4"))
+	var prev chain.BlockID
+	copy(prev[:], mustDecodeHex("000004d2abababababababababababababababababababababababababababab"))
+	header := chain.BlockHeader{
+		Timestamp:        chain.BlockTimestamp(1145145361),
+		Producer:         chain.N("teamgreymass"),
+		Confirmed:        0,
+		Previous:         prev,
+		TransactionMroot: chain.Checksum256Digest([]byte("trx")),
+		ActionMroot:      chain.Checksum256Digest([]byte("act")),
+		ScheduleVersion:  1,
+		HeaderExtensions: []chain.Extension{
+			{
+				Type: chain.ProducerScheduleChangeExtensionID,
+				Data: mustDecodeHex("020000000180b1915e5d268dca000100000001000223e0ae8aacb41b06dc74af1a56b2eb69133f07f7f75bd1d5e53316bff195edf40100"),
+			},
+		},
+	}
+	assert.ABICoding(t, header, mustDecodeHex(
+		"1188414480b1915e5d268dca0000000004d2abababababababababababababababababababababababababababab6a5cd181ce912ebb"+
+			"4c9f883d5044aa710511f87ce98e15d447006695531bb01bf83f07be16e270186d35b876916c461995ebc3af5b1798f898c33285a1"+
+			"847dbc010000000001010037020000000180b1915e5d268dca000100000001000223e0ae8aacb41b06dc74af1a56b2eb69133f07f7"+
+			"f75bd1d5e53316bff195edf40100",
+	))
+	assert.Equal(t, header.BlockNum(), chain.BlockNum(1235))
+	assert.Equal(t, header.ID().String(), "000004d329f8dfbeadd967ea75ffc1f990e8a804c59dcd01a1647b0485d9a7b2")
+	assert.Equal(t, header.ID().Num(), header.BlockNum())
+
+	schedule, err := header.NewProducerSchedule()
+	assert.NoError(t, err)
+	assert.Equal(t, *schedule, chain.ProducerAuthoritySchedule{
+		Version: 2,
+		Producers: []chain.ProducerAuthority{
+			{
+				ProducerName: chain.N("teamgreymass"),
+				Authority: chain.BlockSigningAuthority{
+					Threshold: 1,
+					Keys:      []chain.KeyWeight{{Key: *key, Weight: 1}},
+				},
+			},
+		},
+	})
+	assert.JSONCoding(t, *schedule, `{
+		"version": 2,
+		"producers": [
+			{
+				"producer_name": "teamgreymass",
+				"authority": [0, {"threshold": 1, "keys": [{"key": "PUB_K1_5AHoNnWetuDhKWSDx3WUf8W7Dg5xjHCMc4yHmmSiaJCFvvAgnB", "weight": 1}]}]
+			}
+		]
+	}`)
+	features, err := header.ProtocolFeatureActivation()
+	assert.NoError(t, err)
+	assert.Equal(t, len(features), 0)
+
+	assert.JSONCoding(t, header, `{
+		"timestamp": "2018-02-21T23:58:00.500",
+		"producer": "teamgreymass",
+		"confirmed": 0,
+		"previous": "000004d2abababababababababababababababababababababababababababab",
+		"transaction_mroot": "6a5cd181ce912ebb4c9f883d5044aa710511f87ce98e15d447006695531bb01b",
+		"action_mroot": "f83f07be16e270186d35b876916c461995ebc3af5b1798f898c33285a1847dbc",
+		"schedule_version": 1,
+		"new_producers": null,
+		"header_extensions": [
+			{"type": 1, "data": "020000000180b1915e5d268dca000100000001000223e0ae8aacb41b06dc74af1a56b2eb69133f07f7f75bd1d5e53316bff195edf40100"}
+		]
+	}`)
+
+	legacy := chain.BlockHeader{
+		Previous: prev,
+		NewProducers: &chain.ProducerSchedule{
+			Version:   3,
+			Producers: []chain.ProducerKey{{ProducerName: chain.N("teamgreymass"), BlockSigningKey: *key}},
+		},
+		HeaderExtensions: []chain.Extension{},
+	}
+	var decoded chain.BlockHeader
+	b := bytes.NewBuffer(nil)
+	assert.NoError(t, chain.NewEncoder(b).Encode(legacy))
+	assert.NoError(t, chain.NewDecoder(b).Decode(&decoded))
+	assert.Equal(t, decoded, legacy)
+	schedule, err = legacy.NewProducerSchedule()
+	assert.NoError(t, err)
+	assert.True(t, schedule == nil)
+}
+
+func TestSignedBlock(t *testing.T) {
+	sig := chain.NewSignature(chain.K1, mustDecodeHex(
+		"205150a67288c3b393fdba9061b05019c54b12bdac295fc83bebad7cd63c7bb67d5cb8cc220564da006240a58419f64d06a5c6e1fc62889816a6c3dfdd231ed389",
+	))
+	tx := chain.Transaction{
+		TransactionHeader:  chain.TransactionHeader{Expiration: 1234567890, RefBlockNum: 11, RefBlockPrefix: 22},
+		ContextFreeActions: []chain.Action{},
+		Actions: []chain.Action{
+			{
+				Account:       chain.N("eosio.token"),
+				Name:          chain.N("transfer"),
+				Authorization: []chain.PermissionLevel{{Actor: chain.N("alice"), Permission: chain.N("active")}},
+				Data:          chain.Bytes{0xbe, 0xef},
+			},
+		},
+		Extensions: []chain.TransactionExtension{},
+	}
+	packed := bytes.NewBuffer(nil)
+	assert.NoError(t, chain.NewEncoder(packed).Encode(tx))
+	compressed := bytes.NewBuffer(nil)
+	zw := zlib.NewWriter(compressed)
+	zw.Write(packed.Bytes())
+	zw.Close()
+
+	deferredID := chain.Checksum256Digest([]byte("deferred"))
+	block := chain.SignedBlock{
+		SignedBlockHeader: chain.SignedBlockHeader{
+			BlockHeader: chain.BlockHeader{
+				Timestamp:        chain.BlockTimestamp(1145145362),
+				Producer:         chain.N("teamgreymass"),
+				HeaderExtensions: []chain.Extension{},
+			},
+			ProducerSignature: *sig,
+		},
+		Transactions: []chain.TransactionReceipt{
+			{
+				TransactionReceiptHeader: chain.TransactionReceiptHeader{
+					Status:        chain.TransactionStatusExecuted,
+					CpuUsageUs:    100,
+					NetUsageWords: 16,
+				},
+				Trx: chain.TransactionReceiptTrx{Packed: &chain.PackedTransaction{
+					Signatures:            []chain.Signature{*sig},
+					Compression:           chain.CompressionNone,
+					PackedContextFreeData: chain.Bytes{},
+					PackedTrx:             packed.Bytes(),
+				}},
+			},
+			{
+				TransactionReceiptHeader: chain.TransactionReceiptHeader{
+					Status:        chain.TransactionStatusSoftFail,
+					CpuUsageUs:    200,
+					NetUsageWords: 0,
+				},
+				Trx: chain.TransactionReceiptTrx{ID: &deferredID},
+			},
+			{
+				TransactionReceiptHeader: chain.TransactionReceiptHeader{
+					Status:        chain.TransactionStatusExecuted,
+					CpuUsageUs:    300,
+					NetUsageWords: 12,
+				},
+				Trx: chain.TransactionReceiptTrx{Packed: &chain.PackedTransaction{
+					Signatures:            []chain.Signature{},
+					Compression:           chain.CompressionZlib,
+					PackedContextFreeData: chain.Bytes{},
+					PackedTrx:             compressed.Bytes(),
+				}},
+			},
+		},
+		BlockExtensions: []chain.Extension{
+			{Type: chain.AdditionalBlockSignaturesExtensionID, Data: append([]byte{0x01, 0x00}, sig.Data...)},
+		},
+	}
+
+	b := bytes.NewBuffer(nil)
+	assert.NoError(t, chain.NewEncoder(b).Encode(block))
+	encoded := b.Bytes()
+	assert.ABICoding(t, block, encoded)
+
+	var header chain.SignedBlockHeader
+	assert.NoError(t, chain.NewDecoder(bytes.NewReader(encoded)).Decode(&header))
+	assert.Equal(t, header, block.SignedBlockHeader)
+	assert.Equal(t, header.ID(), block.ID())
+
+	for _, i := range []int{0, 2} {
+		decoded, err := block.Transactions[i].Trx.Packed.Transaction()
+		assert.NoError(t, err)
+		assert.Equal(t, *decoded, tx)
+		id, err := block.Transactions[i].Trx.Packed.ID()
+		assert.NoError(t, err)
+		assert.Equal(t, id, tx.ID())
+	}
+
+	sigs, err := block.AdditionalSignatures()
+	assert.NoError(t, err)
+	assert.Equal(t, sigs, []chain.Signature{*sig})
+
+	assert.JSONCoding(t, block, `{
+		"timestamp": "2018-02-21T23:58:01.000",
+		"producer": "teamgreymass",
+		"confirmed": 0,
+		"previous": "0000000000000000000000000000000000000000000000000000000000000000",
+		"transaction_mroot": "0000000000000000000000000000000000000000000000000000000000000000",
+		"action_mroot": "0000000000000000000000000000000000000000000000000000000000000000",
+		"schedule_version": 0,
+		"new_producers": null,
+		"header_extensions": [],
+		"producer_signature": "SIG_K1_KfPLgpw35iX8nfDzhbcmSBCr7nEGNEYXgmmempQspDJYBCKuAEs5rm3s4ZuLJY428Ca8ZhvR2Dkwu118y3NAoMDxhicRj9",
+		"transactions": [
+			{
+				"status": "executed",
+				"cpu_usage_us": 100,
+				"net_usage_words": 16,
+				"trx": {
+					"signatures": ["SIG_K1_KfPLgpw35iX8nfDzhbcmSBCr7nEGNEYXgmmempQspDJYBCKuAEs5rm3s4ZuLJY428Ca8ZhvR2Dkwu118y3NAoMDxhicRj9"],
+					"compression": "none",
+					"packed_context_free_data": "",
+					"packed_trx": "`+hex.EncodeToString(packed.Bytes())+`"
+				}
+			},
+			{
+				"status": "soft_fail",
+				"cpu_usage_us": 200,
+				"net_usage_words": 0,
+				"trx": "`+deferredID.String()+`"
+			},
+			{
+				"status": "executed",
+				"cpu_usage_us": 300,
+				"net_usage_words": 12,
+				"trx": {
+					"signatures": [],
+					"compression": "zlib",
+					"packed_context_free_data": "",
+					"packed_trx": "`+hex.EncodeToString(compressed.Bytes())+`"
+				}
+			}
+		],
+		"block_extensions": [
+			{"type": 2, "data": "0100`+hex.EncodeToString(sig.Data)+`"}
+		]
+	}`)
+
+	var trx chain.TransactionReceiptTrx
+	assert.NoError(t, trx.UnmarshalJSON([]byte(`["transaction_id", "`+deferredID.String()+`"]`)))
+	assert.Equal(t, *trx.ID, deferredID)
+	assert.NoError(t, trx.UnmarshalJSON([]byte(`[1, {"signatures":[],"compression":"none","packed_context_free_data":"","packed_trx":""}]`)))
+	assert.True(t, trx.ID == nil && trx.Packed != nil)
+}
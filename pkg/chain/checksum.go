@@ -60,6 +60,31 @@ func (c Checksum512) String() string {
 	return hex.EncodeToString(c[:])
 }
 
+// Split the checksum into two 128-bit words the way contracts do when storing it in an idx256 index,
+// each word holding 16 bytes in big-endian order.
+func (c256 Checksum256) Words() [2]Uint128 {
+	var rv [2]Uint128
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 8; j++ {
+			rv[i].Hi = rv[i].Hi<<8 | uint64(c256[16*i+j])
+			rv[i].Lo = rv[i].Lo<<8 | uint64(c256[16*i+8+j])
+		}
+	}
+	return rv
+}
+
+// Create a checksum from two 128-bit words, the inverse of Checksum256.Words.
+func NewChecksum256FromWords(words [2]Uint128) Checksum256 {
+	var rv Checksum256
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 8; j++ {
+			rv[16*i+j] = byte(words[i].Hi >> (56 - 8*j))
+			rv[16*i+8+j] = byte(words[i].Lo >> (56 - 8*j))
+		}
+	}
+	return rv
+}
+
 // abi.Marshaler conformance
 
 func (c160 *Checksum160) MarshalABI(e *abi.Encoder) error {
@@ -31,3 +31,12 @@ func TestChecksum(t *testing.T) {
 	})
 	assert.JSONCoding(t, c512, `"309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f"`)
 }
+
+func TestChecksum256Words(t *testing.T) {
+	c := chain.Checksum256Digest([]byte("hello"))
+	assert.Equal(t, c.String(), "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824")
+	w := c.Words()
+	assert.Equal(t, w[0], chain.Uint128{Hi: 0x2cf24dba5fb0a30e, Lo: 0x26e83b2ac5b9e29e})
+	assert.Equal(t, w[1], chain.Uint128{Hi: 0x1b161e5c1fa7425e, Lo: 0x73043362938b9824})
+	assert.Equal(t, chain.NewChecksum256FromWords(w), c)
+}
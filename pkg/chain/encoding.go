@@ -13,12 +13,30 @@ import (
 func chainDecoder(dec *abi.Decoder, v interface{}) (done bool, err error) {
 	done = true
 	switch v := v.(type) {
+	case *AbiKvSecondaryIndices:
+		err = v.UnmarshalABI(dec)
+	case *AbiKvTable:
+		err = v.UnmarshalABI(dec)
+	case *AbiKvTables:
+		err = v.UnmarshalABI(dec)
+	case *AccountDelta:
+		err = v.UnmarshalABI(dec)
 	case *Action:
 		err = v.UnmarshalABI(dec)
+	case *ActionReceipt:
+		err = v.UnmarshalABI(dec)
 	case *Asset:
 		err = v.UnmarshalABI(dec)
+	case *AuthSequence:
+		err = v.UnmarshalABI(dec)
+	case *Authority:
+		err = v.UnmarshalABI(dec)
 	case *Blob:
 		err = v.UnmarshalABI(dec)
+	case *BlockHeader:
+		err = v.UnmarshalABI(dec)
+	case *BlockID:
+		err = v.UnmarshalABI(dec)
 	case *BlockNum:
 		err = v.UnmarshalABI(dec)
 	case *BlockTimestamp:
@@ -31,18 +49,46 @@ func chainDecoder(dec *abi.Decoder, v interface{}) (done bool, err error) {
 		err = v.UnmarshalABI(dec)
 	case *Checksum512:
 		err = v.UnmarshalABI(dec)
+	case *DeferredTransactionGenerationContext:
+		err = v.UnmarshalABI(dec)
+	case *Exception:
+		err = v.UnmarshalABI(dec)
+	case *ExtendedSymbol:
+		err = v.UnmarshalABI(dec)
+	case *Extension:
+		err = v.UnmarshalABI(dec)
 	case *Float128:
 		err = v.UnmarshalABI(dec)
+	case *Float128Number:
+		err = v.UnmarshalABI(dec)
 	case *Int128:
 		err = v.UnmarshalABI(dec)
 	case *Name:
 		err = v.UnmarshalABI(dec)
+	case *PackedTransaction:
+		err = v.UnmarshalABI(dec)
 	case *PermissionLevel:
 		err = v.UnmarshalABI(dec)
+	case *PermissionLevelWeight:
+		err = v.UnmarshalABI(dec)
+	case *PrivateKey:
+		err = v.UnmarshalABI(dec)
 	case *PublicKey:
 		err = v.UnmarshalABI(dec)
+	case *ResourcePayer:
+		err = v.UnmarshalABI(dec)
+	case *SealedMessage:
+		err = v.UnmarshalABI(dec)
 	case *Signature:
 		err = v.UnmarshalABI(dec)
+	case *SignedBlock:
+		err = v.UnmarshalABI(dec)
+	case *SignedBlockHeader:
+		err = v.UnmarshalABI(dec)
+	case *SignedTransaction:
+		err = v.UnmarshalABI(dec)
+	case *StrictName:
+		err = v.UnmarshalABI(dec)
 	case *Symbol:
 		err = v.UnmarshalABI(dec)
 	case *SymbolCode:
@@ -57,10 +103,16 @@ func chainDecoder(dec *abi.Decoder, v interface{}) (done bool, err error) {
 		err = v.UnmarshalABI(dec)
 	case *TransactionHeader:
 		err = v.UnmarshalABI(dec)
+	case *TransactionReceipt:
+		err = v.UnmarshalABI(dec)
+	case *TransactionStatus:
+		err = v.UnmarshalABI(dec)
 	case *Uint128:
 		err = v.UnmarshalABI(dec)
 	case *Uint64:
 		err = v.UnmarshalABI(dec)
+	case *WaitWeight:
+		err = v.UnmarshalABI(dec)
 	default:
 		done = false
 	}
@@ -70,12 +122,30 @@ func chainDecoder(dec *abi.Decoder, v interface{}) (done bool, err error) {
 func chainEncoder(enc *abi.Encoder, v interface{}) (done bool, err error) {
 	done = true
 	switch v := v.(type) {
+	case AbiKvSecondaryIndices:
+		err = v.MarshalABI(enc)
+	case AbiKvTable:
+		err = v.MarshalABI(enc)
+	case AbiKvTables:
+		err = v.MarshalABI(enc)
+	case AccountDelta:
+		err = v.MarshalABI(enc)
 	case Action:
 		err = v.MarshalABI(enc)
+	case ActionReceipt:
+		err = v.MarshalABI(enc)
 	case Asset:
 		err = v.MarshalABI(enc)
+	case AuthSequence:
+		err = v.MarshalABI(enc)
+	case Authority:
+		err = v.MarshalABI(enc)
 	case Blob:
 		err = v.MarshalABI(enc)
+	case BlockHeader:
+		err = v.MarshalABI(enc)
+	case BlockID:
+		err = v.MarshalABI(enc)
 	case BlockNum:
 		err = v.MarshalABI(enc)
 	case BlockTimestamp:
@@ -88,18 +158,46 @@ func chainEncoder(enc *abi.Encoder, v interface{}) (done bool, err error) {
 		err = v.MarshalABI(enc)
 	case Checksum512:
 		err = v.MarshalABI(enc)
+	case DeferredTransactionGenerationContext:
+		err = v.MarshalABI(enc)
+	case Exception:
+		err = v.MarshalABI(enc)
+	case ExtendedSymbol:
+		err = v.MarshalABI(enc)
+	case Extension:
+		err = v.MarshalABI(enc)
 	case Float128:
 		err = v.MarshalABI(enc)
+	case Float128Number:
+		err = v.MarshalABI(enc)
 	case Int128:
 		err = v.MarshalABI(enc)
 	case Name:
 		err = v.MarshalABI(enc)
+	case PackedTransaction:
+		err = v.MarshalABI(enc)
 	case PermissionLevel:
 		err = v.MarshalABI(enc)
+	case PermissionLevelWeight:
+		err = v.MarshalABI(enc)
+	case PrivateKey:
+		err = v.MarshalABI(enc)
 	case PublicKey:
 		err = v.MarshalABI(enc)
+	case ResourcePayer:
+		err = v.MarshalABI(enc)
+	case SealedMessage:
+		err = v.MarshalABI(enc)
 	case Signature:
 		err = v.MarshalABI(enc)
+	case SignedBlock:
+		err = v.MarshalABI(enc)
+	case SignedBlockHeader:
+		err = v.MarshalABI(enc)
+	case SignedTransaction:
+		err = v.MarshalABI(enc)
+	case StrictName:
+		err = v.MarshalABI(enc)
 	case Symbol:
 		err = v.MarshalABI(enc)
 	case SymbolCode:
@@ -114,10 +212,16 @@ func chainEncoder(enc *abi.Encoder, v interface{}) (done bool, err error) {
 		err = v.MarshalABI(enc)
 	case TransactionHeader:
 		err = v.MarshalABI(enc)
+	case TransactionReceipt:
+		err = v.MarshalABI(enc)
+	case TransactionStatus:
+		err = v.MarshalABI(enc)
 	case Uint128:
 		err = v.MarshalABI(enc)
 	case Uint64:
 		err = v.MarshalABI(enc)
+	case WaitWeight:
+		err = v.MarshalABI(enc)
 	default:
 		done = false
 	}
@@ -0,0 +1,401 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+var ErrInvalidFloat128 = errors.New("invalid float128")
+
+// IEEE 754 binary128 floating point number, used for the long double type of contracts.
+type Float128 struct {
+	Data [16]byte // little-endian bytes as serialized
+}
+
+// Float128 that is marshaled to JSON as a number instead of a hex string of its little-endian bytes,
+// infinities and NaN are marshaled as the strings "+Inf", "-Inf" and "NaN". The binary encoding is the
+// same as Float128.
+type Float128Number Float128
+
+// binary128 layout: 1 sign bit, 15 exponent bits and 112 mantissa bits
+const (
+	float128Bias       = 16383
+	float128MaxExp     = 0x7fff
+	float128MantBits   = 112
+	float128MinSubExp  = 1 - float128Bias - float128MantBits // exponent of the smallest subnormal
+	float128HiMantMask = 1<<(float128MantBits-64) - 1
+)
+
+// Create a new Float128 from a float64, the conversion is exact.
+func NewFloat128FromFloat64(v float64) Float128 {
+	b := math.Float64bits(v)
+	sign := b >> 63
+	exp := (b >> 52) & 0x7ff
+	mant := b & (1<<52 - 1)
+	switch {
+	case exp == 0x7ff:
+		exp = float128MaxExp // keeps the nan payload
+	case exp == 0 && mant == 0:
+	case exp == 0:
+		// subnormal double, normalized in binary128
+		n := bits.Len64(mant)
+		exp = uint64(float128Bias - 1022 - (53 - n))
+		mant = (mant << (53 - n)) & (1<<52 - 1)
+	default:
+		exp = exp - 1023 + float128Bias
+	}
+	return float128FromBits(sign<<63|exp<<48|mant>>4, mant<<60)
+}
+
+// Create a new Float128 from a big.Float, rounding to the nearest binary128 value with ties to even.
+// Values too large to be represented become infinities.
+func NewFloat128FromBigFloat(f *big.Float) Float128 {
+	neg := f.Signbit()
+	switch {
+	case f.IsInf():
+		return float128Inf(neg)
+	case f.Sign() == 0:
+		return float128Zero(neg)
+	}
+	exp := f.MantExp(nil)
+	if exp > float128Bias+2 {
+		return float128Inf(neg)
+	}
+	if exp < float128MinSubExp-2 {
+		return float128Zero(neg)
+	}
+	r, _ := new(big.Float).Abs(f).Rat(nil)
+	return float128FromRat(r, neg)
+}
+
+// Create a new Float128 from a decimal string, e.g. "1.5", "-2.5e-10", "Inf" or "NaN", rounding to
+// the nearest binary128 value with ties to even.
+func NewFloat128FromString(s string) (Float128, error) {
+	neg := false
+	v := s
+	if len(v) > 0 && (v[0] == '-' || v[0] == '+') {
+		neg = v[0] == '-'
+		v = v[1:]
+	}
+	switch strings.ToLower(v) {
+	case "inf", "infinity":
+		return float128Inf(neg), nil
+	case "nan":
+		return float128FromBits(float128Sign(neg)|float128MaxExp<<48|1<<47, 0), nil
+	}
+	mant, exp, ok := parseDecimal(v)
+	if !ok {
+		return Float128{}, fmt.Errorf("%w: %q", ErrInvalidFloat128, s)
+	}
+	mant = strings.TrimLeft(mant, "0")
+	switch {
+	case mant == "":
+		return float128Zero(neg), nil
+	case int64(len(mant))+exp > 4934: // above max, ~1.19e4932
+		return float128Inf(neg), nil
+	case int64(len(mant))+exp < -4966: // below half the smallest subnormal, ~3.2e-4966
+		return float128Zero(neg), nil
+	}
+	num, _ := new(big.Int).SetString(mant, 10)
+	den := big.NewInt(1)
+	if exp >= 0 {
+		num.Mul(num, pow10(int(exp)))
+	} else {
+		den = pow10(int(-exp))
+	}
+	return float128FromRat(new(big.Rat).SetFrac(num, den), neg), nil
+}
+
+// Returns true if f is not a number.
+func (f128 Float128) IsNaN() bool {
+	hi, lo := f128.bits()
+	return (hi>>48)&float128MaxExp == float128MaxExp && (hi&float128HiMantMask != 0 || lo != 0)
+}
+
+// Returns true if f is an infinity, sign > 0 only matches positive infinity, sign < 0 only negative
+// infinity and sign == 0 either.
+func (f128 Float128) IsInf(sign int) bool {
+	hi, lo := f128.bits()
+	if hi&^(1<<63) != float128MaxExp<<48 || lo != 0 {
+		return false
+	}
+	return sign == 0 || (sign > 0) == (hi>>63 == 0)
+}
+
+// Returns true if the sign bit is set.
+func (f128 Float128) Signbit() bool {
+	hi, _ := f128.bits()
+	return hi>>63 != 0
+}
+
+// Exact value as a big.Float with 113 bits of precision, returns an error for NaN.
+func (f128 Float128) BigFloat() (*big.Float, error) {
+	if f128.IsNaN() {
+		return nil, fmt.Errorf("%w: can not convert NaN to big.Float", ErrInvalidFloat128)
+	}
+	hi, lo := f128.bits()
+	rv := new(big.Float).SetPrec(float128MantBits + 1)
+	exp := int((hi >> 48) & float128MaxExp)
+	switch {
+	case exp == float128MaxExp:
+		rv.SetInf(false)
+	default:
+		mant := new(big.Int).SetUint64(hi & float128HiMantMask)
+		mant.Lsh(mant, 64).Or(mant, new(big.Int).SetUint64(lo))
+		e := float128MinSubExp
+		if exp != 0 {
+			mant.SetBit(mant, float128MantBits, 1)
+			e = exp - float128Bias - float128MantBits
+		}
+		rv.SetMantExp(rv.SetInt(mant), e)
+	}
+	if hi>>63 != 0 {
+		rv.Neg(rv)
+	}
+	return rv, nil
+}
+
+// Value rounded to the nearest float64 with ties to even.
+func (f128 Float128) Float64() float64 {
+	hi, lo := f128.bits()
+	if f128.IsNaN() {
+		payload := (hi&float128HiMantMask)<<4 | lo>>60
+		if payload == 0 {
+			payload = 1 << 51
+		}
+		return math.Float64frombits(hi&(1<<63) | 0x7ff<<52 | payload)
+	}
+	bf, _ := f128.BigFloat()
+	rv, _ := bf.Float64()
+	return rv
+}
+
+// Shortest decimal representation that parses back to the same value at 113 bits of precision,
+// e.g. "1.5" or "1e+100". Infinities and NaN are formatted as "+Inf", "-Inf" and "NaN".
+func (f128 Float128) String() string {
+	if f128.IsNaN() {
+		return "NaN"
+	}
+	bf, _ := f128.BigFloat()
+	if bf.IsInf() {
+		return bf.String()
+	}
+	return bf.Text('g', -1)
+}
+
+func (f Float128Number) String() string {
+	return Float128(f).String()
+}
+
+// abi.Marshaler conformance
+
+func (f128 Float128) MarshalABI(e *abi.Encoder) error {
+	return e.WriteBytes(f128.Data[:])
+}
+
+func (f Float128Number) MarshalABI(e *abi.Encoder) error {
+	return Float128(f).MarshalABI(e)
+}
+
+// abi.Unmarshaler conformance
+
+func (f128 *Float128) UnmarshalABI(d *abi.Decoder) error {
+	_, b, err := d.ReadBytes(16)
+	if err == nil {
+		copy(f128.Data[:], b)
+	}
+	return err
+}
+
+func (f *Float128Number) UnmarshalABI(d *abi.Decoder) error {
+	return (*Float128)(f).UnmarshalABI(d)
+}
+
+// encoding.TextMarshaler conformance
+
+func (f128 Float128) MarshalText() (text []byte, err error) {
+	return []byte(hex.EncodeToString(f128.Data[:])), nil
+}
+
+// encoding.TextUnmarshaler conformance
+
+// Accepts both the hex string of the little-endian bytes and decimal strings. A 32 character string
+// of only digits is read as a decimal unless it starts with a zero, hex strings of common values
+// like 2.0 start with the zero low bytes of the significand while decimals have no leading zeros.
+func (f128 *Float128) UnmarshalText(text []byte) error {
+	if len(text) == 32 && (text[0] == '0' || bytes.ContainsAny(text, "abcdefABCDEF")) {
+		if b, err := hex.DecodeString(string(text)); err == nil {
+			copy(f128.Data[:], b)
+			return nil
+		}
+	}
+	new, err := NewFloat128FromString(string(text))
+	if err == nil {
+		*f128 = new
+	}
+	return err
+}
+
+// json.Marshaler conformance
+
+func (f128 Float128) MarshalJSON() ([]byte, error) {
+	text, _ := f128.MarshalText()
+	return json.Marshal(string(text))
+}
+
+func (f Float128Number) MarshalJSON() ([]byte, error) {
+	if Float128(f).IsNaN() || Float128(f).IsInf(0) {
+		return json.Marshal(f.String())
+	}
+	return []byte(f.String()), nil
+}
+
+// json.Unmarshaler conformance
+
+func (f128 *Float128) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if len(s) > 0 && s[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		return f128.UnmarshalText([]byte(s))
+	}
+	new, err := NewFloat128FromString(s)
+	if err == nil {
+		*f128 = new
+	}
+	return err
+}
+
+// Accepts the same forms as Float128.
+func (f *Float128Number) UnmarshalJSON(b []byte) error {
+	return (*Float128)(f).UnmarshalJSON(b)
+}
+
+// helpers
+
+func (f128 Float128) bits() (hi, lo uint64) {
+	return binary.LittleEndian.Uint64(f128.Data[8:]), binary.LittleEndian.Uint64(f128.Data[:8])
+}
+
+func float128FromBits(hi, lo uint64) Float128 {
+	var rv Float128
+	binary.LittleEndian.PutUint64(rv.Data[:8], lo)
+	binary.LittleEndian.PutUint64(rv.Data[8:], hi)
+	return rv
+}
+
+func float128Inf(neg bool) Float128 {
+	return float128FromBits(float128Sign(neg)|float128MaxExp<<48, 0)
+}
+
+func float128Zero(neg bool) Float128 {
+	return float128FromBits(float128Sign(neg), 0)
+}
+
+func float128Sign(neg bool) uint64 {
+	if neg {
+		return 1 << 63
+	}
+	return 0
+}
+
+// round the positive rational r to the nearest binary128 value
+func float128FromRat(r *big.Rat, neg bool) Float128 {
+	num, den := r.Num(), r.Denom()
+	// find exp so that 2^exp <= r < 2^(exp+1)
+	exp := num.BitLen() - den.BitLen()
+	if scaledCmp(num, den, exp) < 0 {
+		exp--
+	}
+	if exp > float128Bias {
+		return float128Inf(neg)
+	}
+	subnormal := exp < 1-float128Bias
+	shift := float128MantBits - exp
+	if subnormal {
+		shift = -float128MinSubExp
+	}
+	scaled := new(big.Rat)
+	if shift >= 0 {
+		scaled.SetFrac(new(big.Int).Lsh(num, uint(shift)), den)
+	} else {
+		scaled.SetFrac(num, new(big.Int).Lsh(den, uint(-shift)))
+	}
+	mant := roundRat(scaled, big.ToNearestEven)
+	if subnormal {
+		// a mantissa rounded up to 2^112 carries into the exponent, giving the smallest normal
+		u := NewUint128(mant)
+		return float128FromBits(float128Sign(neg)|u.Hi, u.Lo)
+	}
+	if mant.BitLen() > float128MantBits+1 {
+		mant.Rsh(mant, 1)
+		exp++
+		if exp > float128Bias {
+			return float128Inf(neg)
+		}
+	}
+	mant.SetBit(mant, float128MantBits, 0)
+	u := NewUint128(mant)
+	return float128FromBits(float128Sign(neg)|uint64(exp+float128Bias)<<48|u.Hi, u.Lo)
+}
+
+// compare num with den * 2^exp
+func scaledCmp(num, den *big.Int, exp int) int {
+	if exp >= 0 {
+		return num.Cmp(new(big.Int).Lsh(den, uint(exp)))
+	}
+	return new(big.Int).Lsh(num, uint(-exp)).Cmp(den)
+}
+
+// split a decimal number such as "12.5e3" into its digits and base 10 exponent, "125" and 2
+func parseDecimal(s string) (mant string, exp int64, ok bool) {
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			if !errors.Is(err, strconv.ErrRange) {
+				return "", 0, false
+			}
+			// saturate, the result is zero or infinity anyway
+			e = math.MaxInt32
+			if s[i+1] == '-' {
+				e = math.MinInt32
+			}
+		}
+		exp = e
+		s = s[:i]
+	}
+	var b strings.Builder
+	point := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			b.WriteByte(s[i])
+			if point {
+				exp--
+			}
+		case s[i] == '.' && !point:
+			point = true
+		default:
+			return "", 0, false
+		}
+	}
+	if b.Len() == 0 {
+		return "", 0, false
+	}
+	return b.String(), exp, true
+}
@@ -0,0 +1,272 @@
+package chain_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestFloat128(t *testing.T) {
+	f1 := chain.Float128{
+		Data: [16]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+	}
+	assert.JSONCoding(t, f1, `"00000000000000000000000000000000"`)
+	f2 := chain.Float128{
+		Data: [16]byte{0x12, 0x34, 0x56, 0x78, 0xab, 0xcd, 0xef, 0x12, 0x34, 0x56, 0x78, 0xab, 0xcd, 0xef, 0x12, 0x34},
+	}
+	assert.JSONCoding(t, f2, `"12345678abcdef12345678abcdef1234"`)
+	assert.ABICoding(t, f2, f2.Data[:])
+}
+
+func TestFloat128Vectors(t *testing.T) {
+	vectors := []struct {
+		bits    string // big-endian
+		decimal string
+		float   float64
+	}{
+		{"00000000000000000000000000000000", "0", 0},
+		{"80000000000000000000000000000000", "-0", math.Copysign(0, -1)},
+		{"3fff0000000000000000000000000000", "1", 1},
+		{"c0000000000000000000000000000000", "-2", -2},
+		{"3ffe0000000000000000000000000000", "0.5", 0.5},
+		{"3ffb999999999999999999999999999a", "0.1", 0.1},
+		{"3ffd5555555555555555555555555555", "0.3333333333333333333333333333333333", 1.0 / 3},
+		{"4000921fb54442d18469898cc51701b8", "3.1415926535897932384626433832795028", math.Pi},
+		{"3fff0000000000000000000000000001", "1.0000000000000000000000000000000002", 1},
+		{"403e0000000000000000000000000000", "9.223372036854775808e+18", 1 << 63},
+		// largest finite
+		{"7ffeffffffffffffffffffffffffffff", "1.189731495357231765085759326628007e+4932", math.Inf(1)},
+		// smallest normal
+		{"00010000000000000000000000000000", "3.3621031431120935062626778173217526e-4932", 0},
+		// largest subnormal
+		{"0000ffffffffffffffffffffffffffff", "3.362103143112093506262677817321752e-4932", 0},
+		// smallest subnormal
+		{"00000000000000000000000000000001", "6.475175119438025110924438958227647e-4966", 0},
+		{"80000000000000000000000000000001", "-6.475175119438025110924438958227647e-4966", math.Copysign(0, -1)},
+		// float64 limits
+		{"43fefffffffffffff000000000000000", "1.7976931348623157081452742373170436e+308", math.MaxFloat64},
+		{"3bcd0000000000000000000000000000", "4.940656458412465441765687928682214e-324", math.SmallestNonzeroFloat64},
+		{"3c00ffffffffffffe000000000000000", "2.2250738585072008890245868760858599e-308", 0x1p-1022 - 0x1p-1074},
+		{"7fff0000000000000000000000000000", "+Inf", math.Inf(1)},
+		{"ffff0000000000000000000000000000", "-Inf", math.Inf(-1)},
+	}
+	for _, v := range vectors {
+		f := f128(v.bits)
+		assert.Equal(t, f.String(), v.decimal)
+		assert.Equal(t, f.Float64(), v.float)
+		assert.Equal(t, math.Signbit(f.Float64()), math.Signbit(v.float))
+		parsed, err := chain.NewFloat128FromString(v.decimal)
+		assert.NoError(t, err)
+		assert.Equal(t, parsed, f)
+		if !f.IsInf(0) {
+			bf, err := f.BigFloat()
+			assert.NoError(t, err)
+			assert.Equal(t, chain.NewFloat128FromBigFloat(bf), f)
+		}
+	}
+}
+
+func TestFloat128NaN(t *testing.T) {
+	nan := f128("7fff8000000000000000000000000000")
+	assert.True(t, nan.IsNaN())
+	assert.True(t, !nan.IsInf(0))
+	assert.Equal(t, nan.String(), "NaN")
+	assert.True(t, math.IsNaN(nan.Float64()))
+	_, err := nan.BigFloat()
+	assert.True(t, errors.Is(err, chain.ErrInvalidFloat128))
+	parsed, err := chain.NewFloat128FromString("nan")
+	assert.NoError(t, err)
+	assert.Equal(t, parsed, nan)
+	parsed, err = chain.NewFloat128FromString("-NaN")
+	assert.NoError(t, err)
+	assert.True(t, parsed.IsNaN() && parsed.Signbit())
+
+	// signaling nan with payload in the low word
+	snan := f128("7fff0000000000000000000000000001")
+	assert.True(t, snan.IsNaN())
+	assert.True(t, math.IsNaN(snan.Float64()))
+	// payload is kept when widening
+	assert.Equal(t, chain.NewFloat128FromFloat64(math.Float64frombits(0x7ff8000000000123)), f128("7fff8000000000123000000000000000"))
+	assert.Equal(t, math.Float64bits(f128("7fff8000000000123000000000000000").Float64()), uint64(0x7ff8000000000123))
+
+	inf := f128("ffff0000000000000000000000000000")
+	assert.True(t, inf.IsInf(0) && inf.IsInf(-1) && !inf.IsInf(1))
+	assert.True(t, !inf.IsNaN())
+	bf, err := inf.BigFloat()
+	assert.NoError(t, err)
+	assert.True(t, bf.IsInf() && bf.Signbit())
+	assert.Equal(t, chain.NewFloat128FromBigFloat(bf), inf)
+	for _, s := range []string{"inf", "+Inf", "infinity", "1e4933", "1.2e4932", "123456789e999999999999"} {
+		parsed, err = chain.NewFloat128FromString(s)
+		assert.NoError(t, err)
+		assert.True(t, parsed.IsInf(1))
+	}
+	assert.True(t, chain.NewFloat128FromBigFloat(new(big.Float).SetMantExp(big.NewFloat(1), 16384)).IsInf(1))
+}
+
+func TestFloat128Rounding(t *testing.T) {
+	vectors := []struct {
+		decimal string
+		bits    string
+	}{
+		// halfway between 1 and the next value, ties to even
+		{"1.000000000000000000000000000000000096296", "3fff0000000000000000000000000000"},
+		{"1.000000000000000000000000000000000096297", "3fff0000000000000000000000000001"},
+		// halfway between the two smallest subnormals
+		{"9.712762679157037666386658437341469e-4966", "00000000000000000000000000000001"},
+		{"9.712762679157037666386658437341470e-4966", "00000000000000000000000000000002"},
+		// half of the smallest subnormal rounds to zero, anything above to the smallest subnormal
+		{"3.237587559719012555462219479113823e-4966", "00000000000000000000000000000000"},
+		{"3.237587559719012555462219479113824e-4966", "00000000000000000000000000000001"},
+		{"-1e-5000", "80000000000000000000000000000000"},
+		{"1e-999999999999", "00000000000000000000000000000000"},
+		// largest subnormal rounding up to the smallest normal
+		{"3.3621031431120935062626778173217525e-4932", "00010000000000000000000000000000"},
+		// rounding up to infinity
+		{"1.189731495357231765085759326628007073e4932", "7ffeffffffffffffffffffffffffffff"},
+		{"1.189731495357231765085759326628007074e4932", "7fff0000000000000000000000000000"},
+		// syntax
+		{"+.5", "3ffe0000000000000000000000000000"},
+		{"5.", "40014000000000000000000000000000"},
+		{"0.0005E4", "40014000000000000000000000000000"},
+		{"000", "00000000000000000000000000000000"},
+	}
+	for _, v := range vectors {
+		f, err := chain.NewFloat128FromString(v.decimal)
+		assert.NoError(t, err)
+		assert.Equal(t, hex.EncodeToString(reverse(f.Data[:])), v.bits)
+	}
+	for _, s := range []string{"", "-", ".", "e5", "1e", "1e+", "0x1p3", "1.2.3", "1,5", " 1", "infinit", "1_000"} {
+		_, err := chain.NewFloat128FromString(s)
+		assert.True(t, errors.Is(err, chain.ErrInvalidFloat128))
+	}
+
+	// float64 rounding
+	one := big.NewFloat(1).SetPrec(113)
+	tie := new(big.Float).SetMantExp(big.NewFloat(1), -53)
+	above := new(big.Float).SetMantExp(big.NewFloat(1), -112)
+	f := chain.NewFloat128FromBigFloat(new(big.Float).Add(one, tie))
+	assert.Equal(t, f.Float64(), 1.0)
+	f = chain.NewFloat128FromBigFloat(new(big.Float).Add(new(big.Float).Add(one, tie), above))
+	assert.Equal(t, f.Float64(), 1+0x1p-52)
+	// half of the smallest float64 subnormal ties to zero
+	f = chain.NewFloat128FromBigFloat(new(big.Float).SetMantExp(big.NewFloat(1), -1075))
+	assert.Equal(t, f.Float64(), 0.0)
+	f = chain.NewFloat128FromBigFloat(new(big.Float).SetMantExp(big.NewFloat(3), -1076))
+	assert.Equal(t, f.Float64(), math.SmallestNonzeroFloat64)
+
+	// big.Float rounding to 113 bits
+	bf := new(big.Float).SetMantExp(big.NewFloat(1), -113)
+	bf.SetPrec(200)
+	bf.Add(bf, big.NewFloat(1))
+	assert.Equal(t, chain.NewFloat128FromBigFloat(bf), f128("3fff0000000000000000000000000000"))
+	bf.Add(bf, new(big.Float).SetMantExp(big.NewFloat(1), -150))
+	assert.Equal(t, chain.NewFloat128FromBigFloat(bf), f128("3fff0000000000000000000000000001"))
+}
+
+func TestFloat128Float64(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	values := []float64{0, 1, -1, math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64, 0x1p-1022, 0x1p-1023, math.Inf(1), math.Inf(-1)}
+	for i := 0; i < 1000; i++ {
+		values = append(values, math.Float64frombits(rng.Uint64()))
+		values = append(values, math.Float64frombits(rng.Uint64()&0x800fffffffffffff)) // subnormals
+	}
+	for _, v := range values {
+		f := chain.NewFloat128FromFloat64(v)
+		if math.IsNaN(v) {
+			assert.True(t, f.IsNaN())
+			assert.Equal(t, math.Float64bits(f.Float64()), math.Float64bits(v))
+			continue
+		}
+		assert.Equal(t, math.Float64bits(f.Float64()), math.Float64bits(v))
+		bf, err := f.BigFloat()
+		assert.NoError(t, err)
+		assert.Equal(t, bf.Cmp(big.NewFloat(v)), 0)
+		assert.Equal(t, bf.Signbit(), math.Signbit(v))
+	}
+}
+
+func TestFloat128RoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		var f chain.Float128
+		rng.Read(f.Data[:])
+		if i%4 == 0 {
+			f.Data[15] &= 0x80 // subnormals
+			f.Data[14] = 0
+		}
+		if f.IsNaN() || f.IsInf(0) {
+			continue
+		}
+		parsed, err := chain.NewFloat128FromString(f.String())
+		assert.NoError(t, err)
+		assert.Equal(t, parsed, f)
+		bf, err := f.BigFloat()
+		assert.NoError(t, err)
+		assert.Equal(t, chain.NewFloat128FromBigFloat(bf), f)
+	}
+}
+
+func TestFloat128JSON(t *testing.T) {
+	pi := f128("4000921fb54442d18469898cc51701b8")
+	assert.JSONCoding(t, pi, `"b80117c58c896984d14244b51f920040"`)
+
+	var f chain.Float128
+	for _, s := range []string{`3.1415926535897932384626433832795028`, `"3.1415926535897932384626433832795028"`, `"b80117c58c896984d14244b51f920040"`} {
+		assert.NoError(t, json.Unmarshal([]byte(s), &f))
+		assert.Equal(t, f, pi)
+	}
+	// 32 digit strings are decimals unless they have a leading zero
+	assert.NoError(t, json.Unmarshal([]byte(`"10000000000000000000000000000000"`), &f))
+	e31, _ := chain.NewFloat128FromString("1e31")
+	assert.Equal(t, f, e31)
+	two, _ := chain.NewFloat128FromString("2")
+	assert.JSONCoding(t, two, `"00000000000000000000000000000040"`)
+	assert.NoError(t, json.Unmarshal([]byte(`"-Inf"`), &f))
+	assert.True(t, f.IsInf(-1))
+	assert.True(t, json.Unmarshal([]byte(`"foo"`), &f) != nil)
+	assert.True(t, json.Unmarshal([]byte(`true`), &f) != nil)
+
+	// numeric json
+	assert.JSONCoding(t, chain.Float128Number(pi), `3.1415926535897932384626433832795028`)
+	f, _ = chain.NewFloat128FromString("-1e100")
+	assert.JSONCoding(t, chain.Float128Number(f), `-1e+100`)
+	assert.JSONCoding(t, chain.Float128Number(f128("80000000000000000000000000000000")), `-0`)
+	assert.JSONCoding(t, chain.Float128Number(f128("7fff0000000000000000000000000000")), `"+Inf"`)
+	assert.JSONCoding(t, chain.Float128Number(f128("7fff8000000000000000000000000000")), `"NaN"`)
+	var n chain.Float128Number
+	assert.NoError(t, json.Unmarshal([]byte(`"b80117c58c896984d14244b51f920040"`), &n))
+	assert.Equal(t, chain.Float128(n), pi)
+	assert.ABICoding(t, chain.Float128Number(pi), pi.Data[:])
+	assert.JSONCoding(t, struct {
+		Value chain.Float128Number `json:"value"`
+	}{chain.Float128Number(pi)}, `{"value": 3.1415926535897932384626433832795028}`)
+}
+
+// helpers
+
+// create a float128 from its big-endian hex representation
+func f128(s string) chain.Float128 {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 16 {
+		panic("invalid float128 hex")
+	}
+	var rv chain.Float128
+	copy(rv.Data[:], reverse(b))
+	return rv
+}
+
+func reverse(b []byte) []byte {
+	rv := make([]byte, len(b))
+	for i := range b {
+		rv[len(b)-1-i] = b[i]
+	}
+	return rv
+}
@@ -0,0 +1,32 @@
+package chain
+
+import "time"
+
+// Chain state as returned by the get_info API endpoint.
+type ChainInfo struct {
+	ServerVersion             string      `json:"server_version"`
+	ChainID                   Checksum256 `json:"chain_id"`
+	HeadBlockNum              BlockNum    `json:"head_block_num"`
+	LastIrreversibleBlockNum  BlockNum    `json:"last_irreversible_block_num"`
+	LastIrreversibleBlockID   BlockID     `json:"last_irreversible_block_id"`
+	HeadBlockID               BlockID     `json:"head_block_id"`
+	HeadBlockTime             TimePoint   `json:"head_block_time"`
+	HeadBlockProducer         Name        `json:"head_block_producer"`
+	VirtualBlockCpuLimit      uint64      `json:"virtual_block_cpu_limit"`
+	VirtualBlockNetLimit      uint64      `json:"virtual_block_net_limit"`
+	BlockCpuLimit             uint64      `json:"block_cpu_limit"`
+	BlockNetLimit             uint64      `json:"block_net_limit"`
+	ServerVersionString       string      `json:"server_version_string,omitempty"`
+	ForkDBHeadBlockNum        BlockNum    `json:"fork_db_head_block_num,omitempty"`
+	ForkDBHeadBlockID         *BlockID    `json:"fork_db_head_block_id,omitempty"`
+	ServerFullVersionString   string      `json:"server_full_version_string,omitempty"`
+	EarliestAvailableBlockNum BlockNum    `json:"earliest_available_block_num,omitempty"`
+}
+
+// Transaction header referencing the last irreversible block, expiring at the head block time plus given duration.
+func (ci ChainInfo) TransactionHeader(expireIn time.Duration) TransactionHeader {
+	var txh TransactionHeader
+	txh.SetReferenceBlock(ci.LastIrreversibleBlockID)
+	txh.Expiration = NewTimePointSec(ci.HeadBlockTime.Time().Add(expireIn))
+	return txh
+}
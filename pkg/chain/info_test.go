@@ -0,0 +1,43 @@
+package chain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestChainInfo(t *testing.T) {
+	var info chain.ChainInfo
+	err := json.Unmarshal([]byte(`{
+		"server_version": "4a6cc8a3",
+		"chain_id": "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906",
+		"head_block_num": 194827440,
+		"last_irreversible_block_num": 194827431,
+		"last_irreversible_block_id": "0b9cd4a7d3d0c7c6ba1d4f2aa3b8fd6d8a73b1e3ab54dd01d4d54f7e9a04ad30",
+		"head_block_id": "0b9cd4b0d6c3d8a7c1b7e4f7c0f8a0e1d2c3b4a5968778695a4b3c2d1e0f1a2b",
+		"head_block_time": "2021-06-28T14:31:35.500",
+		"head_block_producer": "eosnationftw",
+		"virtual_block_cpu_limit": 200000000,
+		"virtual_block_net_limit": 1048576000,
+		"block_cpu_limit": 199900,
+		"block_net_limit": 1048576,
+		"server_version_string": "v2.0.12",
+		"fork_db_head_block_num": 194827440,
+		"fork_db_head_block_id": "0b9cd4b0d6c3d8a7c1b7e4f7c0f8a0e1d2c3b4a5968778695a4b3c2d1e0f1a2b",
+		"server_full_version_string": "v2.0.12-4a6cc8a3"
+	}`), &info)
+	assert.NoError(t, err)
+	assert.Equal(t, info.ChainID.String(), "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906")
+	assert.Equal(t, info.HeadBlockNum, chain.BlockNum(194827440))
+	assert.Equal(t, info.HeadBlockID.Num(), info.HeadBlockNum)
+	assert.Equal(t, info.HeadBlockProducer, chain.N("eosnationftw"))
+	assert.True(t, info.HeadBlockTime.Time().Equal(time.Date(2021, 6, 28, 14, 31, 35, 500000000, time.UTC)))
+
+	txh := info.TransactionHeader(time.Minute)
+	assert.Equal(t, txh.RefBlockNum, uint16(54439))
+	assert.Equal(t, txh.RefBlockPrefix, uint32(709828026))
+	assert.Equal(t, txh.Expiration.String(), "2021-06-28T14:32:35")
+}
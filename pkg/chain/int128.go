@@ -0,0 +1,331 @@
+package chain
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	ErrIntegerOverflow       = errors.New("integer overflow")
+	ErrIntegerDivisionByZero = errors.New("integer division by zero")
+)
+
+// Largest and smallest 128-bit values.
+var (
+	MaxUint128 = Uint128{Lo: 1<<64 - 1, Hi: 1<<64 - 1}
+	MaxInt128  = Int128{Lo: 1<<64 - 1, Hi: 1<<63 - 1}
+	MinInt128  = Int128{Lo: 0, Hi: 1 << 63}
+)
+
+// Create a 128-bit composite key from two names, the same as (uint128_t{hi.value} << 64) | lo.value
+// in a contract.
+func NewUint128FromNames(hi Name, lo Name) Uint128 {
+	return Uint128{Lo: uint64(lo), Hi: uint64(hi)}
+}
+
+// Split a 128-bit composite key into the two names it was created from.
+func (u128 Uint128) Names() (hi Name, lo Name) {
+	return Name(u128.Hi), Name(u128.Lo)
+}
+
+func (u128 Uint128) IsZero() bool {
+	return u128.Lo == 0 && u128.Hi == 0
+}
+
+// Compare u128 with v, returns -1 if u128 < v, 0 if u128 == v and 1 if u128 > v.
+func (u128 Uint128) Cmp(v Uint128) int {
+	switch {
+	case u128 == v:
+		return 0
+	case u128.Hi < v.Hi || (u128.Hi == v.Hi && u128.Lo < v.Lo):
+		return -1
+	default:
+		return 1
+	}
+}
+
+func (u128 Uint128) Add(v Uint128) (Uint128, error) {
+	lo, carry := bits.Add64(u128.Lo, v.Lo, 0)
+	hi, carry := bits.Add64(u128.Hi, v.Hi, carry)
+	if carry != 0 {
+		return Uint128{}, ErrIntegerOverflow
+	}
+	return Uint128{lo, hi}, nil
+}
+
+func (u128 Uint128) Sub(v Uint128) (Uint128, error) {
+	lo, borrow := bits.Sub64(u128.Lo, v.Lo, 0)
+	hi, borrow := bits.Sub64(u128.Hi, v.Hi, borrow)
+	if borrow != 0 {
+		return Uint128{}, ErrIntegerOverflow
+	}
+	return Uint128{lo, hi}, nil
+}
+
+func (u128 Uint128) Mul(v Uint128) (Uint128, error) {
+	if u128.Hi != 0 && v.Hi != 0 {
+		return Uint128{}, ErrIntegerOverflow
+	}
+	h1, l1 := bits.Mul64(u128.Hi, v.Lo)
+	h2, l2 := bits.Mul64(u128.Lo, v.Hi)
+	hi, lo := bits.Mul64(u128.Lo, v.Lo)
+	hi, carry1 := bits.Add64(hi, l1, 0)
+	hi, carry2 := bits.Add64(hi, l2, 0)
+	if h1 != 0 || h2 != 0 || carry1 != 0 || carry2 != 0 {
+		return Uint128{}, ErrIntegerOverflow
+	}
+	return Uint128{lo, hi}, nil
+}
+
+func (u128 Uint128) Div(v Uint128) (Uint128, error) {
+	q, _, err := u128.DivMod(v)
+	return q, err
+}
+
+func (u128 Uint128) Mod(v Uint128) (Uint128, error) {
+	_, r, err := u128.DivMod(v)
+	return r, err
+}
+
+// Return the quotient and remainder of u128 / v.
+func (u128 Uint128) DivMod(v Uint128) (q Uint128, r Uint128, err error) {
+	if v.IsZero() {
+		return Uint128{}, Uint128{}, ErrIntegerDivisionByZero
+	}
+	if v.Hi == 0 {
+		var r64 uint64
+		if u128.Hi < v.Lo {
+			q.Lo, r64 = bits.Div64(u128.Hi, u128.Lo, v.Lo)
+		} else {
+			q.Hi, r64 = bits.Div64(0, u128.Hi, v.Lo)
+			q.Lo, r64 = bits.Div64(r64, u128.Lo, v.Lo)
+		}
+		return q, Uint128{Lo: r64}, nil
+	}
+	// the quotient fits in 64 bits, estimate it from the top bits and correct it by at most one,
+	// see Hacker's Delight 9-5
+	n := uint(bits.LeadingZeros64(v.Hi))
+	v1 := v.Lsh(n)
+	u1 := u128.Rsh(1)
+	tq, _ := bits.Div64(u1.Hi, u1.Lo, v1.Hi)
+	tq >>= 63 - n
+	if tq != 0 {
+		tq--
+	}
+	q = Uint128{Lo: tq}
+	r = u128.wrappingSub(v.wrappingMul(q))
+	if r.Cmp(v) >= 0 {
+		q.Lo++
+		r = r.wrappingSub(v)
+	}
+	return q, r, nil
+}
+
+// Shift left by n bits, bits shifted out are discarded.
+func (u128 Uint128) Lsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{0, u128.Lo << (n - 64)}
+	default:
+		return Uint128{u128.Lo << n, u128.Hi<<n | u128.Lo>>(64-n)}
+	}
+}
+
+// Shift right by n bits.
+func (u128 Uint128) Rsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{u128.Hi >> (n - 64), 0}
+	default:
+		return Uint128{u128.Lo>>n | u128.Hi<<(64-n), u128.Hi >> n}
+	}
+}
+
+func (u128 Uint128) And(v Uint128) Uint128 {
+	return Uint128{u128.Lo & v.Lo, u128.Hi & v.Hi}
+}
+
+func (u128 Uint128) Or(v Uint128) Uint128 {
+	return Uint128{u128.Lo | v.Lo, u128.Hi | v.Hi}
+}
+
+func (u128 Uint128) Xor(v Uint128) Uint128 {
+	return Uint128{u128.Lo ^ v.Lo, u128.Hi ^ v.Hi}
+}
+
+func (u128 Uint128) Not() Uint128 {
+	return Uint128{^u128.Lo, ^u128.Hi}
+}
+
+// Number of bits required to represent u128.
+func (u128 Uint128) BitLen() int {
+	if u128.Hi != 0 {
+		return 64 + bits.Len64(u128.Hi)
+	}
+	return bits.Len64(u128.Lo)
+}
+
+// Returns -1 if i128 < 0, 0 if i128 == 0 and 1 if i128 > 0.
+func (i128 Int128) Sign() int {
+	switch {
+	case int64(i128.Hi) < 0:
+		return -1
+	case i128.Lo == 0 && i128.Hi == 0:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// Compare i128 with v, returns -1 if i128 < v, 0 if i128 == v and 1 if i128 > v.
+func (i128 Int128) Cmp(v Int128) int {
+	switch {
+	case i128 == v:
+		return 0
+	case int64(i128.Hi) < int64(v.Hi) || (i128.Hi == v.Hi && i128.Lo < v.Lo):
+		return -1
+	default:
+		return 1
+	}
+}
+
+func (i128 Int128) Add(v Int128) (Int128, error) {
+	rv := Int128(Uint128(i128).wrappingAdd(Uint128(v)))
+	if i128.neg() == v.neg() && rv.neg() != i128.neg() {
+		return Int128{}, ErrIntegerOverflow
+	}
+	return rv, nil
+}
+
+func (i128 Int128) Sub(v Int128) (Int128, error) {
+	rv := Int128(Uint128(i128).wrappingSub(Uint128(v)))
+	if i128.neg() != v.neg() && rv.neg() != i128.neg() {
+		return Int128{}, ErrIntegerOverflow
+	}
+	return rv, nil
+}
+
+func (i128 Int128) Mul(v Int128) (Int128, error) {
+	p, err := i128.abs().Mul(v.abs())
+	if err != nil {
+		return Int128{}, err
+	}
+	return int128WithSign(p, i128.neg() != v.neg())
+}
+
+// Divide i128 by v, truncating towards zero.
+func (i128 Int128) Div(v Int128) (Int128, error) {
+	q, _, err := i128.DivMod(v)
+	return q, err
+}
+
+// Remainder of the truncated division, with the same sign as i128.
+func (i128 Int128) Mod(v Int128) (Int128, error) {
+	_, ur, err := i128.abs().DivMod(v.abs())
+	if err != nil {
+		return Int128{}, err
+	}
+	return int128WithSign(ur, i128.neg())
+}
+
+// Return the quotient truncated towards zero and the remainder of i128 / v, like the / and % operators.
+func (i128 Int128) DivMod(v Int128) (q Int128, r Int128, err error) {
+	uq, ur, err := i128.abs().DivMod(v.abs())
+	if err != nil {
+		return Int128{}, Int128{}, err
+	}
+	q, err = int128WithSign(uq, i128.neg() != v.neg())
+	if err != nil {
+		return Int128{}, Int128{}, err
+	}
+	r, _ = int128WithSign(ur, i128.neg())
+	return q, r, nil
+}
+
+func (i128 Int128) Neg() (Int128, error) {
+	if i128 == MinInt128 {
+		return Int128{}, ErrIntegerOverflow
+	}
+	return Int128(Uint128{}.wrappingSub(Uint128(i128))), nil
+}
+
+// Shift left by n bits, bits shifted out are discarded.
+func (i128 Int128) Lsh(n uint) Int128 {
+	return Int128(Uint128(i128).Lsh(n))
+}
+
+// Arithmetic shift right by n bits, keeping the sign.
+func (i128 Int128) Rsh(n uint) Int128 {
+	sign := uint64(int64(i128.Hi) >> 63)
+	switch {
+	case n >= 128:
+		return Int128{sign, sign}
+	case n >= 64:
+		return Int128{uint64(int64(i128.Hi) >> (n - 64)), sign}
+	default:
+		return Int128{i128.Lo>>n | i128.Hi<<(64-n), uint64(int64(i128.Hi) >> n)}
+	}
+}
+
+func (i128 Int128) And(v Int128) Int128 {
+	return Int128(Uint128(i128).And(Uint128(v)))
+}
+
+func (i128 Int128) Or(v Int128) Int128 {
+	return Int128(Uint128(i128).Or(Uint128(v)))
+}
+
+func (i128 Int128) Xor(v Int128) Int128 {
+	return Int128(Uint128(i128).Xor(Uint128(v)))
+}
+
+func (i128 Int128) Not() Int128 {
+	return Int128(Uint128(i128).Not())
+}
+
+// helpers
+
+func (u128 Uint128) wrappingAdd(v Uint128) Uint128 {
+	lo, carry := bits.Add64(u128.Lo, v.Lo, 0)
+	return Uint128{lo, u128.Hi + v.Hi + carry}
+}
+
+func (u128 Uint128) wrappingSub(v Uint128) Uint128 {
+	lo, borrow := bits.Sub64(u128.Lo, v.Lo, 0)
+	return Uint128{lo, u128.Hi - v.Hi - borrow}
+}
+
+func (u128 Uint128) wrappingMul(v Uint128) Uint128 {
+	hi, lo := bits.Mul64(u128.Lo, v.Lo)
+	return Uint128{lo, hi + u128.Hi*v.Lo + u128.Lo*v.Hi}
+}
+
+func (i128 Int128) neg() bool {
+	return int64(i128.Hi) < 0
+}
+
+// absolute value, MinInt128 becomes 2^127
+func (i128 Int128) abs() Uint128 {
+	if i128.neg() {
+		return Uint128{}.wrappingSub(Uint128(i128))
+	}
+	return Uint128(i128)
+}
+
+// convert an absolute value to Int128 with the given sign, checking the range
+func int128WithSign(u Uint128, neg bool) (Int128, error) {
+	if neg {
+		if u.Cmp(Uint128(MinInt128)) > 0 {
+			return Int128{}, ErrIntegerOverflow
+		}
+		return Int128(Uint128{}.wrappingSub(u)), nil
+	}
+	if int64(u.Hi) < 0 {
+		return Int128{}, ErrIntegerOverflow
+	}
+	return Int128(u), nil
+}
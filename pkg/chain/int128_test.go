@@ -0,0 +1,218 @@
+package chain_test
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestUint128Arithmetic(t *testing.T) {
+	one := chain.Uint128{Lo: 1}
+	max := chain.MaxUint128
+
+	v, err := chain.Uint128{Lo: 1<<64 - 1}.Add(one)
+	assert.NoError(t, err)
+	assert.Equal(t, v, chain.Uint128{Lo: 0, Hi: 1})
+	v, err = v.Sub(one)
+	assert.NoError(t, err)
+	assert.Equal(t, v, chain.Uint128{Lo: 1<<64 - 1})
+	_, err = max.Add(one)
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = chain.Uint128{}.Sub(one)
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = chain.Uint128{Hi: 1}.Mul(chain.Uint128{Hi: 1})
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = chain.Uint128{Lo: 1 << 63}.Mul(chain.Uint128{Hi: 2})
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = max.Mul(chain.Uint128{Lo: 2})
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	v, err = max.Mul(one)
+	assert.NoError(t, err)
+	assert.Equal(t, v, max)
+	_, _, err = max.DivMod(chain.Uint128{})
+	assert.True(t, errors.Is(err, chain.ErrIntegerDivisionByZero))
+
+	assert.Equal(t, one.Lsh(127), chain.Uint128{Hi: 1 << 63})
+	assert.Equal(t, max.Rsh(127), one)
+	assert.Equal(t, max.Lsh(128), chain.Uint128{})
+	assert.Equal(t, max.Rsh(200), chain.Uint128{})
+	assert.Equal(t, max.Lsh(0), max)
+	assert.Equal(t, chain.Uint128{}.Not(), max)
+	assert.Equal(t, max.BitLen(), 128)
+	assert.Equal(t, one.BitLen(), 1)
+	assert.Equal(t, chain.Uint128{}.BitLen(), 0)
+	assert.True(t, chain.Uint128{}.IsZero())
+	assert.Equal(t, one.Cmp(max), -1)
+	assert.Equal(t, max.Cmp(one), 1)
+	assert.Equal(t, max.Cmp(max), 0)
+}
+
+func TestInt128Arithmetic(t *testing.T) {
+	one := chain.Int128{Lo: 1}
+	minusOne := chain.Int128{Lo: 1<<64 - 1, Hi: 1<<64 - 1}
+
+	v, err := one.Sub(chain.Int128{Lo: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, v, minusOne)
+	assert.Equal(t, v.Sign(), -1)
+	assert.Equal(t, minusOne.Cmp(one), -1)
+	assert.Equal(t, chain.MinInt128.Cmp(chain.MaxInt128), -1)
+
+	_, err = chain.MaxInt128.Add(one)
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = chain.MinInt128.Sub(one)
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = chain.MinInt128.Neg()
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = chain.MinInt128.Mul(minusOne)
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = chain.MinInt128.Div(minusOne)
+	assert.True(t, errors.Is(err, chain.ErrIntegerOverflow))
+	_, err = one.Mod(chain.Int128{})
+	assert.True(t, errors.Is(err, chain.ErrIntegerDivisionByZero))
+	v, err = chain.MinInt128.Mul(one)
+	assert.NoError(t, err)
+	assert.Equal(t, v, chain.MinInt128)
+	v, err = chain.MinInt128.Mod(minusOne)
+	assert.NoError(t, err)
+	assert.Equal(t, v, chain.Int128{})
+
+	// truncated division like the / and % operators
+	q, r, err := chain.NewInt128(big.NewInt(-7)).DivMod(chain.NewInt128(big.NewInt(2)))
+	assert.NoError(t, err)
+	assert.Equal(t, q.String(), "-3")
+	assert.Equal(t, r.String(), "-1")
+	q, r, err = chain.NewInt128(big.NewInt(7)).DivMod(chain.NewInt128(big.NewInt(-2)))
+	assert.NoError(t, err)
+	assert.Equal(t, q.String(), "-3")
+	assert.Equal(t, r.String(), "1")
+
+	assert.Equal(t, minusOne.Rsh(100), minusOne)
+	assert.Equal(t, chain.MinInt128.Rsh(127), minusOne)
+	assert.Equal(t, chain.MinInt128.Rsh(200), minusOne)
+	assert.Equal(t, chain.MaxInt128.Rsh(126), one)
+	assert.Equal(t, one.Lsh(127), chain.MinInt128)
+	assert.Equal(t, chain.MaxInt128.Not(), chain.MinInt128)
+}
+
+func TestInt128Random(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	mod := new(big.Int).Lsh(big.NewInt(1), 128)
+	maxU := new(big.Int).Sub(mod, big.NewInt(1))
+	maxI := new(big.Int).Rsh(maxU, 1)
+	minI := new(big.Int).Neg(new(big.Int).Add(maxI, big.NewInt(1)))
+	random := func() chain.Uint128 {
+		v := chain.Uint128{Lo: rng.Uint64(), Hi: rng.Uint64()}
+		// mix in small values and values with zero words to hit all division paths
+		switch rng.Intn(4) {
+		case 0:
+			v = v.Rsh(uint(rng.Intn(128)))
+		case 1:
+			v.Hi = 0
+		}
+		return v
+	}
+	checkU := func(op string, a, b chain.Uint128, expected *big.Int, v chain.Uint128, err error) {
+		if expected.Sign() < 0 || expected.Cmp(maxU) > 0 {
+			if !errors.Is(err, chain.ErrIntegerOverflow) {
+				t.Fatalf("%v %s %v: expected overflow, got %v %v", a, op, b, v, err)
+			}
+			return
+		}
+		if err != nil || v.BigInt().Cmp(expected) != 0 {
+			t.Fatalf("%v %s %v: expected %v, got %v %v", a, op, b, expected, v, err)
+		}
+	}
+	checkI := func(op string, a, b chain.Int128, expected *big.Int, v chain.Int128, err error) {
+		if expected.Cmp(minI) < 0 || expected.Cmp(maxI) > 0 {
+			if !errors.Is(err, chain.ErrIntegerOverflow) {
+				t.Fatalf("%v %s %v: expected overflow, got %v %v", a, op, b, v, err)
+			}
+			return
+		}
+		if err != nil || v.BigInt().Cmp(expected) != 0 {
+			t.Fatalf("%v %s %v: expected %v, got %v %v", a, op, b, expected, v, err)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		a, b := random(), random()
+		ba, bb := a.BigInt(), b.BigInt()
+		v, err := a.Add(b)
+		checkU("+", a, b, new(big.Int).Add(ba, bb), v, err)
+		v, err = a.Sub(b)
+		checkU("-", a, b, new(big.Int).Sub(ba, bb), v, err)
+		v, err = a.Mul(b)
+		checkU("*", a, b, new(big.Int).Mul(ba, bb), v, err)
+		assert.Equal(t, a.Cmp(b), ba.Cmp(bb))
+		n := uint(rng.Intn(130))
+		assert.Equal(t, a.Lsh(n).BigInt().Cmp(new(big.Int).And(new(big.Int).Lsh(ba, n), maxU)), 0)
+		assert.Equal(t, a.Rsh(n).BigInt().Cmp(new(big.Int).Rsh(ba, n)), 0)
+		assert.Equal(t, a.And(b).BigInt().Cmp(new(big.Int).And(ba, bb)), 0)
+		assert.Equal(t, a.Or(b).BigInt().Cmp(new(big.Int).Or(ba, bb)), 0)
+		assert.Equal(t, a.Xor(b).BigInt().Cmp(new(big.Int).Xor(ba, bb)), 0)
+		assert.Equal(t, a.BitLen(), ba.BitLen())
+		if !b.IsZero() {
+			q, r, err := a.DivMod(b)
+			assert.NoError(t, err)
+			bq, br := new(big.Int).QuoRem(ba, bb, new(big.Int))
+			if q.BigInt().Cmp(bq) != 0 || r.BigInt().Cmp(br) != 0 {
+				t.Fatalf("%v / %v: expected %v %v, got %v %v", a, b, bq, br, q, r)
+			}
+		}
+
+		ia, ib := chain.Int128(a), chain.Int128(b)
+		ba, bb = ia.BigInt(), ib.BigInt()
+		iv, err := ia.Add(ib)
+		checkI("+", ia, ib, new(big.Int).Add(ba, bb), iv, err)
+		iv, err = ia.Sub(ib)
+		checkI("-", ia, ib, new(big.Int).Sub(ba, bb), iv, err)
+		iv, err = ia.Mul(ib)
+		checkI("*", ia, ib, new(big.Int).Mul(ba, bb), iv, err)
+		iv, err = ia.Neg()
+		checkI("neg", ia, ia, new(big.Int).Neg(ba), iv, err)
+		assert.Equal(t, ia.Cmp(ib), ba.Cmp(bb))
+		assert.Equal(t, ia.Sign(), ba.Sign())
+		assert.Equal(t, ia.Rsh(n).BigInt().Cmp(new(big.Int).Rsh(ba, n)), 0)
+		if ib.Sign() != 0 {
+			// big.Int QuoRem truncates like Int128.DivMod
+			bq, br := new(big.Int).QuoRem(ba, bb, new(big.Int))
+			q, err := ia.Div(ib)
+			checkI("/", ia, ib, bq, q, err)
+			r, err := ia.Mod(ib)
+			assert.NoError(t, err)
+			assert.Equal(t, r.BigInt().Cmp(br), 0)
+		}
+	}
+}
+
+func TestInt128Allocations(t *testing.T) {
+	a := chain.Uint128{Lo: 0x0123456789abcdef, Hi: 0xfedcba9876543210}
+	b := chain.Uint128{Lo: 0x1111111111111111, Hi: 0x22}
+	allocs := testing.AllocsPerRun(100, func() {
+		a.Add(b)
+		a.Sub(b)
+		b.Mul(b)
+		a.DivMod(b)
+		a.Cmp(b)
+		a.Lsh(3).Rsh(5).And(b).Or(b).Xor(b).Not()
+		chain.Int128(a).Mul(chain.Int128(b))
+		chain.Int128(a).DivMod(chain.Int128(b))
+		a.Add(chain.MaxUint128)
+		a.Div(chain.Uint128{})
+	})
+	assert.Equal(t, allocs, 0.0)
+}
+
+func TestUint128Names(t *testing.T) {
+	v := chain.NewUint128FromNames(chain.N("alice"), chain.N("eosio.token"))
+	assert.Equal(t, v.Hi, uint64(chain.N("alice")))
+	assert.Equal(t, v.Lo, uint64(chain.N("eosio.token")))
+	assert.Equal(t, v.String(), "69600244652278115211127821372879971840")
+	hi, lo := v.Names()
+	assert.Equal(t, hi, chain.N("alice"))
+	assert.Equal(t, lo, chain.N("eosio.token"))
+}
@@ -0,0 +1,331 @@
+package chain
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+// Key-value tables of a contract keyed by table name, added in eosio::abi/1.2.
+type AbiKvTables map[Name]AbiKvTable
+
+// Secondary indices of a key-value table keyed by index name.
+type AbiKvSecondaryIndices map[Name]AbiKvSecondaryIndex
+
+type AbiKvTable struct {
+	Type             string                `json:"type"`
+	PrimaryIndex     AbiKvPrimaryIndex     `json:"primary_index"`
+	SecondaryIndices AbiKvSecondaryIndices `json:"secondary_indices"`
+}
+
+type AbiKvPrimaryIndex struct {
+	Name Name   `json:"name"`
+	Type string `json:"type"`
+}
+
+type AbiKvSecondaryIndex struct {
+	Type string `json:"type"`
+}
+
+// Return the type of the named primary or secondary index, or an empty string if the table has no such index.
+func (t AbiKvTable) IndexType(index Name) string {
+	if t.PrimaryIndex.Name == index {
+		return t.PrimaryIndex.Type
+	}
+	return t.SecondaryIndices[index].Type
+}
+
+// Encode the value as an order-preserving key of the named index, resolving type aliases using the ABI.
+func (a Abi) EncodeKvKey(table Name, index Name, v interface{}) (Bytes, error) {
+	t, ok := a.KvTables[table]
+	if !ok {
+		return nil, fmt.Errorf("unknown kv table %v", table)
+	}
+	keyType := t.IndexType(index)
+	if keyType == "" {
+		return nil, fmt.Errorf("unknown index %v on kv table %v", index, table)
+	}
+	for i := 0; i < 32; i++ {
+		alias := a.GetType(keyType)
+		if alias == nil {
+			break
+		}
+		keyType = alias.Type
+	}
+	return EncodeKvKey(keyType, v)
+}
+
+// Encode a value as a key for kv lookups. Keys are encoded so that comparing the bytes preserves the
+// order of the values: integers are big-endian with the sign bit flipped, floats have their sign bit
+// flipped or all bits inverted if negative, and strings are terminated by two zero bytes with zero
+// bytes inside the string escaped as 0x00 0x01.
+//
+// Supported types are bool, uint8-uint128, int8-int128, float32, float64, name and string, values must
+// be of the same Go types as used by Abi.Encode.
+func EncodeKvKey(keyType string, v interface{}) (Bytes, error) {
+	var ok bool
+	var rv Bytes
+	switch keyType {
+	case "bool":
+		var vv bool
+		if vv, ok = v.(bool); ok {
+			rv = Bytes{0}
+			if vv {
+				rv[0] = 1
+			}
+		}
+	case "uint8":
+		var vv uint8
+		if vv, ok = v.(uint8); ok {
+			rv = Bytes{vv}
+		}
+	case "uint16":
+		var vv uint16
+		if vv, ok = v.(uint16); ok {
+			rv = make(Bytes, 2)
+			binary.BigEndian.PutUint16(rv, vv)
+		}
+	case "uint32":
+		var vv uint32
+		if vv, ok = v.(uint32); ok {
+			rv = make(Bytes, 4)
+			binary.BigEndian.PutUint32(rv, vv)
+		}
+	case "uint64":
+		var vv uint64
+		var vv2 Uint64
+		if vv, ok = v.(uint64); ok {
+			rv = kvUint64(vv)
+		} else if vv2, ok = v.(Uint64); ok {
+			rv = kvUint64(uint64(vv2))
+		}
+	case "uint128":
+		var vv Uint128
+		if vv, ok = v.(Uint128); ok {
+			rv = vv.Bytes(binary.BigEndian)
+		}
+	case "int8":
+		var vv int8
+		if vv, ok = v.(int8); ok {
+			rv = Bytes{uint8(vv) ^ 0x80}
+		}
+	case "int16":
+		var vv int16
+		if vv, ok = v.(int16); ok {
+			rv = make(Bytes, 2)
+			binary.BigEndian.PutUint16(rv, uint16(vv)^0x8000)
+		}
+	case "int32":
+		var vv int32
+		if vv, ok = v.(int32); ok {
+			rv = make(Bytes, 4)
+			binary.BigEndian.PutUint32(rv, uint32(vv)^0x80000000)
+		}
+	case "int64":
+		var vv int64
+		if vv, ok = v.(int64); ok {
+			rv = kvUint64(uint64(vv) ^ 0x8000000000000000)
+		}
+	case "int128":
+		var vv Int128
+		if vv, ok = v.(Int128); ok {
+			rv = vv.Bytes(binary.BigEndian)
+			rv[0] ^= 0x80
+		}
+	case "float32":
+		var vv float32
+		if vv, ok = v.(float32); ok {
+			if math.IsNaN(float64(vv)) {
+				return nil, fmt.Errorf("can not encode NaN as %v key", keyType)
+			}
+			bits := math.Float32bits(vv)
+			if vv == 0 {
+				bits = 0 // -0 sorts equal to 0
+			}
+			if bits&0x80000000 != 0 {
+				bits = ^bits
+			} else {
+				bits |= 0x80000000
+			}
+			rv = make(Bytes, 4)
+			binary.BigEndian.PutUint32(rv, bits)
+		}
+	case "float64":
+		var vv float64
+		if vv, ok = v.(float64); ok {
+			if math.IsNaN(vv) {
+				return nil, fmt.Errorf("can not encode NaN as %v key", keyType)
+			}
+			bits := math.Float64bits(vv)
+			if vv == 0 {
+				bits = 0 // -0 sorts equal to 0
+			}
+			if bits&0x8000000000000000 != 0 {
+				bits = ^bits
+			} else {
+				bits |= 0x8000000000000000
+			}
+			rv = kvUint64(bits)
+		}
+	case "name":
+		var vv Name
+		if vv, ok = v.(Name); ok {
+			rv = kvUint64(uint64(vv))
+		}
+	case "string":
+		var vv string
+		if vv, ok = v.(string); ok {
+			rv = make(Bytes, 0, len(vv)+2)
+			for i := 0; i < len(vv); i++ {
+				rv = append(rv, vv[i])
+				if vv[i] == 0 {
+					rv = append(rv, 1)
+				}
+			}
+			rv = append(rv, 0, 0)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kv key type %v", keyType)
+	}
+	if !ok {
+		return nil, fmt.Errorf("expected %v found %T", keyType, v)
+	}
+	return rv, nil
+}
+
+// abi.Marshaler conformance
+
+func (kt AbiKvTables) MarshalABI(e *abi.Encoder) error {
+	names := make([]Name, 0, len(kt))
+	for name := range kt {
+		names = append(names, name)
+	}
+	SortNames(names)
+	err := e.WriteVaruint(uint(len(names)))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		err = name.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+		err = kt[name].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t AbiKvTable) MarshalABI(e *abi.Encoder) error {
+	err := e.WriteString(t.Type)
+	if err != nil {
+		return err
+	}
+	err = t.PrimaryIndex.Name.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteString(t.PrimaryIndex.Type)
+	if err != nil {
+		return err
+	}
+	return t.SecondaryIndices.MarshalABI(e)
+}
+
+func (si AbiKvSecondaryIndices) MarshalABI(e *abi.Encoder) error {
+	names := make([]Name, 0, len(si))
+	for name := range si {
+		names = append(names, name)
+	}
+	SortNames(names)
+	err := e.WriteVaruint(uint(len(names)))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		err = name.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+		err = e.WriteString(si[name].Type)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// abi.Unmarshaler conformance
+
+func (kt *AbiKvTables) UnmarshalABI(d *abi.Decoder) error {
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	*kt = make(AbiKvTables, l)
+	for i := uint(0); i < l; i++ {
+		var name Name
+		err = name.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+		var t AbiKvTable
+		err = t.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+		(*kt)[name] = t
+	}
+	return nil
+}
+
+func (t *AbiKvTable) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	t.Type, err = d.ReadString()
+	if err != nil {
+		return err
+	}
+	err = t.PrimaryIndex.Name.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	t.PrimaryIndex.Type, err = d.ReadString()
+	if err != nil {
+		return err
+	}
+	return t.SecondaryIndices.UnmarshalABI(d)
+}
+
+func (si *AbiKvSecondaryIndices) UnmarshalABI(d *abi.Decoder) error {
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	*si = make(AbiKvSecondaryIndices, l)
+	for i := uint(0); i < l; i++ {
+		var name Name
+		err = name.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+		var index AbiKvSecondaryIndex
+		index.Type, err = d.ReadString()
+		if err != nil {
+			return err
+		}
+		(*si)[name] = index
+	}
+	return nil
+}
+
+// helpers
+
+func kvUint64(v uint64) Bytes {
+	rv := make(Bytes, 8)
+	binary.BigEndian.PutUint64(rv, v)
+	return rv
+}
@@ -0,0 +1,149 @@
+package chain_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var kvAbi = loadAbi(`
+{
+    "version": "eosio::abi/1.2",
+    "types": [{"new_type_name": "person_id", "type": "uint64"}],
+    "structs": [
+        {
+            "name": "person",
+            "base": "",
+            "fields": [
+                {"name": "id", "type": "person_id"},
+                {"name": "name", "type": "string"},
+                {"name": "age", "type": "uint8"}
+            ]
+        }
+    ],
+    "actions": [],
+    "tables": [],
+    "ricardian_clauses": [],
+    "kv_tables": {
+        "people": {
+            "type": "person",
+            "primary_index": {"name": "id", "type": "person_id"},
+            "secondary_indices": {
+                "name": {"type": "string"},
+                "age": {"type": "uint8"}
+            }
+        }
+    }
+}
+`)
+
+func TestAbiKvTables(t *testing.T) {
+	people := kvAbi.KvTables[chain.N("people")]
+	assert.Equal(t, people.Type, "person")
+	assert.Equal(t, people.PrimaryIndex, chain.AbiKvPrimaryIndex{Name: chain.N("id"), Type: "person_id"})
+	assert.Equal(t, people.IndexType(chain.N("id")), "person_id")
+	assert.Equal(t, people.IndexType(chain.N("name")), "string")
+	assert.Equal(t, people.IndexType(chain.N("foo")), "")
+
+	assert.ABICoding(t, kvAbi.KvTables, mustDecodeHex(
+		"01"+"00000000a858a9aa"+"06706572736f6e"+ // people: person
+			"0000000000004072"+"09706572736f6e5f6964"+ // primary index id: person_id
+			"02"+"0000000000001433"+"0575696e7438"+"0000000000a0a499"+"06737472696e67", // age: uint8, name: string
+	))
+	assert.JSONCoding(t, kvAbi.KvTables, `{
+		"people": {
+			"type": "person",
+			"primary_index": {"name": "id", "type": "person_id"},
+			"secondary_indices": {"age": {"type": "uint8"}, "name": {"type": "string"}}
+		}
+	}`)
+
+	buf := bytes.NewBuffer(nil)
+	assert.NoError(t, chain.NewEncoder(buf).Encode(kvAbi))
+	var decoded chain.Abi
+	assert.NoError(t, chain.NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&decoded))
+	assert.Equal(t, decoded.KvTables, kvAbi.KvTables)
+	assert.NoError(t, decoded.Validate())
+}
+
+func TestEncodeKvKey(t *testing.T) {
+	vectors := []struct {
+		keyType string
+		value   interface{}
+		key     string
+	}{
+		{"bool", true, "01"},
+		{"uint8", uint8(200), "c8"},
+		{"uint16", uint16(0x1234), "1234"},
+		{"uint32", uint32(7), "00000007"},
+		{"uint64", uint64(1), "0000000000000001"},
+		{"uint64", chain.Uint64(2), "0000000000000002"},
+		{"uint128", chain.Uint128{Hi: 1, Lo: 2}, "00000000000000010000000000000002"},
+		{"int8", int8(-1), "7f"},
+		{"int16", int16(1), "8001"},
+		{"int32", int32(-5), "7ffffffb"},
+		{"int64", int64(1), "8000000000000001"},
+		{"int128", chain.Int128{Hi: math.MaxUint64, Lo: math.MaxUint64}, "7fffffffffffffffffffffffffffffff"},
+		{"float32", float32(-1), "407fffff"},
+		{"float64", float64(1.5), "bff8000000000000"},
+		{"float64", float64(-1.5), "4007ffffffffffff"},
+		{"float64", math.Copysign(0, -1), "8000000000000000"},
+		{"name", chain.N("alice"), "345c850000000000"},
+		{"string", "a\x00b", "610001620000"},
+		{"string", "", "0000"},
+	}
+	for _, v := range vectors {
+		key, err := chain.EncodeKvKey(v.keyType, v.value)
+		assert.NoError(t, err)
+		assert.Equal(t, key, chain.Bytes(mustDecodeHex(v.key)))
+	}
+
+	_, err := chain.EncodeKvKey("uint32", uint64(1))
+	assert.NotNil(t, err)
+	_, err = chain.EncodeKvKey("asset", *chain.A("1.0000 EOS"))
+	assert.NotNil(t, err)
+	_, err = chain.EncodeKvKey("float64", math.NaN())
+	assert.NotNil(t, err)
+}
+
+func TestEncodeKvKeyOrder(t *testing.T) {
+	ints := []int64{math.MinInt64, -1000, -1, 0, 1, 1000, math.MaxInt64}
+	floats := []float64{math.Inf(-1), -1e10, -1.5, -1e-300, 0, 1e-300, 1.5, 1e10, math.Inf(1)}
+	strs := []string{"", "\x00", "\x00\x00", "\x00a", "a", "a\x00", "aa", "b"}
+	var prev chain.Bytes
+	for i, v := range ints {
+		key, err := chain.EncodeKvKey("int64", v)
+		assert.NoError(t, err)
+		assert.True(t, i == 0 || bytes.Compare(prev, key) < 0)
+		prev = key
+	}
+	for i, v := range floats {
+		key, err := chain.EncodeKvKey("float64", v)
+		assert.NoError(t, err)
+		assert.True(t, i == 0 || bytes.Compare(prev, key) < 0)
+		prev = key
+	}
+	for i, v := range strs {
+		key, err := chain.EncodeKvKey("string", v)
+		assert.NoError(t, err)
+		assert.True(t, i == 0 || bytes.Compare(prev, key) < 0)
+		prev = key
+	}
+}
+
+func TestAbiEncodeKvKey(t *testing.T) {
+	key, err := kvAbi.EncodeKvKey(chain.N("people"), chain.N("id"), uint64(42))
+	assert.NoError(t, err)
+	assert.Equal(t, key, chain.Bytes(mustDecodeHex("000000000000002a")))
+	key, err = kvAbi.EncodeKvKey(chain.N("people"), chain.N("name"), "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, key, chain.Bytes(mustDecodeHex("626f620000")))
+
+	_, err = kvAbi.EncodeKvKey(chain.N("people"), chain.N("foo"), "bob")
+	assert.NotNil(t, err)
+	_, err = kvAbi.EncodeKvKey(chain.N("foo"), chain.N("id"), uint64(42))
+	assert.NotNil(t, err)
+}
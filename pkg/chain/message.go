@@ -0,0 +1,149 @@
+package chain
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+var ErrMessageChecksum = errors.New("invalid message checksum, wrong key or corrupted message")
+
+// Message encrypted for the owner of a public key, compatible with the sealed messages used by Anchor.
+type SealedMessage struct {
+	From       PublicKey `json:"from"`
+	Nonce      uint64    `json:"nonce"`
+	Ciphertext Bytes     `json:"ciphertext"`
+	Checksum   uint32    `json:"checksum"`
+}
+
+// Encrypt a message for the owner of given public key using a random nonce.
+func SealMessage(message []byte, priv PrivateKey, pub PublicKey) (*SealedMessage, error) {
+	var b [8]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return nil, err
+	}
+	nonce := binary.LittleEndian.Uint64(b[:])
+	from, err := priv.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, checksum, err := priv.Encrypt(pub, nonce, message)
+	if err != nil {
+		return nil, err
+	}
+	return &SealedMessage{
+		From:       *from,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+		Checksum:   checksum,
+	}, nil
+}
+
+// Decrypt the message using the private key of the recipient.
+func (sm SealedMessage) Unseal(priv PrivateKey) (Bytes, error) {
+	return priv.Decrypt(sm.From, sm.Nonce, sm.Ciphertext, sm.Checksum)
+}
+
+// Encrypt a message for the owner of given public key using AES-256-CBC with a key derived from the
+// shared secret and nonce, the nonce must never be reused for the same key pair. Returns the ciphertext
+// and a checksum of the encryption key that is used to detect decryption with the wrong key.
+func (pk PrivateKey) Encrypt(pub PublicKey, nonce uint64, message []byte) (Bytes, uint32, error) {
+	block, iv, checksum, err := messageCipher(pk, pub, nonce)
+	if err != nil {
+		return nil, 0, err
+	}
+	// pkcs7 padding
+	padding := aes.BlockSize - len(message)%aes.BlockSize
+	data := make([]byte, len(message), len(message)+padding)
+	copy(data, message)
+	data = append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+	return data, checksum, nil
+}
+
+// Decrypt a message encrypted by the owner of given public key, see Encrypt.
+func (pk PrivateKey) Decrypt(pub PublicKey, nonce uint64, ciphertext Bytes, checksum uint32) (Bytes, error) {
+	block, iv, expected, err := messageCipher(pk, pub, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if checksum != expected {
+		return nil, ErrMessageChecksum
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid ciphertext length")
+	}
+	data := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, ciphertext)
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(data[len(data)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("invalid message padding")
+	}
+	return data[:len(data)-padding], nil
+}
+
+// abi.Marshaler conformance
+
+func (sm SealedMessage) MarshalABI(e *abi.Encoder) error {
+	err := sm.From.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint64(sm.Nonce)
+	if err != nil {
+		return err
+	}
+	err = sm.Ciphertext.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return e.WriteUint32(sm.Checksum)
+}
+
+// abi.Unmarshaler conformance
+
+func (sm *SealedMessage) UnmarshalABI(d *abi.Decoder) error {
+	err := sm.From.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	sm.Nonce, err = d.ReadUint64()
+	if err != nil {
+		return err
+	}
+	err = sm.Ciphertext.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	sm.Checksum, err = d.ReadUint32()
+	return err
+}
+
+// helpers
+
+// Derive the aes cipher and iv from sha512(nonce || shared secret), the checksum is the
+// first four bytes of sha256 of the derived key read as a little endian uint32.
+func messageCipher(pk PrivateKey, pub PublicKey, nonce uint64) (cipher.Block, []byte, uint32, error) {
+	secret, err := pk.SharedSecret(pub)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	h := sha512.New()
+	binary.Write(h, binary.LittleEndian, nonce)
+	h.Write(secret[:])
+	key := h.Sum(nil)
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	check := sha256.Sum256(key)
+	return block, key[32:48], binary.LittleEndian.Uint32(check[:4]), nil
+}
@@ -0,0 +1,81 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestMessageEncryption(t *testing.T) {
+	alice, _ := chain.NewPrivateKeyFromString("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	bob, _ := chain.NewPrivateKeyFromString("PVT_K1_z8KBsKppZhw8yhtTrGkkMiYiWqvWXTf6Z5tiM9ng6cqNFKmXy")
+	alicePub, _ := alice.PublicKey()
+	bobPub, _ := bob.PublicKey()
+	message := []byte("hello bob, this is a secret message")
+
+	ciphertext, checksum, err := alice.Encrypt(*bobPub, 42, message)
+	assert.NoError(t, err)
+	assert.Equal(t, ciphertext.Hex(), "30a462d86467d7715172abbf24fc5417329a1e7298c1d4ddf6628aab81892f3985c8d136b6508a13974a13f6da2ad33d")
+	assert.Equal(t, checksum, uint32(1883661368))
+
+	decrypted, err := bob.Decrypt(*alicePub, 42, ciphertext, checksum)
+	assert.NoError(t, err)
+	assert.Equal(t, decrypted, chain.Bytes(message))
+
+	_, err = bob.Decrypt(*alicePub, 43, ciphertext, checksum)
+	assert.Equal(t, err, chain.ErrMessageChecksum)
+	_, err = alice.Decrypt(*alicePub, 42, ciphertext, checksum)
+	assert.Equal(t, err, chain.ErrMessageChecksum)
+}
+
+func TestMessageEncryptionVector(t *testing.T) {
+	// computed outside of this package with the eosjs-ecc Aes.encrypt steps implemented on the node
+	// crypto module: sha512 of the ecdh x coordinate, sha512(nonce || secret) as key and iv and the
+	// first four bytes of its sha256 as checksum
+	alice, _ := chain.NewPrivateKeyFromString("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	bob, _ := chain.NewPrivateKeyFromString("PVT_K1_z8KBsKppZhw8yhtTrGkkMiYiWqvWXTf6Z5tiM9ng6cqNFKmXy")
+	alicePub, _ := alice.PublicKey()
+	bobPub, _ := bob.PublicKey()
+	nonce := uint64(0xfedcba9876543210)
+	message := "Grüße an Alice! 🔐 sealed message with a nonce using all eight bytes"
+	ciphertext := mustDecodeHex("409f0eb5e260becbe3db57467b58f14a7adedd191d76bae0627df607254b95b87abf3f04c031a5d0f19de91e65a0f77ae0c5339b8a285a319180c2c9e62c45af6eef9bb5d395be894c16cb237d9c79b9")
+	checksum := uint32(2524830257)
+
+	decrypted, err := alice.Decrypt(*bobPub, nonce, ciphertext, checksum)
+	assert.NoError(t, err)
+	assert.Equal(t, string(decrypted), message)
+	encrypted, sum, err := bob.Encrypt(*alicePub, nonce, []byte(message))
+	assert.NoError(t, err)
+	assert.Equal(t, encrypted, chain.Bytes(ciphertext))
+	assert.Equal(t, sum, checksum)
+}
+
+func TestSealedMessage(t *testing.T) {
+	alice, _ := chain.NewPrivateKeyFromString("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	bob, _ := chain.NewPrivateKeyFromString("PVT_K1_z8KBsKppZhw8yhtTrGkkMiYiWqvWXTf6Z5tiM9ng6cqNFKmXy")
+	bobPub, _ := bob.PublicKey()
+
+	sealed, err := chain.SealMessage([]byte("hi"), *alice, *bobPub)
+	assert.NoError(t, err)
+	assert.Equal(t, sealed.From.String(), "PUB_K1_7zsqi7QUAjTAdyynd6DVe8uv4K8gCTRHnAoMN9w9CA1xKwj6Qn")
+	unsealed, err := sealed.Unseal(*bob)
+	assert.NoError(t, err)
+	assert.Equal(t, string(unsealed), "hi")
+
+	fixed := chain.SealedMessage{
+		From:       sealed.From,
+		Nonce:      42,
+		Ciphertext: mustDecodeHex("30a462d86467d7715172abbf24fc5417329a1e7298c1d4ddf6628aab81892f3985c8d136b6508a13974a13f6da2ad33d"),
+		Checksum:   1883661368,
+	}
+	assert.ABICoding(t, fixed, mustDecodeHex(
+		"00039997a497d964fc1a62885b05a51166a65a90df00492c8d7cf61d6accf54803be"+
+			"2a00000000000000"+
+			"30"+"30a462d86467d7715172abbf24fc5417329a1e7298c1d4ddf6628aab81892f3985c8d136b6508a13974a13f6da2ad33d"+
+			"38644670",
+	))
+	unsealed, err = fixed.Unseal(*bob)
+	assert.NoError(t, err)
+	assert.Equal(t, string(unsealed), "hello bob, this is a secret message")
+}
@@ -1,18 +1,65 @@
 package chain
 
-import "github.com/greymass/go-eosio/pkg/abi"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+var (
+	ErrInvalidName         = errors.New("invalid name")
+	ErrInvalidAccountName  = errors.New("invalid account name")
+	ErrPremiumName         = errors.New("premium names can only be created by the highest bidder of the name auction")
+	ErrSuffixCreatorOnly   = errors.New("only suffix may create this account")
+	ErrReservedAccountName = errors.New("only privileged accounts can have names that start with 'eosio.'")
+)
 
 // Type representing an EOSIO name.
 type Name uint64
 
-// Create a new name from a string, e.g. "teamgreymass".
+// Name that is validated with NewNameFromString when unmarshaled from text or JSON, invalid
+// names are rejected instead of having their invalid characters replaced with dots and being
+// truncated. The binary encoding is the same as Name.
+type StrictName Name
+
+// Create a new name from a string, e.g. "teamgreymass". Invalid characters are replaced by
+// dots and names longer than 13 characters are truncated, see NewNameFromString.
 func NewName(s string) Name {
 	return Name(stringToName(s))
 }
 
-// Convenience for NewName.
+// Create a new name from a string, returning an error if it contains characters other than
+// a-z, 1-5 and dots, is longer than 13 characters or is not normalized, i.e. ends with a dot.
+// The 13th character can only be a-j, 1-5 or a dot.
+func NewNameFromString(s string) (Name, error) {
+	if len(s) > 13 {
+		return 0, fmt.Errorf("%w: %q is longer than 13 characters", ErrInvalidName, s)
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if i == 12 && c > 'j' {
+			return 0, fmt.Errorf("%w: 13th character of %q must be a-j, 1-5 or a dot", ErrInvalidName, s)
+		}
+		if c != '.' && !(c >= 'a' && c <= 'z') && !(c >= '1' && c <= '5') {
+			return 0, fmt.Errorf("%w: %q contains invalid character %q", ErrInvalidName, s, c)
+		}
+	}
+	if len(s) > 0 && s[len(s)-1] == '.' {
+		return 0, fmt.Errorf("%w: %q ends with a dot", ErrInvalidName, s)
+	}
+	return Name(stringToName(s)), nil
+}
+
+// Convenience for NewNameFromString that panics if the name is invalid.
 func N(s string) Name {
-	return Name(stringToName(s))
+	n, err := NewNameFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
 }
 
 // Returns string representation of the name.
@@ -20,12 +67,131 @@ func (n Name) String() string {
 	return nameToString(uint64(n))
 }
 
+func (n StrictName) String() string {
+	return Name(n).String()
+}
+
+// Returns true if the name can be used as an account name, i.e. it is not empty and at most 12 characters long.
+func (n Name) IsValidAccountName() bool {
+	return n != 0 && n&0x0f == 0
+}
+
+// Number of characters in the name, not counting trailing dots.
+func (n Name) Length() int {
+	for i := 0; i < 13; i++ {
+		if n<<(5*i) == 0 {
+			return i
+		}
+	}
+	return 13
+}
+
+// Return the part of the name after the last dot, or the name itself if it has no dots,
+// e.g. "alice.bob" -> "bob". Same as name::suffix() in the contract development kit.
+func (n Name) Suffix() Name {
+	var remainingBitsAfterLastDot, tmp uint
+	for remainingBits := 59; remainingBits >= 4; remainingBits -= 5 {
+		if (n>>remainingBits)&0x1f == 0 {
+			tmp = uint(remainingBits)
+		} else {
+			remainingBitsAfterLastDot = tmp
+		}
+	}
+	thirteenth := n & 0x0f
+	if thirteenth != 0 {
+		remainingBitsAfterLastDot = tmp
+	}
+	if remainingBitsAfterLastDot == 0 {
+		return n
+	}
+	mask := Name(1)<<remainingBitsAfterLastDot - 16
+	shift := 64 - remainingBitsAfterLastDot
+	return (n&mask)<<shift + thirteenth<<(shift-1)
+}
+
+// Return the part of the name before the last dot, or the name itself if it has no dots,
+// e.g. "alice.bob" -> "alice". Same as name::prefix() in the contract development kit.
+func (n Name) Prefix() Name {
+	notDotSeen := false
+	mask := Name(0x0f)
+	for offset := 0; offset <= 59; {
+		if (n>>offset)&mask == 0 {
+			if notDotSeen {
+				return (n >> offset) << offset
+			}
+		} else {
+			notDotSeen = true
+		}
+		if offset == 0 {
+			offset += 4
+			mask = 0x1f
+		} else {
+			offset += 5
+		}
+	}
+	return n
+}
+
+// Return the account that is allowed to create this name, e.g. "bob" for "alice.bob".
+// Returns false if the name has no suffix.
+func (n Name) Parent() (Name, bool) {
+	suffix := n.Suffix()
+	if suffix == n {
+		return 0, false
+	}
+	return suffix, true
+}
+
+// Returns true if the name can only be created through the system contract name auction,
+// i.e. account names shorter than 12 characters without a dot.
+func (n Name) IsPremium() bool {
+	return n.IsValidAccountName() && !n.isRegular() && n.Suffix() == n
+}
+
+// Check if the creator is allowed to create an account with this name according to the rules of the
+// native newaccount action and the eosio.system contract: account names can be at most 12 characters,
+// names starting with "eosio." are reserved, premium names must be won in the name auction and names
+// with a dot can only be created by their suffix. The system account may create any name. Whether the
+// creator won the auction of a premium name is not checked, an ErrPremiumName error is returned instead.
+func (n Name) CheckCreator(creator Name) error {
+	if !n.IsValidAccountName() {
+		return fmt.Errorf("%w: %q", ErrInvalidAccountName, n.String())
+	}
+	if creator == systemAccount {
+		return nil
+	}
+	if strings.HasPrefix(n.String(), "eosio.") {
+		return ErrReservedAccountName
+	}
+	if n.isRegular() {
+		return nil
+	}
+	suffix := n.Suffix()
+	if suffix == n {
+		return ErrPremiumName
+	}
+	if creator != suffix {
+		return fmt.Errorf("%w: %s can only be created by %s", ErrSuffixCreatorOnly, n.String(), suffix.String())
+	}
+	return nil
+}
+
+// Sort names by their uint64 value, the order used by nodeos for table keys and maps. For valid names
+// this is the same order as sorting the names alphabetically by their string representation.
+func SortNames(names []Name) {
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+}
+
 // abi.Marshaler conformance
 
 func (n Name) MarshalABI(e *abi.Encoder) error {
 	return e.WriteUint64(uint64(n))
 }
 
+func (n StrictName) MarshalABI(e *abi.Encoder) error {
+	return Name(n).MarshalABI(e)
+}
+
 // abi.Unmarshaler conformance
 
 func (n *Name) UnmarshalABI(d *abi.Decoder) error {
@@ -36,12 +202,20 @@ func (n *Name) UnmarshalABI(d *abi.Decoder) error {
 	return err
 }
 
+func (n *StrictName) UnmarshalABI(d *abi.Decoder) error {
+	return (*Name)(n).UnmarshalABI(d)
+}
+
 // encoding.TextMarshaler conformance
 
 func (n Name) MarshalText() (text []byte, err error) {
 	return []byte(n.String()), nil
 }
 
+func (n StrictName) MarshalText() (text []byte, err error) {
+	return Name(n).MarshalText()
+}
+
 // encoding.TextUnmarshaler conformance
 
 func (n *Name) UnmarshalText(text []byte) error {
@@ -50,6 +224,30 @@ func (n *Name) UnmarshalText(text []byte) error {
 	return nil
 }
 
+func (n *StrictName) UnmarshalText(text []byte) error {
+	new, err := NewNameFromString(string(text))
+	if err == nil {
+		*n = StrictName(new)
+	}
+	return err
+}
+
+// helpers
+
+var systemAccount = Name(stringToName("eosio"))
+
+// 12 character names without dots can be created by any account
+func (n Name) isRegular() bool {
+	tmp := n >> 4
+	for i := 0; i < 12; i++ {
+		if tmp&0x1f == 0 {
+			return false
+		}
+		tmp >>= 5
+	}
+	return true
+}
+
 // from https://github.com/eoscanada/eos-go/blob/21697b8969f6446181086db27ae7e7a302bf166d/name.go
 
 var base32Alphabet = []byte(".12345abcdefghijklmnopqrstuvwxyz")
@@ -1,7 +1,11 @@
 package chain_test
 
 import (
+	"encoding/json"
+	"errors"
+	"math/rand"
 	"regexp"
+	"sort"
 	"strings"
 	"testing"
 
@@ -21,6 +25,132 @@ func TestName(t *testing.T) {
 	assert.ABICoding(t, chain.Name(14595364149838066048), []byte{0x80, 0xb1, 0x91, 0x5e, 0x5d, 0x26, 0x8d, 0xca})
 }
 
+func TestNewNameFromString(t *testing.T) {
+	valid := []string{"", "teamgreymass", "eosio.token", "a", "1.2.3.4.5", "............1", "zzzzzzzzzzzzj"}
+	for _, v := range valid {
+		n, err := chain.NewNameFromString(v)
+		assert.NoError(t, err)
+		assert.Equal(t, n.String(), v)
+		assert.Equal(t, chain.N(v), n)
+	}
+	invalid := []string{"Alice", "toolongaccountname", "invålid", "foo bar", "zzzzzzzzzzzzk", "bob6", "foo.", "."}
+	for _, v := range invalid {
+		_, err := chain.NewNameFromString(v)
+		assert.True(t, errors.Is(err, chain.ErrInvalidName))
+	}
+	assert.Equal(t, panics(func() { chain.N("Alice") }), true)
+}
+
+func TestNameIsValidAccountName(t *testing.T) {
+	assert.True(t, chain.N("teamgreymass").IsValidAccountName())
+	assert.True(t, chain.N("a").IsValidAccountName())
+	assert.True(t, !chain.N("").IsValidAccountName())
+	assert.True(t, !chain.N("zzzzzzzzzzzzj").IsValidAccountName())
+}
+
+func TestStrictNames(t *testing.T) {
+	var n chain.Name
+	assert.NoError(t, json.Unmarshal([]byte(`"Alice"`), &n))
+	assert.Equal(t, n.String(), ".lice")
+
+	var sn chain.StrictName
+	assert.NoError(t, json.Unmarshal([]byte(`"alice"`), &sn))
+	assert.Equal(t, chain.Name(sn), chain.N("alice"))
+	assert.Equal(t, sn.String(), "alice")
+	err := json.Unmarshal([]byte(`"Alice"`), &sn)
+	assert.True(t, errors.Is(err, chain.ErrInvalidName))
+	var transfer struct {
+		From chain.StrictName `json:"from"`
+		To   chain.StrictName `json:"to"`
+	}
+	err = json.Unmarshal([]byte(`{"from": "alice", "to": "toolongaccountname"}`), &transfer)
+	assert.True(t, errors.Is(err, chain.ErrInvalidName))
+	assert.True(t, errors.Is(sn.UnmarshalText([]byte("foo.")), chain.ErrInvalidName))
+	assert.JSONCoding(t, chain.StrictName(chain.N("teamgreymass")), `"teamgreymass"`)
+	assert.ABICoding(t, chain.StrictName(chain.N("teamgreymass")), []byte{0x80, 0xb1, 0x91, 0x5e, 0x5d, 0x26, 0x8d, 0xca})
+}
+
+func TestNameSuffixPrefix(t *testing.T) {
+	vectors := [][3]string{
+		// name, suffix, prefix
+		{"", "", ""},
+		{"eosioaccountj", "eosioaccountj", "eosioaccountj"},
+		{".eosioaccounj", "eosioaccounj", ""},
+		{"e.osioaccounj", "osioaccounj", "e"},
+		{"eos.ioaccounj", "ioaccounj", "eos"},
+		{"eosioaccou.nj", "nj", "eosioaccou"},
+		{"eosioaccoun.j", "j", "eosioaccoun"},
+		{"e.o.s.i.o.a.c", "c", "e.o.s.i.o.a"},
+		{"eos.ioa.cco", "cco", "eos.ioa"},
+		{"alice.bob", "bob", "alice"},
+		{"alice", "alice", "alice"},
+	}
+	for _, v := range vectors {
+		n := chain.N(v[0])
+		assert.Equal(t, n.Suffix().String(), v[1])
+		assert.Equal(t, n.Prefix().String(), v[2])
+		assert.Equal(t, n.Length(), len(v[0]))
+	}
+
+	parent, ok := chain.N("alice.bob").Parent()
+	assert.True(t, ok)
+	assert.Equal(t, parent, chain.N("bob"))
+	_, ok = chain.N("alice").Parent()
+	assert.True(t, !ok)
+}
+
+func TestNameCheckCreator(t *testing.T) {
+	assert.True(t, chain.N("alice").IsPremium())
+	assert.True(t, !chain.N("aliceaccount").IsPremium())
+	assert.True(t, !chain.N("alice.bob").IsPremium())
+	assert.True(t, !chain.N("").IsPremium())
+
+	vectors := []struct {
+		name    string
+		creator string
+		err     error
+	}{
+		{"aliceaccount", "bob", nil},
+		{"alice.bob", "bob", nil},
+		{"a.b.c", "c", nil},
+		{"alice", "eosio", nil},
+		{"eosio.foo", "eosio", nil},
+		{"alice", "bob", chain.ErrPremiumName},
+		{"alice.bob", "carol", chain.ErrSuffixCreatorOnly},
+		{"alice.bob", "alice", chain.ErrSuffixCreatorOnly},
+		{"eosio.foo", "foo", chain.ErrReservedAccountName},
+		{"zzzzzzzzzzzzj", "eosio", chain.ErrInvalidAccountName},
+		{"", "eosio", chain.ErrInvalidAccountName},
+	}
+	for _, v := range vectors {
+		err := chain.N(v.name).CheckCreator(chain.N(v.creator))
+		if v.err == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.True(t, errors.Is(err, v.err))
+		}
+	}
+}
+
+func TestSortNames(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	names := make([]chain.Name, 1000)
+	strs := make([]string, len(names))
+	for i := range names {
+		// shorter names and names with leading dots
+		names[i] = chain.Name(rnd.Uint64() << (rnd.Intn(13) * 5))
+		if i%2 == 0 {
+			names[i] >>= rnd.Intn(13) * 5
+		}
+		strs[i] = names[i].String()
+	}
+	chain.SortNames(names)
+	sort.Strings(strs)
+	for i := range names {
+		assert.Equal(t, names[i].String(), strs[i])
+	}
+}
+
 func FuzzName(f *testing.F) {
 	f.Add("")
 	f.Add(" ")
@@ -36,6 +166,9 @@ func FuzzName(f *testing.F) {
 		if !re.MatchString(actual) || strings.HasSuffix(actual, ".") {
 			t.Errorf("%q is not a valid name", orig)
 		}
+		if strict, err := chain.NewNameFromString(orig); err == nil && (strict != name || actual != orig) {
+			t.Errorf("%q accepted but does not round trip", orig)
+		}
 	})
 }
 
@@ -51,3 +184,13 @@ func BenchmarkStringFromName(b *testing.B) {
 		_ = n1.String()
 	}
 }
+
+// helpers
+
+func panics(fn func()) (rv bool) {
+	defer func() {
+		rv = recover() != nil
+	}()
+	fn()
+	return false
+}
@@ -2,7 +2,6 @@ package chain
 
 import (
 	"encoding/binary"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"math"
@@ -19,10 +18,6 @@ type Uint128 struct {
 
 type Int128 Uint128
 
-type Float128 struct {
-	Data [16]byte // not sure what the best way to represent this is, abieos seems to use a hex string ¯\_(ツ)_/¯
-}
-
 // uint64 alias that encodes to string for values above 32bit instead of scientific notation in JSON
 type Uint64 uint64
 
@@ -136,10 +131,6 @@ func (i128 Int128) MarshalABI(e *abi.Encoder) error {
 	return e.WriteBytes(i128.Bytes(binary.LittleEndian))
 }
 
-func (f128 Float128) MarshalABI(e *abi.Encoder) error {
-	return e.WriteBytes(f128.Data[:])
-}
-
 func (u64 Uint64) MarshalABI(e *abi.Encoder) error {
 	return e.WriteUint64(uint64(u64))
 }
@@ -166,14 +157,6 @@ func (i128 *Int128) UnmarshalABI(d *abi.Decoder) error {
 	return (*Uint128)(i128).UnmarshalABI(d)
 }
 
-func (f128 *Float128) UnmarshalABI(d *abi.Decoder) error {
-	_, b, err := d.ReadBytes(16)
-	if err == nil {
-		copy(f128.Data[:], b)
-	}
-	return err
-}
-
 func (u64 *Uint64) UnmarshalABI(d *abi.Decoder) error {
 	v, err := d.ReadUint64()
 	if err == nil {
@@ -200,10 +183,6 @@ func (i128 Int128) MarshalText() (text []byte, err error) {
 	return []byte(i128.String()), nil
 }
 
-func (f128 Float128) MarshalText() (text []byte, err error) {
-	return []byte(hex.EncodeToString(f128.Data[:])), nil
-}
-
 // encoding.TextUnmarshaler conformance
 
 func (u128 *Uint128) UnmarshalText(text []byte) error {
@@ -218,14 +197,6 @@ func (i128 *Int128) UnmarshalText(text []byte) error {
 	return err
 }
 
-func (f128 *Float128) UnmarshalText(text []byte) error {
-	b, err := hex.DecodeString(string(text))
-	if err == nil {
-		copy(f128.Data[:], b)
-	}
-	return err
-}
-
 // json.Marshaler conformance
 
 func (u64 Uint64) MarshalJSON() ([]byte, error) {
@@ -115,17 +115,6 @@ func TestUint64(t *testing.T) {
 	assert.JSONCoding(t, chain.Uint64(4294967296), `"4294967296"`)
 }
 
-func TestFloat128(t *testing.T) {
-	f1 := chain.Float128{
-		Data: [16]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
-	}
-	assert.JSONCoding(t, f1, `"00000000000000000000000000000000"`)
-	f2 := chain.Float128{
-		Data: [16]byte{0x12, 0x34, 0x56, 0x78, 0xab, 0xcd, 0xef, 0x12, 0x34, 0x56, 0x78, 0xab, 0xcd, 0xef, 0x12, 0x34},
-	}
-	assert.JSONCoding(t, f2, `"12345678abcdef12345678abcdef1234"`)
-}
-
 func TestBlockNum(t *testing.T) {
 	bn := chain.BlockNum(0)
 	assert.JSONCoding(t, bn, `0`)
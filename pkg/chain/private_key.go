@@ -0,0 +1,203 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/base58"
+)
+
+type PrivateKey struct {
+	Type KeyType
+	Data []byte
+}
+
+func NewPrivateKey(t KeyType, d []byte) *PrivateKey {
+	return &PrivateKey{
+		Type: t,
+		Data: d,
+	}
+}
+
+// Create new private key from string, e.g. PVT_K1_... or a legacy WIF key.
+func NewPrivateKeyFromString(s string) (*PrivateKey, error) {
+	if len(s) < 8 {
+		return nil, errors.New("invalid private key string")
+	}
+	if s[0:4] == "PVT_" {
+		// new format
+		var t KeyType
+		switch s[4:6] {
+		case "K1":
+			t = K1
+		case "P1":
+			t = P1
+		default:
+			return nil, fmt.Errorf("unknown key type: %s", s[4:6])
+		}
+		d, err := base58.CheckDecodeEosio(s[7:], t.String())
+		if err != nil {
+			return nil, err
+		}
+		if len(d) != 32 {
+			return nil, errors.New("invalid private key length")
+		}
+		return &PrivateKey{
+			Type: t,
+			Data: d,
+		}, nil
+	}
+	// legacy wif format
+	d, err := base58.CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(d) != 33 || d[0] != 0x80 {
+		return nil, errors.New("invalid legacy private key")
+	}
+	return &PrivateKey{
+		Type: K1,
+		Data: d[1:],
+	}, nil
+}
+
+func (pk PrivateKey) String() string {
+	return "PVT_" + pk.Type.String() + "_" + base58.CheckEncodeEosio(pk.Data, pk.Type.String())
+}
+
+// panics if key type isn't k1
+func (pk PrivateKey) LegacyString() string {
+	if pk.Type != K1 {
+		panic("only K1 keys can be converted to legacy format")
+	}
+	return base58.CheckEncode(append([]byte{0x80}, pk.Data...))
+}
+
+// Derive the public key, only K1 keys are supported.
+func (pk PrivateKey) PublicKey() (*PublicKey, error) {
+	priv, err := pk.k1()
+	if err != nil {
+		return nil, err
+	}
+	return NewPublicKey(K1, priv.PubKey().SerializeCompressed()), nil
+}
+
+// Compute the ECDH shared secret with the owner of given public key, the sha512 digest of the x coordinate
+// of the shared point. Only K1 keys are supported.
+func (pk PrivateKey) SharedSecret(pub PublicKey) (Checksum512, error) {
+	priv, err := pk.k1()
+	if err != nil {
+		return Checksum512{}, err
+	}
+	if pub.Type != K1 {
+		return Checksum512{}, fmt.Errorf("unable to compute shared secret with %s key", pub.Type)
+	}
+	key, err := secp256k1.ParsePubKey(pub.Data)
+	if err != nil {
+		return Checksum512{}, err
+	}
+	return Checksum512Digest(secp256k1.GenerateSharedSecret(priv, key)), nil
+}
+
+// Sign a digest, producing a canonical signature with an embedded recovery id. Only K1 keys are supported.
+func (pk PrivateKey) SignDigest(digest Checksum256) (*Signature, error) {
+	priv, err := pk.k1()
+	if err != nil {
+		return nil, err
+	}
+	var e secp256k1.ModNScalar
+	e.SetByteSlice(digest[:])
+	for i := uint32(0); ; i++ {
+		k := secp256k1.NonceRFC6979(pk.Data, digest[:], nil, nil, i)
+		var R secp256k1.JacobianPoint
+		secp256k1.ScalarBaseMultNonConst(k, &R)
+		R.ToAffine()
+		var r secp256k1.ModNScalar
+		overflow := r.SetByteSlice(R.X.Bytes()[:])
+		if r.IsZero() {
+			continue
+		}
+		recovery := byte(R.Y.IsOddBit())
+		if overflow {
+			recovery |= 2
+		}
+		kinv := new(secp256k1.ModNScalar).InverseValNonConst(k)
+		s := new(secp256k1.ModNScalar).Mul2(&priv.Key, &r).Add(&e).Mul(kinv)
+		if s.IsZero() {
+			continue
+		}
+		if s.IsOverHalfOrder() {
+			s.Negate()
+			recovery ^= 1
+		}
+		// 27 + 4 (compressed public key) + recovery id, followed by r and s
+		data := make([]byte, 65)
+		data[0] = 31 + recovery
+		r.PutBytesUnchecked(data[1:33])
+		s.PutBytesUnchecked(data[33:65])
+		if isCanonical(data) {
+			return NewSignature(K1, data), nil
+		}
+	}
+}
+
+// abi.Marshaler conformance
+
+func (pk PrivateKey) MarshalABI(e *abi.Encoder) error {
+	err := e.WriteByte(byte(pk.Type))
+	if err != nil {
+		return err
+	}
+	return e.WriteBytes(pk.Data)
+}
+
+// abi.Unmarshaler conformance
+
+func (pk *PrivateKey) UnmarshalABI(d *abi.Decoder) error {
+	t, err := d.ReadByte()
+	if err != nil {
+		return err
+	}
+	pk.Type = KeyType(t)
+	_, pk.Data, err = d.ReadBytes(32)
+	return err
+}
+
+// encoding.TextMarshaler conformance
+
+func (pk PrivateKey) MarshalText() (text []byte, err error) {
+	return []byte(pk.String()), nil
+}
+
+// encoding.TextUnmarshaler conformance
+
+func (pk *PrivateKey) UnmarshalText(text []byte) error {
+	new, err := NewPrivateKeyFromString(string(text))
+	if err == nil {
+		*pk = *new
+	}
+	return err
+}
+
+// helpers
+
+func (pk PrivateKey) k1() (*secp256k1.PrivateKey, error) {
+	if pk.Type != K1 {
+		return nil, fmt.Errorf("unsupported private key type: %s", pk.Type)
+	}
+	if len(pk.Data) != 32 {
+		return nil, errors.New("invalid private key length")
+	}
+	return secp256k1.PrivKeyFromBytes(pk.Data), nil
+}
+
+// canonical signatures have r and s values that are exactly 32 bytes long when
+// encoded as DER integers, i.e. no leading zero byte or high bit set
+func isCanonical(sig []byte) bool {
+	return sig[1]&0x80 == 0 &&
+		!(sig[1] == 0 && sig[2]&0x80 == 0) &&
+		sig[33]&0x80 == 0 &&
+		!(sig[33] == 0 && sig[34]&0x80 == 0)
+}
@@ -0,0 +1,68 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestPrivateKey(t *testing.T) {
+	k, err := chain.NewPrivateKeyFromString("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	assert.NoError(t, err)
+	assert.Equal(t, k.Type, chain.K1)
+	assert.Equal(t, k.String(), "PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	assert.Equal(t, k.LegacyString(), "5J9bWm2ThenDm3tjvmUgHtWCVMUdjRR1pxnRtnJjvKA4b2ut5WK")
+	pub, err := k.PublicKey()
+	assert.NoError(t, err)
+	assert.Equal(t, pub.String(), "PUB_K1_7zsqi7QUAjTAdyynd6DVe8uv4K8gCTRHnAoMN9w9CA1xKwj6Qn")
+
+	legacy, err := chain.NewPrivateKeyFromString("5J9bWm2ThenDm3tjvmUgHtWCVMUdjRR1pxnRtnJjvKA4b2ut5WK")
+	assert.NoError(t, err)
+	assert.Equal(t, legacy, k)
+
+	_, err = chain.NewPrivateKeyFromString("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkV")
+	assert.NotNil(t, err)
+	_, err = chain.NewPrivateKeyFromString("5J9bWm2ThenDm3tjvmUgHtWCVMUdjRR1pxnRtnJjvKA4b2ut5WX")
+	assert.NotNil(t, err)
+
+	assert.JSONCoding(t, *k, `"PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU"`)
+}
+
+func TestSharedSecret(t *testing.T) {
+	alice, _ := chain.NewPrivateKeyFromString("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	bob, _ := chain.NewPrivateKeyFromString("PVT_K1_z8KBsKppZhw8yhtTrGkkMiYiWqvWXTf6Z5tiM9ng6cqNFKmXy")
+	alicePub, _ := alice.PublicKey()
+	bobPub, _ := bob.PublicKey()
+	s1, err := alice.SharedSecret(*bobPub)
+	assert.NoError(t, err)
+	s2, err := bob.SharedSecret(*alicePub)
+	assert.NoError(t, err)
+	assert.Equal(t, s1, s2)
+	assert.Equal(t, s1.String(), "a71b4ec5a9577926a1d2aa1d9d99327fd3b68f6a1ea597200a0d890bd3331df300a2d49fec0b2b3e6969ce9263c5d6cf47c191c1ef149373ecc9f0d98116b598")
+}
+
+func TestSignDigest(t *testing.T) {
+	k, _ := chain.NewPrivateKeyFromString("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	pub, _ := k.PublicKey()
+	for i := 0; i < 32; i++ {
+		digest := chain.Checksum256Digest([]byte{byte(i)})
+		sig, err := k.SignDigest(digest)
+		assert.NoError(t, err)
+		assert.Equal(t, sig.Type, chain.K1)
+		// r and s must not need padding in der encoding
+		assert.True(t, sig.Data[1]&0x80 == 0 && sig.Data[33]&0x80 == 0)
+		recovered, err := sig.RecoverDigest(digest)
+		assert.NoError(t, err)
+		assert.Equal(t, recovered.String(), pub.String())
+	}
+	// deterministic rfc6979 nonce
+	digest := chain.Checksum256Digest([]byte("hello"))
+	sig1, _ := k.SignDigest(digest)
+	sig2, _ := k.SignDigest(digest)
+	assert.Equal(t, sig1.String(), sig2.String())
+
+	p1 := chain.NewPrivateKey(chain.P1, make([]byte, 32))
+	_, err := p1.SignDigest(digest)
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -64,6 +65,10 @@ func (pk *PublicKey) LegacyString(prefix string) string {
 	return prefix + base58.CheckEncode(pk.Data)
 }
 
+func (pk PublicKey) Equal(other PublicKey) bool {
+	return pk.Type == other.Type && bytes.Equal(pk.Data, other.Data)
+}
+
 // abi.Marshaler conformance
 
 func (pk PublicKey) MarshalABI(e *abi.Encoder) error {
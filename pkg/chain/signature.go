@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"github.com/greymass/go-eosio/pkg/abi"
 	"github.com/greymass/go-eosio/pkg/base58"
 )
@@ -44,10 +45,25 @@ func NewSignatureString(s string) (*Signature, error) {
 
 }
 
-func (pk *Signature) String() string {
+func (pk Signature) String() string {
 	return "SIG_" + pk.Type.String() + "_" + base58.CheckEncodeEosio(pk.Data, pk.Type.String())
 }
 
+// Recover the public key used to create the signature for given digest, only K1 signatures are supported.
+func (s Signature) RecoverDigest(digest Checksum256) (*PublicKey, error) {
+	if s.Type != K1 {
+		return nil, fmt.Errorf("unable to recover public key from %s signature", s.Type)
+	}
+	if len(s.Data) != 65 {
+		return nil, errors.New("invalid signature length")
+	}
+	pub, _, err := ecdsa.RecoverCompact(s.Data, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	return NewPublicKey(K1, pub.SerializeCompressed()), nil
+}
+
 // abi.Marshaler conformance
 
 func (s Signature) MarshalABI(e *abi.Encoder) error {
@@ -107,7 +123,7 @@ func (s *Signature) UnmarshalABI(d *abi.Decoder) error {
 
 // encoding.TextMarshaler conformance
 
-func (s *Signature) MarshalText() (text []byte, err error) {
+func (s Signature) MarshalText() (text []byte, err error) {
 	return []byte(s.String()), nil
 }
 
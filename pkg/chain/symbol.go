@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -9,10 +10,22 @@ import (
 	"github.com/greymass/go-eosio/pkg/abi"
 )
 
+var (
+	ErrInvalidSymbol          = errors.New("invalid symbol")
+	ErrInvalidSymbolPrecision = errors.New("invalid symbol precision")
+	ErrInvalidSymbolCode      = errors.New("invalid symbol code")
+)
+
 type Symbol uint64
 
 type SymbolCode uint64
 
+// Symbol together with the contract that issued it, e.g. "4,EOS@eosio.token"
+type ExtendedSymbol struct {
+	Symbol   Symbol `json:"sym"`
+	Contract Name   `json:"contract"`
+}
+
 // Create new symbol from precision and name.
 func NewSymbol(precision uint8, name string) (Symbol, error) {
 	v, err := rawSymbolValue(precision, name)
@@ -26,11 +39,11 @@ func NewSymbol(precision uint8, name string) (Symbol, error) {
 func NewSymbolFromString(s string) (Symbol, error) {
 	p := strings.Split(s, ",")
 	if len(p) != 2 {
-		return 0, errors.New("invalid asset symbol string")
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSymbol, s)
 	}
-	precision, err := strconv.ParseInt(p[0], 10, 8)
+	precision, err := strconv.ParseUint(p[0], 10, 8)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSymbolPrecision, p[0])
 	}
 	symbolValue, err := rawSymbolValue(uint8(precision), p[1])
 	if err != nil {
@@ -40,6 +53,32 @@ func NewSymbolFromString(s string) (Symbol, error) {
 	return Symbol(symbolValue), nil
 }
 
+// Create new symbol code from string, e.g. "EOS"
+func NewSymbolCode(s string) (SymbolCode, error) {
+	v, err := rawSymbolValue(0, s)
+	if err != nil {
+		return 0, err
+	}
+	return SymbolCode(v >> 8), nil
+}
+
+// Create new extended symbol from string, e.g. "4,EOS@eosio.token"
+func NewExtendedSymbolFromString(s string) (ExtendedSymbol, error) {
+	p := strings.Split(s, "@")
+	if len(p) != 2 {
+		return ExtendedSymbol{}, fmt.Errorf("%w: %q", ErrInvalidSymbol, s)
+	}
+	symbol, err := NewSymbolFromString(p[0])
+	if err != nil {
+		return ExtendedSymbol{}, err
+	}
+	contract, err := NewNameFromString(p[1])
+	if err != nil {
+		return ExtendedSymbol{}, err
+	}
+	return ExtendedSymbol{symbol, contract}, nil
+}
+
 // Asset symbol name, e.g. "EOS"
 func (s Symbol) Name() string {
 	v := s
@@ -78,6 +117,39 @@ func (s Symbol) String() string {
 	return fmt.Sprint(s.Decimals()) + "," + s.Name()
 }
 
+// Returns true if the symbol has a valid code and at most 18 decimals.
+func (s Symbol) IsValid() bool {
+	return s.Decimals() <= 18 && s.Code().IsValid()
+}
+
+// Returns true if the symbol code is 1-7 uppercase letters.
+func (sc SymbolCode) IsValid() bool {
+	if sc == 0 || sc>>56 != 0 {
+		return false
+	}
+	for v := sc; v > 0; v >>= 8 {
+		if c := byte(v); c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// String representation of the symbol code, e.g. "EOS"
+func (sc SymbolCode) String() string {
+	return Symbol(uint64(sc) << 8).Name()
+}
+
+// String representation of extended symbol, e.g. "4,EOS@eosio.token"
+func (es ExtendedSymbol) String() string {
+	return es.Symbol.String() + "@" + es.Contract.String()
+}
+
+// Returns true if the symbol is valid.
+func (es ExtendedSymbol) IsValid() bool {
+	return es.Symbol.IsValid()
+}
+
 // abi.Marshaler conformance
 
 func (s Symbol) MarshalABI(e *abi.Encoder) error {
@@ -88,6 +160,14 @@ func (sc SymbolCode) MarshalABI(e *abi.Encoder) error {
 	return e.WriteUint64(uint64(sc))
 }
 
+func (es ExtendedSymbol) MarshalABI(e *abi.Encoder) error {
+	err := es.Symbol.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return es.Contract.MarshalABI(e)
+}
+
 // abi.Unmarshaler conformance
 
 func (s *Symbol) UnmarshalABI(d *abi.Decoder) error {
@@ -106,6 +186,14 @@ func (sc *SymbolCode) UnmarshalABI(d *abi.Decoder) error {
 	return err
 }
 
+func (es *ExtendedSymbol) UnmarshalABI(d *abi.Decoder) error {
+	err := es.Symbol.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return es.Contract.UnmarshalABI(d)
+}
+
 // encoding.TextMarshaler conformance
 
 func (s Symbol) MarshalText() (text []byte, err error) {
@@ -113,8 +201,11 @@ func (s Symbol) MarshalText() (text []byte, err error) {
 }
 
 func (sc SymbolCode) MarshalText() (text []byte, err error) {
-	s := Symbol(uint64(sc) << 8)
-	return []byte(s.Name()), nil
+	return []byte(sc.String()), nil
+}
+
+func (es ExtendedSymbol) MarshalText() (text []byte, err error) {
+	return []byte(es.String()), nil
 }
 
 // encoding.TextUnmarshaler conformance
@@ -128,26 +219,59 @@ func (s *Symbol) UnmarshalText(text []byte) error {
 }
 
 func (sc *SymbolCode) UnmarshalText(text []byte) error {
-	new, err := NewSymbolFromString("0," + string(text))
+	new, err := NewSymbolCode(string(text))
 	if err == nil {
-		*sc = new.Code()
+		*sc = new
 	}
 	return err
 }
 
+// Accepts the "4,EOS@eosio.token" form.
+func (es *ExtendedSymbol) UnmarshalText(text []byte) error {
+	new, err := NewExtendedSymbolFromString(string(text))
+	if err == nil {
+		*es = new
+	}
+	return err
+}
+
+// json.Marshaler conformance
+
+// Encoded as {"sym": "4,EOS", "contract": "eosio.token"} like nodeos does.
+func (es ExtendedSymbol) MarshalJSON() ([]byte, error) {
+	type object ExtendedSymbol
+	return json.Marshal(object(es))
+}
+
+// json.Unmarshaler conformance
+
+// Accepts both the object and the "4,EOS@eosio.token" string form.
+func (es *ExtendedSymbol) UnmarshalJSON(b []byte) error {
+	type object ExtendedSymbol
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		err := json.Unmarshal(b, &s)
+		if err != nil {
+			return err
+		}
+		return es.UnmarshalText([]byte(s))
+	}
+	return json.Unmarshal(b, (*object)(es))
+}
+
 // helpers
 
 func rawSymbolValue(precision uint8, s string) (uint64, error) {
 	if precision > 18 {
-		return 0, errors.New("invalid symbol precision")
+		return 0, fmt.Errorf("%w: %d", ErrInvalidSymbolPrecision, precision)
 	}
-	if len(s) > 7 {
-		return 0, errors.New("invalid symbol name")
+	if len(s) == 0 || len(s) > 7 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidSymbolCode, s)
 	}
 	var rv uint64 = 0
 	for i := 0; i < len(s); i++ {
 		if !(s[i] >= 'A' && s[i] <= 'Z') {
-			return 0, errors.New("invalid character in symbol name")
+			return 0, fmt.Errorf("%w: %q", ErrInvalidSymbolCode, s)
 		}
 		rv |= (uint64(s[i]) << (8 * (i + 1)))
 	}
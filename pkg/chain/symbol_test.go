@@ -1,6 +1,9 @@
 package chain_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/greymass/go-eosio/internal/assert"
@@ -25,9 +28,106 @@ func TestSymbol(t *testing.T) {
 	_, err = chain.NewSymbolFromString("JERFERY,BERSOS")
 	assert.HasError(t, &err)
 
+	invalid := []struct {
+		s   string
+		err error
+	}{
+		{"4", chain.ErrInvalidSymbol},
+		{"4,EOS,", chain.ErrInvalidSymbol},
+		{"-1,XYZ", chain.ErrInvalidSymbolPrecision},
+		{"-255,XYZ", chain.ErrInvalidSymbolPrecision},
+		{"+4,XYZ", chain.ErrInvalidSymbolPrecision},
+		{"256,XYZ", chain.ErrInvalidSymbolPrecision},
+		{"19,XYZ", chain.ErrInvalidSymbolPrecision},
+		{",XYZ", chain.ErrInvalidSymbolPrecision},
+		{"4,", chain.ErrInvalidSymbolCode},
+		{"4,eos", chain.ErrInvalidSymbolCode},
+		{"4,E0S", chain.ErrInvalidSymbolCode},
+		{"4,ABCDEFGH", chain.ErrInvalidSymbolCode},
+	}
+	for _, v := range invalid {
+		_, err = chain.NewSymbolFromString(v.s)
+		assert.True(t, errors.Is(err, v.err))
+	}
+
 	assert.ABICoding(t, symbol, []byte{0x04, 0x42, 0x45, 0x5a, 0x4f, 0x53, 0x00, 0x00})
 	assert.JSONCoding(t, symbol, `"4,BEZOS"`)
 
 	assert.ABICoding(t, symbol.Code(), []byte{0x42, 0x45, 0x5a, 0x4f, 0x53, 0x00, 0x00, 0x00})
 	assert.JSONCoding(t, symbol.Code(), `"BEZOS"`)
+	assert.Equal(t, symbol.Code().String(), "BEZOS")
+}
+
+func TestSymbolCode(t *testing.T) {
+	code, err := chain.NewSymbolCode("EOS")
+	assert.NoError(t, err)
+	assert.Equal(t, code, chain.SymbolCode(0x534f45))
+	for _, s := range []string{"", "eos", "EOS ", "ABCDEFGH", "0,EOS"} {
+		_, err = chain.NewSymbolCode(s)
+		assert.True(t, errors.Is(err, chain.ErrInvalidSymbolCode))
+	}
+	var sc chain.SymbolCode
+	err = json.Unmarshal([]byte(`""`), &sc)
+	assert.True(t, errors.Is(err, chain.ErrInvalidSymbolCode))
+}
+
+func TestExtendedSymbol(t *testing.T) {
+	es, err := chain.NewExtendedSymbolFromString("4,EOS@eosio.token")
+	assert.NoError(t, err)
+	assert.Equal(t, es.Symbol, chain.A("1.0000 EOS").Symbol)
+	assert.Equal(t, es.Contract, chain.N("eosio.token"))
+	assert.Equal(t, es.String(), "4,EOS@eosio.token")
+	assert.True(t, es.IsValid())
+
+	data := []byte{0x04, 0x45, 0x4f, 0x53, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa6, 0x82, 0x34, 0x03, 0xea, 0x30, 0x55}
+	assert.ABICoding(t, es, data)
+	assert.JSONCoding(t, es, `{"sym": "4,EOS", "contract": "eosio.token"}`)
+
+	text, err := es.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, string(text), "4,EOS@eosio.token")
+	var es2 chain.ExtendedSymbol
+	assert.NoError(t, es2.UnmarshalText(text))
+	assert.Equal(t, es2, es)
+	var es3 chain.ExtendedSymbol
+	assert.NoError(t, json.Unmarshal([]byte(`"4,EOS@eosio.token"`), &es3))
+	assert.Equal(t, es3, es)
+	keyed := map[chain.ExtendedSymbol]int{es: 1}
+	assert.JSONCoding(t, keyed, `{"4,EOS@eosio.token": 1}`)
+
+	_, err = chain.NewExtendedSymbolFromString("4,EOS")
+	assert.True(t, errors.Is(err, chain.ErrInvalidSymbol))
+	_, err = chain.NewExtendedSymbolFromString("4,EOS@eosio@token")
+	assert.True(t, errors.Is(err, chain.ErrInvalidSymbol))
+	_, err = chain.NewExtendedSymbolFromString("4,eos@eosio.token")
+	assert.True(t, errors.Is(err, chain.ErrInvalidSymbolCode))
+	_, err = chain.NewExtendedSymbolFromString("4,EOS@EOSIO")
+	assert.True(t, errors.Is(err, chain.ErrInvalidName))
+
+	abi := chain.Abi{
+		Version: "eosio::abi/1.1",
+		Structs: []chain.AbiStruct{{Name: "open", Fields: []chain.AbiField{{Name: "sym", Type: "extended_symbol"}}}},
+		Actions: []chain.AbiAction{{Name: chain.N("open"), Type: "open"}},
+	}
+	assert.NoError(t, abi.Validate())
+	buf := bytes.NewBuffer(nil)
+	err = abi.Encode(buf, "open", map[string]interface{}{"sym": es})
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), data)
+	rv, err := abi.Decode(bytes.NewReader(data), "open")
+	assert.NoError(t, err)
+	assert.Equal(t, rv, map[string]interface{}{"sym": es})
+}
+
+func TestSymbolIsValid(t *testing.T) {
+	symbol, err := chain.NewSymbolFromString("18,ABCDEFG")
+	assert.NoError(t, err)
+	assert.True(t, symbol.IsValid())
+	assert.True(t, symbol.Code().IsValid())
+	assert.True(t, !chain.Symbol(0).IsValid())
+	assert.True(t, !chain.SymbolCode(0).IsValid())
+	assert.True(t, !(symbol + 1).IsValid())                         // 19 decimals
+	assert.True(t, !chain.SymbolCode(0x61).IsValid())               // lowercase "a"
+	assert.True(t, !chain.SymbolCode(0x410041).IsValid())           // embedded zero byte
+	assert.True(t, !chain.SymbolCode(0x4141414141414141).IsValid()) // 8 characters
 }
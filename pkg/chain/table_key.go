@@ -0,0 +1,229 @@
+package chain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidTableKey = errors.New("invalid table key")
+
+// Key of a multi-index table index, used as the lower_bound and upper_bound of get_table_rows requests.
+type TableKey interface {
+	// Value of the key_type parameter.
+	KeyType() string
+	// Value of the encode_type parameter.
+	EncodeType() string
+	// Key formatted as a lower_bound or upper_bound parameter.
+	Bound() string
+}
+
+// uint64 index key, the primary index and idx64 secondary indices.
+type I64Key uint64
+
+// uint64 index key formatted as a name.
+type NameKey Name
+
+// uint128 index key, idx128 secondary indices.
+type I128Key Uint128
+
+// 256-bit index key of an idx256 secondary index, e.g. a checksum256 stored by the contract.
+// Bounds and the next_key are in the natural order, nodeos converts them to the two 128-bit
+// words the key is stored as.
+type I256Key Checksum256
+
+// sha256 index key, idx256 secondary indices holding checksum256 values.
+type Sha256Key Checksum256
+
+// ripemd160 index key, idx256 secondary indices holding checksum160 values.
+type Ripemd160Key Checksum160
+
+// double index key, idx_double secondary indices.
+type Float64Key float64
+
+// long double index key, idx_long_double secondary indices. Bounds are converted from a double
+// by nodeos and the next_key is rounded to a double, so keys have double precision.
+type Float128Key float64
+
+// Value of the index_position parameter for the n-th index of a table, 1 being the primary index.
+func TableIndexPosition(n int) string {
+	if n >= 1 && n <= len(tableIndexPositions) {
+		return tableIndexPositions[n-1]
+	}
+	return strconv.Itoa(n)
+}
+
+// Parse the next_key of a get_table_rows response using the key type of the request.
+func ParseTableKey(keyType string, s string) (TableKey, error) {
+	switch keyType {
+	case "i64":
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTableKey, err)
+		}
+		return I64Key(v), nil
+	case "name":
+		v, err := NewNameFromString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTableKey, err)
+		}
+		return NameKey(v), nil
+	case "i128":
+		i, ok := new(big.Int), false
+		if strings.HasPrefix(s, "0x") {
+			_, ok = i.SetString(s[2:], 16)
+		} else {
+			_, ok = i.SetString(s, 10)
+		}
+		if !ok || i.Sign() < 0 || i.BitLen() > 128 {
+			return nil, fmt.Errorf("%w: invalid i128 %q", ErrInvalidTableKey, s)
+		}
+		return I128Key(NewUint128(i)), nil
+	case "i256", "sha256":
+		var v Checksum256
+		err := parseTableKeyHex(v[:], s)
+		if err != nil {
+			return nil, err
+		}
+		if keyType == "i256" {
+			return I256Key(v), nil
+		}
+		return Sha256Key(v), nil
+	case "ripemd160":
+		var v Checksum160
+		err := parseTableKeyHex(v[:], s)
+		if err != nil {
+			return nil, err
+		}
+		return Ripemd160Key(v), nil
+	case "float64", "float128":
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidTableKey, err)
+		}
+		if keyType == "float128" {
+			return Float128Key(v), nil
+		}
+		return Float64Key(v), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown key type %q", ErrInvalidTableKey, keyType)
+	}
+}
+
+func (k I64Key) KeyType() string {
+	return "i64"
+}
+
+func (k I64Key) EncodeType() string {
+	return "dec"
+}
+
+func (k I64Key) Bound() string {
+	return strconv.FormatUint(uint64(k), 10)
+}
+
+func (k NameKey) KeyType() string {
+	return "name"
+}
+
+func (k NameKey) EncodeType() string {
+	return "dec"
+}
+
+func (k NameKey) Bound() string {
+	return Name(k).String()
+}
+
+func (k I128Key) KeyType() string {
+	return "i128"
+}
+
+func (k I128Key) EncodeType() string {
+	return "dec"
+}
+
+func (k I128Key) Bound() string {
+	return Uint128(k).String()
+}
+
+func (k I256Key) KeyType() string {
+	return "i256"
+}
+
+func (k I256Key) EncodeType() string {
+	return "hex"
+}
+
+func (k I256Key) Bound() string {
+	return hex.EncodeToString(k[:])
+}
+
+func (k Sha256Key) KeyType() string {
+	return "sha256"
+}
+
+func (k Sha256Key) EncodeType() string {
+	return "hex"
+}
+
+func (k Sha256Key) Bound() string {
+	return hex.EncodeToString(k[:])
+}
+
+func (k Ripemd160Key) KeyType() string {
+	return "ripemd160"
+}
+
+func (k Ripemd160Key) EncodeType() string {
+	return "hex"
+}
+
+func (k Ripemd160Key) Bound() string {
+	return hex.EncodeToString(k[:])
+}
+
+func (k Float64Key) KeyType() string {
+	return "float64"
+}
+
+func (k Float64Key) EncodeType() string {
+	return "dec"
+}
+
+func (k Float64Key) Bound() string {
+	return strconv.FormatFloat(float64(k), 'g', -1, 64)
+}
+
+func (k Float128Key) KeyType() string {
+	return "float128"
+}
+
+func (k Float128Key) EncodeType() string {
+	return "dec"
+}
+
+func (k Float128Key) Bound() string {
+	return strconv.FormatFloat(float64(k), 'g', -1, 64)
+}
+
+// helpers
+
+var tableIndexPositions = []string{
+	"primary", "secondary", "tertiary", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth", "tenth",
+}
+
+// next_key of i256 indices is prefixed with 0x when using the dec encode type
+func parseTableKeyHex(dst []byte, s string) error {
+	s = strings.TrimPrefix(s, "0x")
+	if hex.DecodedLen(len(s)) != len(dst) {
+		return fmt.Errorf("%w: expected %d hex encoded bytes", ErrInvalidTableKey, len(dst))
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTableKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package chain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestTableKey(t *testing.T) {
+	var sum256 chain.Checksum256
+	assert.NoError(t, sum256.UnmarshalText([]byte("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")))
+	var sum160 chain.Checksum160
+	assert.NoError(t, sum160.UnmarshalText([]byte("000102030405060708090a0b0c0d0e0f10111213")))
+
+	vectors := []struct {
+		key        chain.TableKey
+		keyType    string
+		encodeType string
+		bound      string
+	}{
+		{chain.I64Key(1234), "i64", "dec", "1234"},
+		{chain.NameKey(chain.N("teamgreymass")), "name", "dec", "teamgreymass"},
+		{chain.I128Key(chain.Uint128{Hi: 1, Lo: 2}), "i128", "dec", "18446744073709551618"},
+		{chain.I256Key(sum256), "i256", "hex", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"},
+		{chain.Sha256Key(sum256), "sha256", "hex", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"},
+		{chain.Ripemd160Key(sum160), "ripemd160", "hex", "000102030405060708090a0b0c0d0e0f10111213"},
+		{chain.Float64Key(-1.5), "float64", "dec", "-1.5"},
+		{chain.Float128Key(1e100), "float128", "dec", "1e+100"},
+	}
+	for _, v := range vectors {
+		assert.Equal(t, v.key.KeyType(), v.keyType)
+		assert.Equal(t, v.key.EncodeType(), v.encodeType)
+		assert.Equal(t, v.key.Bound(), v.bound)
+		// the next_key of a response can be sent back as a bound
+		key, err := chain.ParseTableKey(v.keyType, v.bound)
+		assert.NoError(t, err)
+		assert.Equal(t, key, v.key)
+		assert.Equal(t, key.Bound(), v.bound)
+	}
+
+	key, err := chain.ParseTableKey("i256", "0x000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	assert.NoError(t, err)
+	assert.Equal(t, key, chain.TableKey(chain.I256Key(sum256)))
+	key, err = chain.ParseTableKey("i128", "0x10000000000000002")
+	assert.NoError(t, err)
+	assert.Equal(t, key, chain.TableKey(chain.I128Key(chain.Uint128{Hi: 1, Lo: 2})))
+
+	invalid := [][2]string{
+		{"i64", "-1"},
+		{"name", "Alice"},
+		{"name", "toolongaccountname"},
+		{"i128", "0x100000000000000000000000000000000"},
+		{"sha256", "0001"},
+		{"ripemd160", "zz0102030405060708090a0b0c0d0e0f10111213"},
+		{"float64", "foo"},
+		{"i512", "1"},
+	}
+	for _, v := range invalid {
+		_, err = chain.ParseTableKey(v[0], v[1])
+		assert.True(t, errors.Is(err, chain.ErrInvalidTableKey))
+	}
+}
+
+func TestTableIndexPosition(t *testing.T) {
+	assert.Equal(t, chain.TableIndexPosition(1), "primary")
+	assert.Equal(t, chain.TableIndexPosition(2), "secondary")
+	assert.Equal(t, chain.TableIndexPosition(10), "tenth")
+	assert.Equal(t, chain.TableIndexPosition(11), "11")
+}
@@ -0,0 +1,548 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+// Authorization sequence numbers of an action receipt, encoded as a flat_map<name, uint64>.
+type AuthSequence map[Name]uint64
+
+// Receipt of an action executed on a receiver.
+type ActionReceipt struct {
+	Receiver       Name         `json:"receiver"`
+	ActDigest      Checksum256  `json:"act_digest"`
+	GlobalSequence Uint64       `json:"global_sequence"`
+	RecvSequence   Uint64       `json:"recv_sequence"`
+	AuthSequence   AuthSequence `json:"auth_sequence"`
+	CodeSequence   uint         `json:"code_sequence"`
+	AbiSequence    uint         `json:"abi_sequence"`
+}
+
+// Change in RAM usage of an account.
+type AccountDelta struct {
+	Account Name  `json:"account"`
+	Delta   int64 `json:"delta"`
+}
+
+// Exception raised while executing a transaction or action.
+type Exception struct {
+	Code    int64  `json:"code"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	// Log messages with their context, format and data. They are encoded as fc variants in the
+	// binary form, when encoding integers without a sign become uint64 and blobs become strings.
+	Stack []json.RawMessage `json:"stack,omitempty"`
+}
+
+// Trace of an action execution, as returned by the send_transaction2 and push_transaction API endpoints.
+type ActionTrace struct {
+	ActionOrdinal                          uint           `json:"action_ordinal"`
+	CreatorActionOrdinal                   uint           `json:"creator_action_ordinal"`
+	ClosestUnnotifiedAncestorActionOrdinal uint           `json:"closest_unnotified_ancestor_action_ordinal"`
+	Receipt                                *ActionReceipt `json:"receipt" eosio:"optional"`
+	Receiver                               Name           `json:"receiver"`
+	Act                                    Action         `json:"act"`
+	ContextFree                            bool           `json:"context_free"`
+	Elapsed                                int64          `json:"elapsed"`
+	Console                                string         `json:"console"`
+	TrxID                                  Checksum256    `json:"trx_id"`
+	BlockNum                               BlockNum       `json:"block_num"`
+	BlockTime                              BlockTimestamp `json:"block_time"`
+	ProducerBlockID                        *BlockID       `json:"producer_block_id" eosio:"optional"`
+	AccountRamDeltas                       []AccountDelta `json:"account_ram_deltas"`
+	Except                                 *Exception     `json:"except" eosio:"optional"`
+	ErrorCode                              *Uint64        `json:"error_code" eosio:"optional"`
+	ReturnValue                            Bytes          `json:"return_value"`
+}
+
+// Trace of a transaction execution, as returned by the send_transaction2 and push_transaction API endpoints.
+type TransactionTrace struct {
+	ID              Checksum256               `json:"id"`
+	BlockNum        BlockNum                  `json:"block_num"`
+	BlockTime       BlockTimestamp            `json:"block_time"`
+	ProducerBlockID *BlockID                  `json:"producer_block_id" eosio:"optional"`
+	Receipt         *TransactionReceiptHeader `json:"receipt" eosio:"optional"`
+	Elapsed         int64                     `json:"elapsed"`
+	NetUsage        Uint64                    `json:"net_usage"`
+	Scheduled       bool                      `json:"scheduled"`
+	ActionTraces    []ActionTrace             `json:"action_traces"`
+	AccountRamDelta *AccountDelta             `json:"account_ram_delta" eosio:"optional"`
+	// Trace of the failed deferred transaction, set for onerror handlers.
+	FailedDtrxTrace *TransactionTrace `json:"failed_dtrx_trace" eosio:"optional"`
+	Except          *Exception        `json:"except" eosio:"optional"`
+	ErrorCode       *Uint64           `json:"error_code" eosio:"optional"`
+}
+
+// Action trace with the action data and return value decoded using the contract ABI.
+type DecodedActionTrace struct {
+	*ActionTrace
+	// Decoded action data, nil if no ABI was available for the contract.
+	Data map[string]interface{}
+	// Decoded return value, nil if the action has no return value or the ABI doesn't describe it.
+	ReturnValue interface{}
+}
+
+// Digest of the receipt, the leaves of the action_mroot merkle tree.
+func (ar ActionReceipt) Digest() Checksum256 {
+	b := bytes.NewBuffer(nil)
+	err := ar.MarshalABI(NewEncoder(b))
+	if err != nil {
+		panic(err)
+	}
+	return Checksum256Digest(b.Bytes())
+}
+
+// Decode the action data of all traces using the ABI of the contract the action was sent to,
+// traces of contracts not found in abis are returned with their data undecoded.
+func (tt TransactionTrace) DecodeActions(abis map[Name]*Abi) ([]DecodedActionTrace, error) {
+	rv := make([]DecodedActionTrace, len(tt.ActionTraces))
+	for i := range tt.ActionTraces {
+		decoded, err := tt.ActionTraces[i].Decode(abis[tt.ActionTraces[i].Act.Account])
+		if err != nil {
+			return nil, err
+		}
+		rv[i] = *decoded
+	}
+	return rv, nil
+}
+
+// Decode the action data and return value using the ABI of the contract the action was sent to, abi can be nil.
+func (at *ActionTrace) Decode(abi *Abi) (*DecodedActionTrace, error) {
+	rv := &DecodedActionTrace{ActionTrace: at}
+	if abi == nil {
+		return rv, nil
+	}
+	data, err := at.Act.Decode(abi)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode %s::%s action data: %w", at.Act.Account, at.Act.Name, err)
+	}
+	rv.Data = data
+	if len(at.ReturnValue) > 0 && abi.GetActionResult(at.Act.Name) != nil {
+		rv.ReturnValue, err = abi.DecodeActionResult(bytes.NewReader(at.ReturnValue), at.Act.Name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s::%s return value: %w", at.Act.Account, at.Act.Name, err)
+		}
+	}
+	return rv, nil
+}
+
+// abi.Marshaler conformance
+
+func (as AuthSequence) MarshalABI(e *abi.Encoder) error {
+	names := as.sortedNames()
+	err := e.WriteVaruint(uint(len(names)))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		err = name.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+		err = e.WriteUint64(as[name])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ar ActionReceipt) MarshalABI(e *abi.Encoder) error {
+	err := ar.Receiver.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = ar.ActDigest.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint64(uint64(ar.GlobalSequence))
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint64(uint64(ar.RecvSequence))
+	if err != nil {
+		return err
+	}
+	err = ar.AuthSequence.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteVaruint(ar.CodeSequence)
+	if err != nil {
+		return err
+	}
+	return e.WriteVaruint(ar.AbiSequence)
+}
+
+func (ad AccountDelta) MarshalABI(e *abi.Encoder) error {
+	err := ad.Account.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return e.WriteInt64(ad.Delta)
+}
+
+func (ex Exception) MarshalABI(e *abi.Encoder) error {
+	err := e.WriteInt64(ex.Code)
+	if err != nil {
+		return err
+	}
+	err = e.WriteString(ex.Name)
+	if err != nil {
+		return err
+	}
+	err = e.WriteString(ex.Message)
+	if err != nil {
+		return err
+	}
+	err = e.WriteVaruint(uint(len(ex.Stack)))
+	if err != nil {
+		return err
+	}
+	for _, msg := range ex.Stack {
+		dec := json.NewDecoder(bytes.NewReader(msg))
+		dec.UseNumber()
+		err = writeVariant(e, dec)
+		if err != nil {
+			return fmt.Errorf("invalid exception log message: %w", err)
+		}
+	}
+	return nil
+}
+
+// abi.Unmarshaler conformance
+
+func (as *AuthSequence) UnmarshalABI(d *abi.Decoder) error {
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	*as = make(AuthSequence, l)
+	for i := uint(0); i < l; i++ {
+		var name Name
+		err = name.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+		seq, err := d.ReadUint64()
+		if err != nil {
+			return err
+		}
+		(*as)[name] = seq
+	}
+	return nil
+}
+
+func (ar *ActionReceipt) UnmarshalABI(d *abi.Decoder) error {
+	err := ar.Receiver.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = ar.ActDigest.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	v, err := d.ReadUint64()
+	if err != nil {
+		return err
+	}
+	ar.GlobalSequence = Uint64(v)
+	v, err = d.ReadUint64()
+	if err != nil {
+		return err
+	}
+	ar.RecvSequence = Uint64(v)
+	err = ar.AuthSequence.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	ar.CodeSequence, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	ar.AbiSequence, err = d.ReadVaruint()
+	return err
+}
+
+func (ad *AccountDelta) UnmarshalABI(d *abi.Decoder) error {
+	err := ad.Account.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	ad.Delta, err = d.ReadInt64()
+	return err
+}
+
+func (ex *Exception) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	ex.Code, err = d.ReadInt64()
+	if err != nil {
+		return err
+	}
+	ex.Name, err = d.ReadString()
+	if err != nil {
+		return err
+	}
+	ex.Message, err = d.ReadString()
+	if err != nil {
+		return err
+	}
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	ex.Stack = nil
+	for i := uint(0); i < l; i++ {
+		b := bytes.NewBuffer(nil)
+		err = readVariant(d, b, 0)
+		if err != nil {
+			return err
+		}
+		ex.Stack = append(ex.Stack, b.Bytes())
+	}
+	return nil
+}
+
+// json.Marshaler conformance
+
+func (as AuthSequence) MarshalJSON() ([]byte, error) {
+	names := as.sortedNames()
+	pairs := make([][2]interface{}, len(names))
+	for i, name := range names {
+		pairs[i] = [2]interface{}{name, Uint64(as[name])}
+	}
+	return json.Marshal(pairs)
+}
+
+// json.Unmarshaler conformance
+
+func (as *AuthSequence) UnmarshalJSON(b []byte) error {
+	var pairs [][2]json.RawMessage
+	err := json.Unmarshal(b, &pairs)
+	if err != nil {
+		return err
+	}
+	*as = make(AuthSequence, len(pairs))
+	for _, pair := range pairs {
+		var name Name
+		var seq Uint64
+		err = json.Unmarshal(pair[0], &name)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(pair[1], &seq)
+		if err != nil {
+			return err
+		}
+		(*as)[name] = uint64(seq)
+	}
+	return nil
+}
+
+// helpers
+
+// flat_map keys are ordered by the name value
+func (as AuthSequence) sortedNames() []Name {
+	names := make([]Name, 0, len(as))
+	for name := range as {
+		names = append(names, name)
+	}
+	SortNames(names)
+	return names
+}
+
+// fc::variant type tags
+const (
+	variantNull = iota
+	variantInt64
+	variantUint64
+	variantDouble
+	variantBool
+	variantString
+	variantArray
+	variantObject
+	variantBlob
+)
+
+// nesting limit for variants, guards against malformed input
+const maxVariantDepth = 1024
+
+// read a binary fc variant and write it as JSON to w
+func readVariant(d *abi.Decoder, w *bytes.Buffer, depth int) error {
+	if depth > maxVariantDepth {
+		return errors.New("variant nested too deeply")
+	}
+	t, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	var v interface{}
+	switch t {
+	case variantNull:
+	case variantInt64:
+		v, err = d.ReadInt64()
+	case variantUint64:
+		v, err = d.ReadUint64()
+	case variantDouble:
+		var f float64
+		f, err = d.ReadFloat64()
+		if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			v = strconv.FormatFloat(f, 'g', -1, 64)
+		} else {
+			v = f
+		}
+	case variantBool:
+		v, err = d.ReadBool()
+	case variantString:
+		v, err = d.ReadString()
+	case variantBlob:
+		var l uint
+		l, err = d.ReadVaruint()
+		if err == nil {
+			_, v, err = d.ReadBytes(int(l))
+		}
+	case variantArray, variantObject:
+		l, err := d.ReadVaruint()
+		if err != nil {
+			return err
+		}
+		open, close := byte('['), byte(']')
+		if t == variantObject {
+			open, close = '{', '}'
+		}
+		w.WriteByte(open)
+		for i := uint(0); i < l; i++ {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			if t == variantObject {
+				key, err := d.ReadString()
+				if err != nil {
+					return err
+				}
+				writeJSON(w, key)
+				w.WriteByte(':')
+			}
+			err = readVariant(d, w, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+		w.WriteByte(close)
+		return nil
+	default:
+		return fmt.Errorf("unknown variant type %d", t)
+	}
+	if err != nil {
+		return err
+	}
+	writeJSON(w, v)
+	return nil
+}
+
+// write the next JSON value of dec as a binary fc variant, keeping the order of object keys
+func writeVariant(e *abi.Encoder, dec *json.Decoder) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := token.(type) {
+	case nil:
+		return e.WriteVaruint(variantNull)
+	case bool:
+		err = e.WriteVaruint(variantBool)
+		if err == nil {
+			err = e.WriteBool(v)
+		}
+		return err
+	case string:
+		err = e.WriteVaruint(variantString)
+		if err == nil {
+			err = e.WriteString(v)
+		}
+		return err
+	case json.Number:
+		if !strings.ContainsAny(string(v), ".eE") {
+			if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+				err = e.WriteVaruint(variantUint64)
+				if err == nil {
+					err = e.WriteUint64(u)
+				}
+				return err
+			}
+			if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+				err = e.WriteVaruint(variantInt64)
+				if err == nil {
+					err = e.WriteInt64(i)
+				}
+				return err
+			}
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return err
+		}
+		err = e.WriteVaruint(variantDouble)
+		if err == nil {
+			err = e.WriteFloat64(f)
+		}
+		return err
+	case json.Delim:
+		// elements are buffered since the count precedes them
+		t := uint(variantArray)
+		if v == '{' {
+			t = variantObject
+		}
+		b := bytes.NewBuffer(nil)
+		be := NewEncoder(b)
+		var n uint
+		for dec.More() {
+			if t == variantObject {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				err = be.WriteString(key.(string))
+				if err != nil {
+					return err
+				}
+			}
+			err = writeVariant(be, dec)
+			if err != nil {
+				return err
+			}
+			n++
+		}
+		_, err = dec.Token() // closing delimiter
+		if err != nil {
+			return err
+		}
+		err = e.WriteVaruint(t)
+		if err != nil {
+			return err
+		}
+		err = e.WriteVaruint(n)
+		if err != nil {
+			return err
+		}
+		return e.WriteBytes(b.Bytes())
+	default:
+		return fmt.Errorf("unexpected JSON token %v", token)
+	}
+}
+
+func writeJSON(w *bytes.Buffer, v interface{}) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	w.Truncate(w.Len() - 1) // trailing newline
+}
@@ -0,0 +1,173 @@
+package chain_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestActionReceipt(t *testing.T) {
+	var digest chain.Checksum256
+	assert.NoError(t, digest.UnmarshalText([]byte("e4f6b0a3b5c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f")))
+	receipt := chain.ActionReceipt{
+		Receiver:       chain.N("eosio.token"),
+		ActDigest:      digest,
+		GlobalSequence: 100,
+		RecvSequence:   5,
+		AuthSequence:   chain.AuthSequence{chain.N("bob"): 7, chain.N("alice"): 3},
+		CodeSequence:   2,
+		AbiSequence:    300,
+	}
+	assert.ABICoding(t, receipt, mustDecodeHex(
+		"00a6823403ea3055e4f6b0a3b5c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f"+
+			"64000000000000000500000000000000020000000000855c3403000000000000000000000000000e3d070000000000000002ac02",
+	))
+	assert.JSONCoding(t, receipt, `{
+		"receiver": "eosio.token",
+		"act_digest": "e4f6b0a3b5c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f",
+		"global_sequence": 100,
+		"recv_sequence": 5,
+		"auth_sequence": [["alice", 3], ["bob", 7]],
+		"code_sequence": 2,
+		"abi_sequence": 300
+	}`)
+	assert.Equal(t, receipt.Digest().String(), "dc7eb13a2988d7b42576969aba844a7440625bcfff519211a6cd11c89c516bcb")
+}
+
+const transactionTraceJSON = `{
+	"id": "2b3a9a9fa1f0a7e4e4c6d0f1d4d0a4f8b5b2b0c7e1e6f6f1e9b1b7c3a3b2a1f0",
+	"block_num": 1234,
+	"block_time": "2021-06-28T14:31:35.500",
+	"producer_block_id": null,
+	"receipt": {"status": "executed", "cpu_usage_us": 155, "net_usage_words": 16},
+	"elapsed": 155,
+	"net_usage": 128,
+	"scheduled": false,
+	"action_traces": [{
+		"action_ordinal": 1,
+		"creator_action_ordinal": 0,
+		"closest_unnotified_ancestor_action_ordinal": 0,
+		"receipt": {
+			"receiver": "eosio.token",
+			"act_digest": "e4f6b0a3b5c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f",
+			"global_sequence": "5000000000",
+			"recv_sequence": 5,
+			"auth_sequence": [["foo", 3]],
+			"code_sequence": 1,
+			"abi_sequence": 1
+		},
+		"receiver": "eosio.token",
+		"act": {
+			"account": "eosio.token",
+			"name": "transfer",
+			"authorization": [{"actor": "foo", "permission": "active"}],
+			"data": "0000000000002856000000000000ae391027000000000000044f5453000000000568656c6c6f"
+		},
+		"context_free": false,
+		"elapsed": 80,
+		"console": "",
+		"trx_id": "2b3a9a9fa1f0a7e4e4c6d0f1d4d0a4f8b5b2b0c7e1e6f6f1e9b1b7c3a3b2a1f0",
+		"block_num": 1234,
+		"block_time": "2021-06-28T14:31:35.500",
+		"producer_block_id": null,
+		"account_ram_deltas": [{"account": "foo", "delta": -12}],
+		"except": null,
+		"error_code": null,
+		"return_value": ""
+	}],
+	"account_ram_delta": null,
+	"failed_dtrx_trace": null,
+	"except": null,
+	"error_code": null
+}`
+
+func TestTransactionTrace(t *testing.T) {
+	var trace chain.TransactionTrace
+	assert.NoError(t, json.Unmarshal([]byte(transactionTraceJSON), &trace))
+	assert.Equal(t, trace.Receipt.Status, chain.TransactionStatusExecuted)
+	assert.Equal(t, len(trace.ActionTraces), 1)
+	at := trace.ActionTraces[0]
+	assert.Equal(t, at.Receipt.GlobalSequence, chain.Uint64(5000000000))
+	assert.Equal(t, at.Receipt.AuthSequence[chain.N("foo")], uint64(3))
+	assert.Equal(t, at.AccountRamDeltas[0].Delta, int64(-12))
+	assert.Equal(t, at.BlockTime.String(), "2021-06-28T14:31:35.500")
+
+	// binary round trip
+	b := bytes.NewBuffer(nil)
+	assert.NoError(t, chain.NewEncoder(b).Encode(trace))
+	var decoded chain.TransactionTrace
+	assert.NoError(t, chain.NewDecoder(bytes.NewReader(b.Bytes())).Decode(&decoded))
+	assert.JSONCoding(t, decoded, transactionTraceJSON)
+
+	code := chain.Uint64(3050003)
+	trace.Except = &chain.Exception{Code: 3050003, Name: "eosio_assert_message_exception", Message: "overdrawn balance"}
+	trace.ErrorCode = &code
+	b.Reset()
+	assert.NoError(t, chain.NewEncoder(b).Encode(trace))
+	decoded = chain.TransactionTrace{}
+	assert.NoError(t, chain.NewDecoder(bytes.NewReader(b.Bytes())).Decode(&decoded))
+	assert.Equal(t, *decoded.Except, *trace.Except)
+	assert.Equal(t, *decoded.ErrorCode, code)
+
+	actions, err := trace.DecodeActions(map[chain.Name]*chain.Abi{chain.N("eosio.token"): tokenAbi})
+	assert.NoError(t, err)
+	assert.Equal(t, actions[0].Data["memo"], "hello")
+	assert.Equal(t, actions[0].Data["quantity"], *chain.A("1.0000 OTS"))
+	actions, err = trace.DecodeActions(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, actions[0].Data == nil, true)
+	assert.Equal(t, actions[0].Act.Name, chain.N("transfer"))
+}
+
+func TestActionTraceReturnValue(t *testing.T) {
+	at := chain.ActionTrace{
+		Act: chain.Action{
+			Account: chain.N("calc"),
+			Name:    chain.N("sum"),
+			Data:    mustDecodeHex("0300000004000000"),
+		},
+		ReturnValue: mustDecodeHex("07000000"),
+	}
+	decoded, err := at.Decode(sumAbi)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.Data, map[string]interface{}{"a": uint32(3), "b": uint32(4)})
+	assert.Equal(t, decoded.ReturnValue, uint32(7))
+
+	// no return value
+	at.ReturnValue = nil
+	decoded, err = at.Decode(sumAbi)
+	assert.NoError(t, err)
+	assert.True(t, decoded.ReturnValue == nil)
+
+	// malformed return value
+	at.ReturnValue = []byte{0x07}
+	_, err = at.Decode(sumAbi)
+	assert.NotNil(t, err)
+}
+
+func TestExceptionLogMessages(t *testing.T) {
+	// eosio_assert failure with a log message packed as an fc variant
+	ex := chain.Exception{
+		Code:    3050003,
+		Name:    "eosio_assert_message_exception",
+		Message: "eosio_assert_message assertion failure",
+		Stack: []json.RawMessage{
+			json.RawMessage(`{"context":{"level":"error","file":"wasm_interface.cpp","line":1204,"method":"eosio_assert","hostname":"","thread_name":"nodeos","timestamp":"2021-06-28T14:31:35.512","context":null},"format":"assertion failure with message: ${s}","data":{"s":"overdrawn balance","n":-1,"ok":true,"f":1.5}}`),
+		},
+	}
+	assert.ABICoding(t, ex, mustDecodeHex("138a2e00000000001e656f73696f5f6173736572745f6d6573736167655f657863657074696f6e26656f73696f5f6173736572745f6d65737361676520617373657274696f6e206661696c75726501070307636f6e746578740708056c6576656c05056572726f720466696c6505127761736d5f696e746572666163652e637070046c696e6502b404000000000000066d6574686f64050c656f73696f5f61737365727408686f73746e616d6505000b7468726561645f6e616d6505066e6f64656f730974696d657374616d700517323032312d30362d32385431343a33313a33352e35313207636f6e746578740006666f726d61740524617373657274696f6e206661696c7572652077697468206d6573736167653a20247b737d04646174610704017305116f766572647261776e2062616c616e6365016e01ffffffffffffffff026f6b0401016603000000000000f83f"))
+
+	// blobs are base64 encoded
+	var decoded chain.Exception
+	err := chain.NewDecoder(bytes.NewReader(mustDecodeHex("0100000000000000000001060208020102050378797a"))).Decode(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.Stack, []json.RawMessage{json.RawMessage(`["AQI=","xyz"]`)})
+
+	err = chain.NewDecoder(bytes.NewReader(mustDecodeHex("010000000000000000000109"))).Decode(&decoded)
+	assert.HasError(t, &err)
+	ex.Stack = []json.RawMessage{json.RawMessage(`{"foo":`)}
+	assert.NotNil(t, chain.NewEncoder(bytes.NewBuffer(nil)).Encode(ex))
+}
@@ -1,6 +1,13 @@
 package chain
 
-import "github.com/greymass/go-eosio/pkg/abi"
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
 
 type TransactionHeader struct {
 	Expiration       TimePointSec `json:"expiration"`
@@ -23,6 +30,171 @@ type Transaction struct {
 	Extensions         []TransactionExtension `json:"transaction_extensions"`
 }
 
+// Transaction with signatures and context free data.
+type SignedTransaction struct {
+	Transaction
+	Signatures      []Signature `json:"signatures"`
+	ContextFreeData []Bytes     `json:"context_free_data"`
+}
+
+// Transaction compression type.
+type CompressionType uint8
+
+const (
+	CompressionNone CompressionType = 0
+	CompressionZlib CompressionType = 1
+)
+
+// Transaction in the format it is transmitted and stored in blocks.
+type PackedTransaction struct {
+	Signatures            []Signature     `json:"signatures"`
+	Compression           CompressionType `json:"compression"`
+	PackedContextFreeData Bytes           `json:"packed_context_free_data"`
+	PackedTrx             Bytes           `json:"packed_trx"`
+}
+
+// Set the TAPOS (transaction as proof of stake) reference block of the header, the transaction
+// will only be valid on chains that include the block.
+func (txh *TransactionHeader) SetReferenceBlock(id BlockID) {
+	txh.RefBlockNum = uint16(id.Num())
+	txh.RefBlockPrefix = id.RefBlockPrefix()
+}
+
+// Transaction id, the sha256 digest of the serialized transaction.
+func (tx Transaction) ID() Checksum256 {
+	b := bytes.NewBuffer(nil)
+	err := tx.MarshalABI(NewEncoder(b))
+	if err != nil {
+		panic(err)
+	}
+	return Checksum256Digest(b.Bytes())
+}
+
+// Digest signed when authorizing the transaction on given chain, the transaction
+// is assumed to have no context free data.
+func (tx Transaction) SigningDigest(chainID Checksum256) Checksum256 {
+	return signingDigest(chainID, tx, nil)
+}
+
+// Digest signed when authorizing the transaction on given chain, commits to the context free data.
+func (stx SignedTransaction) SigningDigest(chainID Checksum256) Checksum256 {
+	return signingDigest(chainID, stx.Transaction, stx.ContextFreeData)
+}
+
+// Pack the signed transaction using given compression.
+func (stx SignedTransaction) Pack(compression CompressionType) (*PackedTransaction, error) {
+	b := bytes.NewBuffer(nil)
+	err := stx.Transaction.MarshalABI(NewEncoder(b))
+	if err != nil {
+		return nil, err
+	}
+	trx := b.Bytes()
+	var cfd []byte
+	if len(stx.ContextFreeData) > 0 {
+		b = bytes.NewBuffer(nil)
+		err = NewEncoder(b).Encode(stx.ContextFreeData)
+		if err != nil {
+			return nil, err
+		}
+		cfd = b.Bytes()
+	}
+	switch compression {
+	case CompressionNone:
+	case CompressionZlib:
+		trx, err = zlibCompress(trx)
+		if err != nil {
+			return nil, err
+		}
+		if len(cfd) > 0 {
+			cfd, err = zlibCompress(cfd)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown compression type: %d", compression)
+	}
+	return &PackedTransaction{
+		Signatures:            stx.Signatures,
+		Compression:           compression,
+		PackedContextFreeData: cfd,
+		PackedTrx:             trx,
+	}, nil
+}
+
+// Unpack the transaction, decompressing it if needed.
+func (ptx PackedTransaction) Transaction() (*Transaction, error) {
+	var r io.Reader = bytes.NewReader(ptx.PackedTrx)
+	switch ptx.Compression {
+	case CompressionNone:
+	case CompressionZlib:
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unknown compression type: %d", ptx.Compression)
+	}
+	var tx Transaction
+	err := NewDecoder(r).Decode(&tx)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// Transaction id of the packed transaction.
+func (ptx PackedTransaction) ID() (Checksum256, error) {
+	tx, err := ptx.Transaction()
+	if err != nil {
+		return Checksum256{}, err
+	}
+	return tx.ID(), nil
+}
+
+// Digest of the packed transaction used in transaction receipts, commits to the signatures and context free data.
+func (ptx PackedTransaction) PackedDigest() Checksum256 {
+	b := bytes.NewBuffer(nil)
+	e := NewEncoder(b)
+	l := uint(len(ptx.Signatures))
+	err := e.WriteVaruint(l)
+	for i := uint(0); i < l && err == nil; i++ {
+		err = ptx.Signatures[i].MarshalABI(e)
+	}
+	if err == nil {
+		err = ptx.PackedContextFreeData.MarshalABI(e)
+	}
+	if err != nil {
+		panic(err)
+	}
+	prunable := Checksum256Digest(b.Bytes())
+	b.Reset()
+	err = e.WriteUint8(uint8(ptx.Compression))
+	if err == nil {
+		err = ptx.PackedTrx.MarshalABI(e)
+	}
+	if err == nil {
+		err = prunable.MarshalABI(e)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return Checksum256Digest(b.Bytes())
+}
+
+func (ct CompressionType) String() string {
+	switch ct {
+	case CompressionNone:
+		return "none"
+	case CompressionZlib:
+		return "zlib"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(ct))
+	}
+}
+
 // abi.Marshaler conformance
 
 func (txh TransactionHeader) MarshalABI(e *abi.Encoder) error {
@@ -103,6 +275,61 @@ func (tx Transaction) MarshalABI(e *abi.Encoder) error {
 	return err
 }
 
+func (stx SignedTransaction) MarshalABI(e *abi.Encoder) error {
+	var err error
+	err = stx.Transaction.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	l := uint(len(stx.Signatures))
+	err = e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = stx.Signatures[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	l = uint(len(stx.ContextFreeData))
+	err = e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = stx.ContextFreeData[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (ptx PackedTransaction) MarshalABI(e *abi.Encoder) error {
+	var err error
+	l := uint(len(ptx.Signatures))
+	err = e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = ptx.Signatures[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	err = e.WriteUint8(uint8(ptx.Compression))
+	if err != nil {
+		return err
+	}
+	err = ptx.PackedContextFreeData.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return ptx.PackedTrx.MarshalABI(e)
+}
+
 // abi.Unmarshaler conformance
 
 func (txh *TransactionHeader) UnmarshalABI(d *abi.Decoder) error {
@@ -183,3 +410,119 @@ func (tx *Transaction) UnmarshalABI(d *abi.Decoder) error {
 	}
 	return err
 }
+
+func (stx *SignedTransaction) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	err = stx.Transaction.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	var len uint
+	len, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	stx.Signatures = make([]Signature, len)
+	for i := 0; i < int(len); i++ {
+		err = stx.Signatures[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	len, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	stx.ContextFreeData = make([]Bytes, len)
+	for i := 0; i < int(len); i++ {
+		err = stx.ContextFreeData[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (ptx *PackedTransaction) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	var len uint
+	len, err = d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	ptx.Signatures = make([]Signature, len)
+	for i := 0; i < int(len); i++ {
+		err = ptx.Signatures[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	var c uint8
+	c, err = d.ReadUint8()
+	if err != nil {
+		return err
+	}
+	ptx.Compression = CompressionType(c)
+	err = ptx.PackedContextFreeData.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return ptx.PackedTrx.UnmarshalABI(d)
+}
+
+// encoding.TextMarshaler conformance
+
+func (ct CompressionType) MarshalText() (text []byte, err error) {
+	return []byte(ct.String()), nil
+}
+
+// encoding.TextUnmarshaler conformance
+
+func (ct *CompressionType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "none":
+		*ct = CompressionNone
+	case "zlib":
+		*ct = CompressionZlib
+	default:
+		return fmt.Errorf("unknown compression type: %s", text)
+	}
+	return nil
+}
+
+// helpers
+
+func zlibCompress(data []byte) ([]byte, error) {
+	b := bytes.NewBuffer(nil)
+	w := zlib.NewWriter(b)
+	_, err := w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func signingDigest(chainID Checksum256, tx Transaction, cfd []Bytes) Checksum256 {
+	b := bytes.NewBuffer(nil)
+	b.Write(chainID[:])
+	err := tx.MarshalABI(NewEncoder(b))
+	if err != nil {
+		panic(err)
+	}
+	// context free data digest, all zeroes when empty
+	var cfdDigest Checksum256
+	if len(cfd) > 0 {
+		c := bytes.NewBuffer(nil)
+		err = NewEncoder(c).Encode(cfd)
+		if err != nil {
+			panic(err)
+		}
+		cfdDigest = Checksum256Digest(c.Bytes())
+	}
+	b.Write(cfdDigest[:])
+	return Checksum256Digest(b.Bytes())
+}
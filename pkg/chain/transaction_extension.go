@@ -0,0 +1,216 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+// Known transaction extension ids.
+const (
+	DeferredTransactionGenerationContextID uint16 = 0
+	ResourcePayerExtensionID               uint16 = 1
+)
+
+var ErrInvalidTransactionExtension = errors.New("invalid transaction extension")
+
+// Typed value of a transaction extension.
+type TransactionExtensionValue interface {
+	ExtensionID() uint16
+}
+
+// Context of a transaction generated by a contract as a deferred transaction.
+type DeferredTransactionGenerationContext struct {
+	SenderTrxID Checksum256 `json:"sender_trx_id"`
+	SenderID    Uint128     `json:"sender_id"`
+	Sender      Name        `json:"sender"`
+}
+
+// Account paying for the resources used by the transaction, and the maximum amounts it will pay for.
+type ResourcePayer struct {
+	Payer          Name   `json:"payer"`
+	MaxNetBytes    uint64 `json:"max_net_bytes"`
+	MaxCpuUs       uint64 `json:"max_cpu_us"`
+	MaxMemoryBytes uint64 `json:"max_memory_bytes"`
+}
+
+type transactionExtensionType struct {
+	unique bool
+	new    func() TransactionExtensionValue
+}
+
+var transactionExtensionsMu sync.RWMutex
+
+var transactionExtensions = map[uint16]transactionExtensionType{
+	DeferredTransactionGenerationContextID: {true, func() TransactionExtensionValue { return &DeferredTransactionGenerationContext{} }},
+	ResourcePayerExtensionID:               {true, func() TransactionExtensionValue { return &ResourcePayer{} }},
+}
+
+// Register a transaction extension type, e.g. for chains with custom extensions. The function must return a
+// pointer to a new value that can be decoded into. Unique extensions can only appear once in a transaction.
+func RegisterTransactionExtension(id uint16, unique bool, fn func() TransactionExtensionValue) {
+	transactionExtensionsMu.Lock()
+	defer transactionExtensionsMu.Unlock()
+	transactionExtensions[id] = transactionExtensionType{unique, fn}
+}
+
+func (DeferredTransactionGenerationContext) ExtensionID() uint16 {
+	return DeferredTransactionGenerationContextID
+}
+
+func (ResourcePayer) ExtensionID() uint16 {
+	return ResourcePayerExtensionID
+}
+
+// Validate the transaction extensions the same way nodeos does, extensions must be of a known type,
+// sorted by type and unique extensions may only appear once.
+func (tx Transaction) ValidateExtensions() error {
+	_, err := tx.DecodeExtensions()
+	return err
+}
+
+// Validate and decode all transaction extensions.
+func (tx Transaction) DecodeExtensions() ([]TransactionExtensionValue, error) {
+	rv := make([]TransactionExtensionValue, len(tx.Extensions))
+	for i, ext := range tx.Extensions {
+		if i > 0 && ext.Type < tx.Extensions[i-1].Type {
+			return nil, fmt.Errorf("%w: extensions are not in ascending order", ErrInvalidTransactionExtension)
+		}
+		t, ok := getTransactionExtension(ext.Type)
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported extension type %d", ErrInvalidTransactionExtension, ext.Type)
+		}
+		if t.unique && i > 0 && ext.Type == tx.Extensions[i-1].Type {
+			return nil, fmt.Errorf("%w: extension type %d is not allowed to repeat", ErrInvalidTransactionExtension, ext.Type)
+		}
+		v := t.new()
+		err := NewDecoder(bytes.NewReader(ext.Data)).Decode(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to decode extension type %d: %v", ErrInvalidTransactionExtension, ext.Type, err)
+		}
+		rv[i] = v
+	}
+	return rv, nil
+}
+
+// Decode the first extension with the same type as v into v, which must be a pointer.
+func (tx Transaction) GetExtension(v TransactionExtensionValue) (found bool, err error) {
+	for _, ext := range tx.Extensions {
+		if ext.Type == v.ExtensionID() {
+			return true, NewDecoder(bytes.NewReader(ext.Data)).Decode(v)
+		}
+	}
+	return false, nil
+}
+
+// Add an extension keeping the extensions sorted by type, replacing any existing extension
+// of the same type if the type is unique.
+func (tx *Transaction) SetExtension(v TransactionExtensionValue) error {
+	b := bytes.NewBuffer(nil)
+	err := NewEncoder(b).Encode(v)
+	if err != nil {
+		return err
+	}
+	id := v.ExtensionID()
+	ext := TransactionExtension{Type: id, Data: b.Bytes()}
+	if t, ok := getTransactionExtension(id); !ok || t.unique {
+		for i := range tx.Extensions {
+			if tx.Extensions[i].Type == id {
+				tx.Extensions[i] = ext
+				return nil
+			}
+		}
+	}
+	i := 0
+	for i < len(tx.Extensions) && tx.Extensions[i].Type <= id {
+		i++
+	}
+	tx.Extensions = append(tx.Extensions, TransactionExtension{})
+	copy(tx.Extensions[i+1:], tx.Extensions[i:])
+	tx.Extensions[i] = ext
+	return nil
+}
+
+// Remove all extensions of given type.
+func (tx *Transaction) RemoveExtension(id uint16) {
+	exts := tx.Extensions[:0]
+	for _, ext := range tx.Extensions {
+		if ext.Type != id {
+			exts = append(exts, ext)
+		}
+	}
+	tx.Extensions = exts
+}
+
+// abi.Marshaler conformance
+
+func (ctx DeferredTransactionGenerationContext) MarshalABI(e *abi.Encoder) error {
+	err := ctx.SenderTrxID.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = ctx.SenderID.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return ctx.Sender.MarshalABI(e)
+}
+
+func (rp ResourcePayer) MarshalABI(e *abi.Encoder) error {
+	err := rp.Payer.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint64(rp.MaxNetBytes)
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint64(rp.MaxCpuUs)
+	if err != nil {
+		return err
+	}
+	return e.WriteUint64(rp.MaxMemoryBytes)
+}
+
+// abi.Unmarshaler conformance
+
+func (ctx *DeferredTransactionGenerationContext) UnmarshalABI(d *abi.Decoder) error {
+	err := ctx.SenderTrxID.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = ctx.SenderID.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return ctx.Sender.UnmarshalABI(d)
+}
+
+func (rp *ResourcePayer) UnmarshalABI(d *abi.Decoder) error {
+	err := rp.Payer.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	rp.MaxNetBytes, err = d.ReadUint64()
+	if err != nil {
+		return err
+	}
+	rp.MaxCpuUs, err = d.ReadUint64()
+	if err != nil {
+		return err
+	}
+	rp.MaxMemoryBytes, err = d.ReadUint64()
+	return err
+}
+
+// helpers
+
+func getTransactionExtension(id uint16) (transactionExtensionType, bool) {
+	transactionExtensionsMu.RLock()
+	defer transactionExtensionsMu.RUnlock()
+	t, ok := transactionExtensions[id]
+	return t, ok
+}
@@ -0,0 +1,77 @@
+package chain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+func TestResourcePayer(t *testing.T) {
+	rp := chain.ResourcePayer{
+		Payer:          chain.N("alice"),
+		MaxNetBytes:    1000,
+		MaxCpuUs:       2000,
+		MaxMemoryBytes: 3000,
+	}
+	assert.ABICoding(t, rp, mustDecodeHex("0000000000855c34e803000000000000d007000000000000b80b000000000000"))
+	assert.JSONCoding(t, rp, `{"payer":"alice","max_net_bytes":1000,"max_cpu_us":2000,"max_memory_bytes":3000}`)
+}
+
+func TestDeferredTransactionGenerationContext(t *testing.T) {
+	var ctx chain.DeferredTransactionGenerationContext
+	ctx.SenderTrxID[0] = 0xff
+	ctx.SenderID = chain.Uint128{Lo: 1}
+	ctx.Sender = chain.N("foo")
+	assert.ABICoding(t, ctx, mustDecodeHex(
+		"ff00000000000000000000000000000000000000000000000000000000000000"+
+			"01000000000000000000000000000000"+
+			"000000000000285d",
+	))
+}
+
+func TestTransactionExtensions(t *testing.T) {
+	var tx chain.Transaction
+	rp := chain.ResourcePayer{Payer: chain.N("alice"), MaxNetBytes: 1}
+	assert.NoError(t, tx.SetExtension(rp))
+	ctx := chain.DeferredTransactionGenerationContext{Sender: chain.N("foo")}
+	assert.NoError(t, tx.SetExtension(&ctx))
+	assert.Equal(t, len(tx.Extensions), 2)
+	assert.Equal(t, tx.Extensions[0].Type, chain.DeferredTransactionGenerationContextID)
+	assert.Equal(t, tx.Extensions[1].Type, chain.ResourcePayerExtensionID)
+
+	// unique extensions are replaced
+	rp.MaxNetBytes = 2
+	assert.NoError(t, tx.SetExtension(rp))
+	assert.Equal(t, len(tx.Extensions), 2)
+
+	var decoded chain.ResourcePayer
+	found, err := tx.GetExtension(&decoded)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, decoded, rp)
+
+	exts, err := tx.DecodeExtensions()
+	assert.NoError(t, err)
+	assert.Equal(t, exts[0], chain.TransactionExtensionValue(&ctx))
+	assert.Equal(t, exts[1], chain.TransactionExtensionValue(&rp))
+
+	tx.RemoveExtension(chain.DeferredTransactionGenerationContextID)
+	assert.Equal(t, len(tx.Extensions), 1)
+	found, err = tx.GetExtension(&ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, found, false)
+
+	// nodeos validation rules
+	tx.Extensions = append(tx.Extensions, tx.Extensions[0])
+	assert.True(t, errors.Is(tx.ValidateExtensions(), chain.ErrInvalidTransactionExtension))
+	tx.Extensions = []chain.TransactionExtension{tx.Extensions[0], {Type: 0}}
+	assert.True(t, errors.Is(tx.ValidateExtensions(), chain.ErrInvalidTransactionExtension))
+	tx.Extensions = []chain.TransactionExtension{{Type: 42}}
+	assert.True(t, errors.Is(tx.ValidateExtensions(), chain.ErrInvalidTransactionExtension))
+	tx.Extensions = []chain.TransactionExtension{{Type: chain.ResourcePayerExtensionID, Data: []byte{1, 2}}}
+	assert.True(t, errors.Is(tx.ValidateExtensions(), chain.ErrInvalidTransactionExtension))
+	tx.Extensions = nil
+	assert.NoError(t, tx.ValidateExtensions())
+}
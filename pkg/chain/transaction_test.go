@@ -80,4 +80,62 @@ func TestTransaction(t *testing.T) {
 			"transaction_extensions": []
 		}
 	`)
+
+	var chainID chain.Checksum256
+	assert.NoError(t, chainID.UnmarshalText([]byte("aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906")))
+	assert.Equal(t, tx.SigningDigest(chainID).String(), "d889fc69824539264ffa2dc5608635ae94cfc5649c4b6c66b20abb019861421d")
+}
+
+func TestSignedTransaction(t *testing.T) {
+	tx := chain.Transaction{
+		TransactionHeader: chain.TransactionHeader{
+			Expiration:     chain.TimePointSec(1234567890),
+			RefBlockNum:    11,
+			RefBlockPrefix: 22,
+		},
+		ContextFreeActions: []chain.Action{},
+		Actions: []chain.Action{
+			{
+				Account:       chain.N("foo"),
+				Name:          chain.N("bar"),
+				Authorization: []chain.PermissionLevel{{Actor: chain.N("baz"), Permission: chain.N("qux")}},
+				Data:          []byte{0xde, 0xad, 0xbe, 0xef},
+			},
+		},
+		Extensions: []chain.TransactionExtension{},
+	}
+	sig := chain.NewSignature(chain.K1, make([]byte, 65))
+	sig.Data[0] = 0x1f
+	stx := chain.SignedTransaction{
+		Transaction:     tx,
+		Signatures:      []chain.Signature{*sig},
+		ContextFreeData: []chain.Bytes{{0xbe, 0xef}},
+	}
+	assert.ABICoding(t, stx, mustDecodeHex(
+		"d20296490b0016000000000000"+"00"+"01"+"000000000000285d"+"000000000000ae39"+
+			"01"+"000000000000be39"+"000000000000bab6"+"04deadbeef"+"00"+
+			"01001f"+"0000000000000000000000000000000000000000000000000000000000000000"+
+			"0000000000000000000000000000000000000000000000000000000000000000"+
+			"0102beef",
+	))
+
+	var chainID chain.Checksum256
+	assert.NoError(t, chainID.UnmarshalText([]byte("aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906")))
+	assert.Equal(t, stx.SigningDigest(chainID).String(), "6887f92b3d018ff373210b6d7ac69ec94e870083d54749f765775f7ed87c67aa")
+	assert.Equal(t, tx.SigningDigest(chainID).String(), "4429ab1a58c5a208731b2baf35e3ea15a93c8843fe7eb1adc7b6e8315919009c")
+
+	ptx, err := stx.Pack(chain.CompressionNone)
+	assert.NoError(t, err)
+	assert.Equal(t, ptx.Signatures, stx.Signatures)
+	assert.Equal(t, ptx.PackedContextFreeData, chain.Bytes{0x01, 0x02, 0xbe, 0xef})
+	unpacked, err := ptx.Transaction()
+	assert.NoError(t, err)
+	assert.Equal(t, *unpacked, tx)
+
+	ptx, err = stx.Pack(chain.CompressionZlib)
+	assert.NoError(t, err)
+	assert.Equal(t, ptx.Compression, chain.CompressionZlib)
+	id, err := ptx.ID()
+	assert.NoError(t, err)
+	assert.Equal(t, id, tx.ID())
 }
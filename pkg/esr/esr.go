@@ -0,0 +1,482 @@
+// Package esr implements EOSIO Signing Requests (ESR), the esr: links used by wallets such as Anchor
+// to request signatures for transactions and identity proofs.
+package esr
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var (
+	// Resolved to the signer actor, or the signer permission when used as permission.
+	PlaceholderName = chain.Name(1) // ............1
+	// Resolved to the signer permission.
+	PlaceholderPermission = chain.Name(2) // ............2
+)
+
+// Flags that can be set on a request.
+const (
+	// The wallet should broadcast the transaction after signing.
+	FlagBroadcast uint8 = 1 << 0
+	// The callback should be sent in the background.
+	FlagBackground uint8 = 1 << 1
+)
+
+var (
+	ErrInvalidVersion = errors.New("esr: unsupported protocol version")
+	ErrInvalidScheme  = errors.New("esr: invalid scheme, expected esr:")
+	ErrTooLarge       = errors.New("esr: decompressed payload too large")
+)
+
+// Maximum size of a decompressed request payload, guards against compression bombs.
+const MaxPayloadSize = 1 << 20
+
+// Aliases for well known chain ids.
+type ChainAlias uint8
+
+const (
+	ChainAliasReserved ChainAlias = iota
+	ChainAliasEOS
+	ChainAliasTelos
+	ChainAliasJungle
+	ChainAliasKylin
+	ChainAliasWorbli
+	ChainAliasBOS
+	ChainAliasMeetone
+	ChainAliasInsights
+	ChainAliasBEOS
+	ChainAliasWAX
+	ChainAliasProton
+	ChainAliasFIO
+)
+
+var chainAliases = map[ChainAlias]string{
+	ChainAliasEOS:      "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906",
+	ChainAliasTelos:    "4667b205c6838ef70ff7988f6e8257e8be0e1284a2f59699054a018f743b1d11",
+	ChainAliasJungle:   "e70aaab8997e1dfce58fbfac80cbbb8fecec7b99cf982a9444273cbc64c41473",
+	ChainAliasKylin:    "5fff1dae8dc8e2fc4d5b23b2c7665c97f9e9d8edf2b6485a86ba311c25639191",
+	ChainAliasWorbli:   "73647cde120091e0a4b85bced2f3cfdb3041e266cbbe95cee59b73235a1b3b6f",
+	ChainAliasBOS:      "d5a3d18fbb3c084e3b1f3fa98c21014b5f3db536cc15d08f9f6479517c6a3d86",
+	ChainAliasMeetone:  "cfe6486a83bad4962f232d48003b1824ab5665c36778141034d75e57b956e422",
+	ChainAliasInsights: "b042025541e25a472bffde2d62edd457b7e70cee943412b1ea0f044f88591664",
+	ChainAliasBEOS:     "b912d19a6abd2b1b05611ae5be473355d64d95aeff0c09bedc8c166cd6468fe4",
+	ChainAliasWAX:      "1064487b3cd1a897ce03ae5b6a865651747e2e152090f99c1d19d44e01aea5a4",
+	ChainAliasProton:   "384da888112027f0321850a169f737c33e53b388aad48b5adace4bab97f437e0",
+	ChainAliasFIO:      "21dcae42c0182200e93f954a074011f9048a7624c6fe81d3c9541a614a88bd1c",
+}
+
+// Chain id of the request, either an alias or a full chain id. Only one of the fields should be set.
+type ChainIDVariant struct {
+	Alias *ChainAlias
+	ID    *chain.Checksum256
+}
+
+// The request payload, only one of the fields should be set.
+type RequestVariant struct {
+	Action      *chain.Action
+	Actions     []chain.Action
+	Transaction *chain.Transaction
+	Identity    *Identity
+}
+
+// Request for a proof that the signer controls an account, see IdentityAction.
+type Identity struct {
+	// Only encoded in version 3 requests, the scope the identity is requested for, usually the app name.
+	Scope chain.Name `json:"scope"`
+	// Optional, the permission the signer should use. Any permission is accepted when nil.
+	Permission *chain.PermissionLevel `json:"permission"`
+}
+
+type InfoPair struct {
+	Key   string      `json:"key"`
+	Value chain.Bytes `json:"value"`
+}
+
+// Signature of the request creator, see SigningRequest.SignatureDigest.
+type RequestSignature struct {
+	Signer    chain.Name      `json:"signer"`
+	Signature chain.Signature `json:"signature"`
+}
+
+type SigningRequest struct {
+	// Protocol version, 2 or 3.
+	Version  uint8
+	ChainID  ChainIDVariant
+	Request  RequestVariant
+	Flags    uint8
+	Callback string
+	Info     []InfoPair
+	// Optional signature of the request creator, not part of the signed payload.
+	Signature *RequestSignature
+}
+
+// Create a chain id variant, using an alias if there is one for the chain id.
+func NewChainIDVariant(id chain.Checksum256) ChainIDVariant {
+	for alias, s := range chainAliases {
+		if s == id.String() {
+			alias := alias
+			return ChainIDVariant{Alias: &alias}
+		}
+	}
+	return ChainIDVariant{ID: &id}
+}
+
+// Decode a signing request from an esr: uri, the scheme is optional.
+func Decode(uri string) (*SigningRequest, error) {
+	if i := strings.Index(uri, ":"); i != -1 {
+		if uri[:i] != "esr" && uri[:i] != "web+esr" {
+			return nil, ErrInvalidScheme
+		}
+		uri = strings.TrimPrefix(uri[i+1:], "//")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(uri, "="))
+	if err != nil {
+		return nil, fmt.Errorf("esr: invalid payload: %w", err)
+	}
+	return DecodeBytes(data)
+}
+
+// Decode a signing request from its binary form, the header byte followed by the optionally compressed payload.
+func DecodeBytes(data []byte) (*SigningRequest, error) {
+	if len(data) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	header := data[0]
+	var r io.Reader = bytes.NewReader(data[1:])
+	if header&0x80 != 0 {
+		fr := flate.NewReader(r)
+		defer fr.Close()
+		payload, err := io.ReadAll(io.LimitReader(fr, MaxPayloadSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("esr: invalid payload: %w", err)
+		}
+		if len(payload) > MaxPayloadSize {
+			return nil, ErrTooLarge
+		}
+		r = bytes.NewReader(payload)
+	}
+	req := SigningRequest{Version: header & 0x7f}
+	err := req.UnmarshalABI(chain.NewDecoder(r))
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Encode the request as an esr: uri, with slashes the uri is in the esr://<payload> form.
+func (r SigningRequest) Encode(compress, slashes bool) (string, error) {
+	data, err := r.EncodeBytes(compress)
+	if err != nil {
+		return "", err
+	}
+	scheme := "esr:"
+	if slashes {
+		scheme = "esr://"
+	}
+	return scheme + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Encode the request to its binary form.
+func (r SigningRequest) EncodeBytes(compress bool) ([]byte, error) {
+	payload := bytes.NewBuffer(nil)
+	err := r.MarshalABI(chain.NewEncoder(payload))
+	if err != nil {
+		return nil, err
+	}
+	header := r.Version
+	if !compress {
+		return append([]byte{header}, payload.Bytes()...), nil
+	}
+	header |= 0x80
+	b := bytes.NewBuffer([]byte{header})
+	fw, err := flate.NewWriter(b, flate.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	_, err = fw.Write(payload.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	err = fw.Close()
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// The digest the request creator signs, see RequestSignature.
+func (r SigningRequest) SignatureDigest() (chain.Checksum256, error) {
+	unsigned := r
+	unsigned.Signature = nil
+	b := bytes.NewBuffer([]byte{r.Version, 'r', 'e', 'q', 'u', 'e', 's', 't'})
+	err := unsigned.MarshalABI(chain.NewEncoder(b))
+	if err != nil {
+		return chain.Checksum256{}, err
+	}
+	return chain.Checksum256Digest(b.Bytes()), nil
+}
+
+// The chain id of the request, resolving aliases.
+func (r SigningRequest) GetChainID() (chain.Checksum256, error) {
+	return r.ChainID.Resolve()
+}
+
+// True if the request is valid on any chain, only supported by version 3 requests.
+func (r SigningRequest) IsMultiChain() bool {
+	return r.ChainID.Alias != nil && *r.ChainID.Alias == ChainAliasReserved
+}
+
+func (r SigningRequest) IsIdentity() bool {
+	return r.Request.Identity != nil
+}
+
+func (r SigningRequest) ShouldBroadcast() bool {
+	return r.Flags&FlagBroadcast != 0 && !r.IsIdentity()
+}
+
+// Value of the info pair with given key, nil if not set.
+func (r SigningRequest) GetInfo(key string) chain.Bytes {
+	for _, pair := range r.Info {
+		if pair.Key == key {
+			return pair.Value
+		}
+	}
+	return nil
+}
+
+// Set the info pair with given key, replacing any existing value.
+func (r *SigningRequest) SetInfo(key string, value chain.Bytes) {
+	for i, pair := range r.Info {
+		if pair.Key == key {
+			r.Info[i].Value = value
+			return
+		}
+	}
+	r.Info = append(r.Info, InfoPair{Key: key, Value: value})
+}
+
+// The chain id, resolving aliases.
+func (v ChainIDVariant) Resolve() (chain.Checksum256, error) {
+	var rv chain.Checksum256
+	switch {
+	case v.ID != nil:
+		return *v.ID, nil
+	case v.Alias != nil:
+		s, ok := chainAliases[*v.Alias]
+		if !ok {
+			return rv, fmt.Errorf("esr: unknown chain alias %d", *v.Alias)
+		}
+		err := rv.UnmarshalText([]byte(s))
+		return rv, err
+	}
+	return rv, errors.New("esr: missing chain id")
+}
+
+// abi.Marshaler conformance
+
+func (r SigningRequest) MarshalABI(e *abi.Encoder) error {
+	if r.Version != 2 && r.Version != 3 {
+		return ErrInvalidVersion
+	}
+	err := r.ChainID.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = r.marshalRequest(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteUint8(r.Flags)
+	if err != nil {
+		return err
+	}
+	err = e.WriteString(r.Callback)
+	if err != nil {
+		return err
+	}
+	err = e.Encode(r.Info)
+	if err != nil {
+		return err
+	}
+	if r.Signature != nil {
+		err = e.Encode(*r.Signature)
+	}
+	return err
+}
+
+func (v ChainIDVariant) MarshalABI(e *abi.Encoder) error {
+	switch {
+	case v.Alias != nil:
+		err := e.WriteVaruint(0)
+		if err != nil {
+			return err
+		}
+		return e.WriteUint8(uint8(*v.Alias))
+	case v.ID != nil:
+		err := e.WriteVaruint(1)
+		if err != nil {
+			return err
+		}
+		return v.ID.MarshalABI(e)
+	}
+	return errors.New("esr: missing chain id")
+}
+
+// abi.Unmarshaler conformance
+
+func (r *SigningRequest) UnmarshalABI(d *abi.Decoder) error {
+	if r.Version != 2 && r.Version != 3 {
+		return ErrInvalidVersion
+	}
+	err := r.ChainID.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = r.unmarshalRequest(d)
+	if err != nil {
+		return err
+	}
+	r.Flags, err = d.ReadUint8()
+	if err != nil {
+		return err
+	}
+	r.Callback, err = d.ReadString()
+	if err != nil {
+		return err
+	}
+	err = d.Decode(&r.Info)
+	if err != nil {
+		return err
+	}
+	var sig RequestSignature
+	err = d.Decode(&sig)
+	if err == io.EOF {
+		r.Signature = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	r.Signature = &sig
+	return nil
+}
+
+func (v *ChainIDVariant) UnmarshalABI(d *abi.Decoder) error {
+	idx, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	*v = ChainIDVariant{}
+	switch idx {
+	case 0:
+		alias, err := d.ReadUint8()
+		if err != nil {
+			return err
+		}
+		v.Alias = (*ChainAlias)(&alias)
+	case 1:
+		v.ID = new(chain.Checksum256)
+		return v.ID.UnmarshalABI(d)
+	default:
+		return fmt.Errorf("esr: invalid chain id variant index %d", idx)
+	}
+	return nil
+}
+
+// helpers
+
+// The request variant is encoded by hand since the identity layout depends on the protocol version.
+func (r SigningRequest) marshalRequest(e *abi.Encoder) error {
+	req := r.Request
+	var err error
+	switch {
+	case req.Action != nil:
+		err = e.WriteVaruint(0)
+		if err == nil {
+			err = e.Encode(*req.Action)
+		}
+	case req.Actions != nil:
+		err = e.WriteVaruint(1)
+		if err == nil {
+			err = e.Encode(req.Actions)
+		}
+	case req.Transaction != nil:
+		err = e.WriteVaruint(2)
+		if err == nil {
+			err = e.Encode(*req.Transaction)
+		}
+	case req.Identity != nil:
+		err = e.WriteVaruint(3)
+		if err == nil {
+			err = req.Identity.marshal(e, r.Version)
+		}
+	default:
+		err = errors.New("esr: missing request")
+	}
+	return err
+}
+
+func (r *SigningRequest) unmarshalRequest(d *abi.Decoder) error {
+	idx, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	r.Request = RequestVariant{}
+	switch idx {
+	case 0:
+		r.Request.Action = new(chain.Action)
+		return d.Decode(r.Request.Action)
+	case 1:
+		err = d.Decode(&r.Request.Actions)
+		if err == nil && r.Request.Actions == nil {
+			r.Request.Actions = []chain.Action{}
+		}
+		return err
+	case 2:
+		r.Request.Transaction = new(chain.Transaction)
+		return d.Decode(r.Request.Transaction)
+	case 3:
+		r.Request.Identity = new(Identity)
+		return r.Request.Identity.unmarshal(d, r.Version)
+	default:
+		return fmt.Errorf("esr: invalid request variant index %d", idx)
+	}
+}
+
+func (id Identity) marshal(e *abi.Encoder, version uint8) error {
+	if version >= 3 {
+		err := id.Scope.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	err := e.WriteBool(id.Permission != nil)
+	if err != nil || id.Permission == nil {
+		return err
+	}
+	return e.Encode(*id.Permission)
+}
+
+func (id *Identity) unmarshal(d *abi.Decoder, version uint8) error {
+	*id = Identity{}
+	if version >= 3 {
+		err := id.Scope.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	exists, err := d.ReadBool()
+	if err != nil || !exists {
+		return err
+	}
+	id.Permission = new(chain.PermissionLevel)
+	return d.Decode(id.Permission)
+}
@@ -0,0 +1,151 @@
+package esr_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/hex"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/esr"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// placeholder transfer of 1.0000 EOS to foo with memo "hi"
+var testTransfer = chain.Action{
+	Account: chain.N("eosio.token"),
+	Name:    chain.N("transfer"),
+	Authorization: []chain.PermissionLevel{
+		{Actor: esr.PlaceholderName, Permission: esr.PlaceholderName},
+	},
+	Data: mustDecodeHex("0100000000000000000000000000285d102700000000000004454f5300000000026869"),
+}
+
+func testRequest() esr.SigningRequest {
+	alias := esr.ChainAliasEOS
+	action := testTransfer
+	return esr.SigningRequest{
+		Version:  2,
+		ChainID:  esr.ChainIDVariant{Alias: &alias},
+		Request:  esr.RequestVariant{Action: &action},
+		Flags:    esr.FlagBroadcast,
+		Callback: "https://example.com/cb?sig={{sig}}&tx={{tx}}&who={{sa}}@{{sp}}",
+		Info:     []esr.InfoPair{{Key: "foo", Value: chain.Bytes{0x01, 0x02}}},
+	}
+}
+
+func TestDecode(t *testing.T) {
+	expected := testRequest()
+	req, err := esr.Decode("esr:gmNgZGBY1mTC_MoglIGBIVzX5uxZRqAQGMBoZRgDAjRiBdQhLBZX_2AQzZSRyWiXUVJSUGylr59akZhbkJOql5yfq5-cZF-cmW5bXQ0ka2vVSiqAzJIKIKs8Ix8kmlhb6wCkCmprGZnT8vOZGJkA")
+	assert.NoError(t, err)
+	assert.Equal(t, *req, expected)
+
+	req, err = esr.Decode("AgABAACmgjQD6jBVAAAAVy08zc0BAQAAAAAAAAABAAAAAAAAACMBAAAAAAAAAAAAAAAAAChdECcAAAAAAAAERU9TAAAAAAJoaQE-aHR0cHM6Ly9leGFtcGxlLmNvbS9jYj9zaWc9e3tzaWd9fSZ0eD17e3R4fX0md2hvPXt7c2F9fUB7e3NwfX0BA2ZvbwIBAg")
+	assert.NoError(t, err)
+	assert.Equal(t, *req, expected)
+	assert.Equal(t, req.GetInfo("foo"), chain.Bytes{0x01, 0x02})
+	assert.True(t, req.GetInfo("bar") == nil)
+	assert.True(t, req.ShouldBroadcast())
+	chainID, err := req.GetChainID()
+	assert.NoError(t, err)
+	assert.Equal(t, chainID.String(), "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906")
+
+	_, err = esr.Decode("https://example.com")
+	assert.Equal(t, err, esr.ErrInvalidScheme)
+	_, err = esr.Decode("esr:AQAB")
+	assert.Equal(t, err, esr.ErrInvalidVersion)
+	_, err = esr.Decode("esr://AgABAACmgjQD6jBVAAAAVy08zc0BAQ")
+	assert.NotNil(t, err)
+
+	// compressed payloads are limited to MaxPayloadSize once decompressed
+	data := bytes.NewBuffer([]byte{0x82})
+	fw, _ := flate.NewWriter(data, flate.BestCompression)
+	fw.Write(make([]byte, esr.MaxPayloadSize+1))
+	fw.Close()
+	_, err = esr.DecodeBytes(data.Bytes())
+	assert.Equal(t, err, esr.ErrTooLarge)
+}
+
+func TestEncode(t *testing.T) {
+	req := testRequest()
+	uri, err := req.Encode(false, false)
+	assert.NoError(t, err)
+	assert.Equal(t, uri, "esr:AgABAACmgjQD6jBVAAAAVy08zc0BAQAAAAAAAAABAAAAAAAAACMBAAAAAAAAAAAAAAAAAChdECcAAAAAAAAERU9TAAAAAAJoaQE-aHR0cHM6Ly9leGFtcGxlLmNvbS9jYj9zaWc9e3tzaWd9fSZ0eD17e3R4fX0md2hvPXt7c2F9fUB7e3NwfX0BA2ZvbwIBAg")
+
+	uri, err = req.Encode(true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, uri[:6], "esr://")
+	decoded, err := esr.Decode(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, *decoded, req)
+
+	// chain ids with an alias are encoded using it
+	id := chain.Checksum256{}
+	assert.NoError(t, id.UnmarshalText([]byte("1064487b3cd1a897ce03ae5b6a865651747e2e152090f99c1d19d44e01aea5a4")))
+	assert.Equal(t, *esr.NewChainIDVariant(id).Alias, esr.ChainAliasWAX)
+	id[0] = 0
+	req.ChainID = esr.NewChainIDVariant(id)
+	assert.Equal(t, *req.ChainID.ID, id)
+	req.Request = esr.RequestVariant{Actions: []chain.Action{testTransfer, testTransfer}}
+	req.SetInfo("foo", chain.Bytes{0x03})
+	req.SetInfo("bar", chain.Bytes{})
+	req.Signature = &esr.RequestSignature{
+		Signer:    chain.N("foo"),
+		Signature: *chain.NewSignature(chain.K1, make([]byte, 65)),
+	}
+	uri, err = req.Encode(true, false)
+	assert.NoError(t, err)
+	decoded, err = esr.Decode(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, *decoded, req)
+	assert.Equal(t, decoded.GetInfo("foo"), chain.Bytes{0x03})
+
+	// the request signature is not part of the signed digest
+	digest, err := req.SignatureDigest()
+	assert.NoError(t, err)
+	req.Signature = nil
+	unsignedDigest, err := req.SignatureDigest()
+	assert.NoError(t, err)
+	assert.Equal(t, digest, unsignedDigest)
+
+	req.Version = 1
+	_, err = req.Encode(true, true)
+	assert.Equal(t, err, esr.ErrInvalidVersion)
+}
+
+func TestIdentity(t *testing.T) {
+	alias := esr.ChainAliasEOS
+	v2 := esr.SigningRequest{
+		Version:  2,
+		ChainID:  esr.ChainIDVariant{Alias: &alias},
+		Request:  esr.RequestVariant{Identity: &esr.Identity{}},
+		Flags:    esr.FlagBroadcast,
+		Callback: "https://example.com",
+	}
+	b, err := v2.EncodeBytes(false)
+	assert.NoError(t, err)
+	assert.Equal(t, b, mustDecodeHex("020001030001"+"1368747470733a2f2f6578616d706c652e636f6d"+"00"))
+	assert.True(t, !v2.ShouldBroadcast())
+
+	v3 := v2
+	v3.Version = 3
+	v3.Request = esr.RequestVariant{Identity: &esr.Identity{
+		Scope:      chain.N("foo"),
+		Permission: &chain.PermissionLevel{Actor: chain.N("foo"), Permission: chain.N("active")},
+	}}
+	for _, req := range []esr.SigningRequest{v2, v3} {
+		uri, err := req.Encode(true, true)
+		assert.NoError(t, err)
+		decoded, err := esr.Decode(uri)
+		assert.NoError(t, err)
+		assert.Equal(t, *decoded, req)
+	}
+}
@@ -0,0 +1,197 @@
+package esr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var (
+	ErrProofExpired         = errors.New("esr: identity proof expired")
+	ErrProofWrongSigningKey = errors.New("esr: identity proof not signed by a key that satisfies the signer authority")
+)
+
+// Proof that the signer controls an account permission, created by signing a version 3 identity request.
+// Encoded as text in the EOSIO <base64url> form.
+type IdentityProof struct {
+	ChainID    chain.Checksum256
+	Scope      chain.Name
+	Expiration chain.TimePointSec
+	Signer     chain.PermissionLevel
+	Signature  chain.Signature
+}
+
+// Looks up the authority of an account permission, e.g. using the get_account API.
+type AuthorityProvider interface {
+	GetAuthority(ctx context.Context, level chain.PermissionLevel) (*chain.Authority, error)
+}
+
+// Create an identity proof from its string form, e.g. EOSIO <base64url>.
+func NewIdentityProofFromString(s string) (*IdentityProof, error) {
+	var rv IdentityProof
+	err := rv.UnmarshalText([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// Create an identity proof from the payload of a signed identity request callback, see ResolvedRequest.GetCallback.
+func NewIdentityProofFromCallback(payload map[string]string) (*IdentityProof, error) {
+	req, err := Decode(payload["req"])
+	if err != nil {
+		return nil, err
+	}
+	if req.Version < 3 || !req.IsIdentity() {
+		return nil, errors.New("esr: not a version 3 identity request")
+	}
+	var rv IdentityProof
+	if cid, ok := payload["cid"]; ok {
+		err = rv.ChainID.UnmarshalText([]byte(cid))
+	} else {
+		rv.ChainID, err = req.GetChainID()
+	}
+	if err != nil {
+		return nil, err
+	}
+	rv.Scope = req.Request.Identity.Scope
+	rv.Expiration, err = chain.NewTimePointSecFromString(payload["ex"])
+	if err != nil {
+		return nil, err
+	}
+	rv.Signer.Actor, err = chain.NewNameFromString(payload["sa"])
+	if err != nil {
+		return nil, err
+	}
+	rv.Signer.Permission, err = chain.NewNameFromString(payload["sp"])
+	if err != nil {
+		return nil, err
+	}
+	sig, err := chain.NewSignatureString(payload["sig"])
+	if err != nil {
+		return nil, err
+	}
+	rv.Signature = *sig
+	return &rv, nil
+}
+
+// The transaction signed to create the proof, it can never be broadcast since it has no reference block.
+func (p IdentityProof) Transaction() chain.Transaction {
+	data := bytes.NewBuffer(nil)
+	err := Identity{Scope: p.Scope, Permission: &p.Signer}.marshal(chain.NewEncoder(data), 3)
+	if err != nil {
+		panic(err)
+	}
+	return chain.Transaction{
+		TransactionHeader: chain.TransactionHeader{Expiration: p.Expiration},
+		Actions: []chain.Action{
+			*chain.NewAction(0, chain.N("identity"), []chain.PermissionLevel{p.Signer}, data.Bytes()),
+		},
+	}
+}
+
+// Recover the key that signed the proof.
+func (p IdentityProof) Recover() (*chain.PublicKey, error) {
+	return p.Signature.RecoverDigest(p.Transaction().SigningDigest(p.ChainID))
+}
+
+// Verify that the proof has not expired at given time and that it is signed by a key that satisfies the authority.
+func (p IdentityProof) Verify(auth chain.Authority, now time.Time) error {
+	if !now.Before(p.Expiration.Time()) {
+		return ErrProofExpired
+	}
+	key, err := p.Recover()
+	if err != nil {
+		return err
+	}
+	if !auth.HasPermission(*key) {
+		return ErrProofWrongSigningKey
+	}
+	return nil
+}
+
+// Verify the proof against the current authority of the signer, see Verify.
+func (p IdentityProof) VerifyAccount(ctx context.Context, provider AuthorityProvider, now time.Time) error {
+	auth, err := provider.GetAuthority(ctx, p.Signer)
+	if err != nil {
+		return err
+	}
+	return p.Verify(*auth, now)
+}
+
+func (p IdentityProof) String() string {
+	b := bytes.NewBuffer(nil)
+	err := p.MarshalABI(chain.NewEncoder(b))
+	if err != nil {
+		panic(err)
+	}
+	return "EOSIO " + base64.RawURLEncoding.EncodeToString(b.Bytes())
+}
+
+// abi.Marshaler conformance
+
+func (p IdentityProof) MarshalABI(e *abi.Encoder) error {
+	err := p.ChainID.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = p.Scope.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = p.Expiration.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = p.Signer.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	return p.Signature.MarshalABI(e)
+}
+
+// abi.Unmarshaler conformance
+
+func (p *IdentityProof) UnmarshalABI(d *abi.Decoder) error {
+	err := p.ChainID.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = p.Scope.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = p.Expiration.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	err = p.Signer.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	return p.Signature.UnmarshalABI(d)
+}
+
+// encoding.TextMarshaler conformance
+
+func (p IdentityProof) MarshalText() (text []byte, err error) {
+	return []byte(p.String()), nil
+}
+
+// encoding.TextUnmarshaler conformance
+
+func (p *IdentityProof) UnmarshalText(text []byte) error {
+	s := strings.TrimPrefix(string(text), "EOSIO ")
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return fmt.Errorf("esr: invalid identity proof: %w", err)
+	}
+	return p.UnmarshalABI(chain.NewDecoder(bytes.NewReader(data)))
+}
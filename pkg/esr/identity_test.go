@@ -0,0 +1,110 @@
+package esr_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/esr"
+)
+
+func testSigningKey(seed string) (*secp256k1.PrivateKey, chain.PublicKey) {
+	d := chain.Checksum256Digest([]byte(seed))
+	priv := secp256k1.PrivKeyFromBytes(d[:])
+	return priv, *chain.NewPublicKey(chain.K1, priv.PubKey().SerializeCompressed())
+}
+
+type testAuthorityProvider map[chain.PermissionLevel]chain.Authority
+
+func (p testAuthorityProvider) GetAuthority(ctx context.Context, level chain.PermissionLevel) (*chain.Authority, error) {
+	auth, ok := p[level]
+	if !ok {
+		return nil, errors.New("unknown account")
+	}
+	return &auth, nil
+}
+
+func TestIdentityProof(t *testing.T) {
+	priv, pub := testSigningKey("alice")
+	_, otherPub := testSigningKey("bob")
+	signer := chain.PermissionLevel{Actor: chain.N("alice"), Permission: chain.N("active")}
+	expiration := chain.TimePointSec(1600000000)
+
+	alias := esr.ChainAliasEOS
+	req := esr.SigningRequest{
+		Version:  3,
+		ChainID:  esr.ChainIDVariant{Alias: &alias},
+		Request:  esr.RequestVariant{Identity: &esr.Identity{Scope: chain.N("myapp")}},
+		Callback: "https://example.com/login",
+	}
+	resolved, err := req.Resolve(nil, signer, chain.TransactionHeader{Expiration: expiration})
+	assert.NoError(t, err)
+	digest := resolved.Transaction.SigningDigest(resolved.ChainID)
+	sig := *chain.NewSignature(chain.K1, ecdsa.SignCompact(priv, digest[:], true))
+	cb, err := resolved.GetCallback([]chain.Signature{sig}, 0)
+	assert.NoError(t, err)
+
+	proof, err := esr.NewIdentityProofFromCallback(cb.Payload)
+	assert.NoError(t, err)
+	assert.Equal(t, *proof, esr.IdentityProof{
+		ChainID:    resolved.ChainID,
+		Scope:      chain.N("myapp"),
+		Expiration: expiration,
+		Signer:     signer,
+		Signature:  sig,
+	})
+	assert.Equal(t, proof.Transaction(), resolved.Transaction)
+
+	key, err := proof.Recover()
+	assert.NoError(t, err)
+	assert.Equal(t, *key, pub)
+
+	before := expiration.Time().Add(-time.Minute)
+	auth := chain.Authority{Threshold: 1, Keys: []chain.KeyWeight{{Key: pub, Weight: 1}}}
+	assert.NoError(t, proof.Verify(auth, before))
+	assert.Equal(t, proof.Verify(auth, expiration.Time()), esr.ErrProofExpired)
+	otherAuth := chain.Authority{Threshold: 1, Keys: []chain.KeyWeight{{Key: otherPub, Weight: 1}}}
+	assert.Equal(t, proof.Verify(otherAuth, before), esr.ErrProofWrongSigningKey)
+	assert.Equal(t, proof.Verify(chain.Authority{}, before), esr.ErrProofWrongSigningKey)
+	assert.Equal(t, proof.Verify(chain.Authority{Keys: auth.Keys}, before), esr.ErrProofWrongSigningKey)
+
+	provider := testAuthorityProvider{signer: auth}
+	assert.NoError(t, proof.VerifyAccount(context.Background(), provider, before))
+	proof.Signer.Permission = chain.N("owner")
+	assert.NotNil(t, proof.VerifyAccount(context.Background(), provider, before))
+	proof.Signer.Permission = chain.N("active")
+
+	// string encoding
+	s := proof.String()
+	assert.Equal(t, s[:6], "EOSIO ")
+	decoded, err := esr.NewIdentityProofFromString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, *decoded, *proof)
+	assert.JSONCoding(t, struct {
+		Proof esr.IdentityProof `json:"proof"`
+	}{*proof}, `{"proof": "`+s+`"}`)
+	_, err = esr.NewIdentityProofFromString("EOSIO AAAA")
+	assert.NotNil(t, err)
+
+	// invalid signer names are rejected instead of being mangled
+	payload := map[string]string{}
+	for k, v := range cb.Payload {
+		payload[k] = v
+	}
+	payload["sa"] = "Alice"
+	_, err = esr.NewIdentityProofFromCallback(payload)
+	assert.True(t, errors.Is(err, chain.ErrInvalidName))
+
+	// only version 3 identity requests create proofs
+	req.Version = 2
+	uri, err := req.Encode(true, true)
+	assert.NoError(t, err)
+	cb.Payload["req"] = uri
+	_, err = esr.NewIdentityProofFromCallback(cb.Payload)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,271 @@
+package esr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Signing request resolved for a signer, ready to be signed.
+type ResolvedRequest struct {
+	Request     *SigningRequest
+	Signer      chain.PermissionLevel
+	ChainID     chain.Checksum256
+	Transaction chain.Transaction
+}
+
+// Callback to send after the resolved request has been signed.
+type Callback struct {
+	URL        string
+	Background bool
+	Payload    map[string]string
+}
+
+var callbackTemplate = regexp.MustCompile(`{{([a-z0-9]+)}}`)
+
+// Accounts whose ABIs are used to resolve the placeholders in the action data, all of them are required
+// by Resolve. Identity requests need no ABIs.
+func (r SigningRequest) RequiredAbis() []chain.Name {
+	var rv []chain.Name
+	actions, _ := r.RawActions()
+	for _, action := range actions {
+		if action.Account == 0 {
+			continue
+		}
+		seen := false
+		for _, account := range rv {
+			seen = seen || account == action.Account
+		}
+		if !seen {
+			rv = append(rv, action.Account)
+		}
+	}
+	return rv
+}
+
+// Actions of the request without resolving placeholders, identity requests produce an identity action.
+func (r SigningRequest) RawActions() ([]chain.Action, error) {
+	req := r.Request
+	switch {
+	case req.Action != nil:
+		return []chain.Action{*req.Action}, nil
+	case req.Actions != nil:
+		return req.Actions, nil
+	case req.Transaction != nil:
+		return req.Transaction.Actions, nil
+	case req.Identity != nil:
+		action, err := r.IdentityAction()
+		if err != nil {
+			return nil, err
+		}
+		return []chain.Action{*action}, nil
+	}
+	return nil, errors.New("esr: missing request")
+}
+
+// The action signed for identity requests, the identity action of the zero account.
+func (r SigningRequest) IdentityAction() (*chain.Action, error) {
+	id := r.Request.Identity
+	if id == nil {
+		return nil, errors.New("esr: not an identity request")
+	}
+	permission := chain.PermissionLevel{Actor: PlaceholderName, Permission: PlaceholderPermission}
+	if id.Permission != nil {
+		permission = *id.Permission
+	}
+	data := bytes.NewBuffer(nil)
+	err := Identity{Scope: id.Scope, Permission: &permission}.marshal(chain.NewEncoder(data), r.Version)
+	if err != nil {
+		return nil, err
+	}
+	return chain.NewAction(0, chain.N("identity"), []chain.PermissionLevel{permission}, data.Bytes()), nil
+}
+
+// Resolve the request for given signer. The ABIs of the contracts listed by RequiredAbis are used
+// to resolve placeholders in the action data, mapping an account to a nil ABI opts out and leaves
+// the data of its actions as is, unless they are authorized by a placeholder. The expiration and
+// reference block fields of the tapos header are used if the request does not specify them, identity
+// requests only use the expiration which is required for version 3 identity requests.
+func (r SigningRequest) Resolve(abis map[chain.Name]*chain.Abi, signer chain.PermissionLevel, tapos chain.TransactionHeader) (*ResolvedRequest, error) {
+	chainID, err := r.GetChainID()
+	if err != nil {
+		return nil, err
+	}
+	var tx chain.Transaction
+	if r.Request.Transaction != nil {
+		tx = *r.Request.Transaction
+	}
+	actions, err := r.RawActions()
+	if err != nil {
+		return nil, err
+	}
+	if r.Request.Identity != nil {
+		// identity action data is resolved without an abi
+		actions[0].Data, err = resolveIdentityData(r.Version, *r.Request.Identity, signer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	tx.Actions = make([]chain.Action, len(actions))
+	for i, action := range actions {
+		resolved, err := resolveAction(action, abis, signer, r.Request.Identity == nil)
+		if err != nil {
+			return nil, err
+		}
+		tx.Actions[i] = *resolved
+	}
+	if r.IsIdentity() {
+		// version 3 identity proofs expire, see IdentityProof
+		if r.Version >= 3 {
+			if tapos.Expiration == 0 {
+				return nil, errors.New("esr: missing expiration for identity request")
+			}
+			tx.Expiration = tapos.Expiration
+		}
+	} else if tx.Expiration == 0 && tx.RefBlockNum == 0 && tx.RefBlockPrefix == 0 {
+		if tapos.Expiration == 0 {
+			return nil, errors.New("esr: missing tapos values")
+		}
+		tx.Expiration = tapos.Expiration
+		tx.RefBlockNum = tapos.RefBlockNum
+		tx.RefBlockPrefix = tapos.RefBlockPrefix
+	}
+	return &ResolvedRequest{
+		Request:     &r,
+		Signer:      signer,
+		ChainID:     chainID,
+		Transaction: tx,
+	}, nil
+}
+
+// The callback for the signed request, nil if the request has no callback. The block number is
+// included in the payload if non-zero, it should be set when the transaction was broadcast.
+func (rr ResolvedRequest) GetCallback(signatures []chain.Signature, blockNum uint32) (*Callback, error) {
+	if rr.Request.Callback == "" {
+		return nil, nil
+	}
+	if len(signatures) == 0 {
+		return nil, errors.New("esr: must have at least one signature to resolve callback")
+	}
+	uri, err := rr.Request.Encode(true, true)
+	if err != nil {
+		return nil, err
+	}
+	tx := rr.Transaction
+	payload := map[string]string{
+		"sig": signatures[0].String(),
+		"tx":  tx.ID().String(),
+		"rbn": strconv.Itoa(int(tx.RefBlockNum)),
+		"rid": strconv.FormatUint(uint64(tx.RefBlockPrefix), 10),
+		"ex":  tx.Expiration.String(),
+		"req": uri,
+		"sa":  rr.Signer.Actor.String(),
+		"sp":  rr.Signer.Permission.String(),
+	}
+	for i, sig := range signatures[1:] {
+		payload["sig"+strconv.Itoa(i+1)] = sig.String()
+	}
+	if blockNum != 0 {
+		payload["bn"] = strconv.FormatUint(uint64(blockNum), 10)
+	}
+	if rr.Request.IsMultiChain() {
+		payload["cid"] = rr.ChainID.String()
+	}
+	url := callbackTemplate.ReplaceAllStringFunc(rr.Request.Callback, func(m string) string {
+		return payload[m[2:len(m)-2]]
+	})
+	return &Callback{
+		URL:        url,
+		Background: rr.Request.Flags&FlagBackground != 0,
+		Payload:    payload,
+	}, nil
+}
+
+// helpers
+
+func resolveIdentityData(version uint8, id Identity, signer chain.PermissionLevel) (chain.Bytes, error) {
+	permission := signer
+	if id.Permission != nil {
+		permission = resolvePermission(*id.Permission, signer)
+	}
+	data := bytes.NewBuffer(nil)
+	err := Identity{Scope: id.Scope, Permission: &permission}.marshal(chain.NewEncoder(data), version)
+	return data.Bytes(), err
+}
+
+func resolvePermission(level chain.PermissionLevel, signer chain.PermissionLevel) chain.PermissionLevel {
+	if level.Actor == PlaceholderName {
+		level.Actor = signer.Actor
+	}
+	if level.Permission == PlaceholderName || level.Permission == PlaceholderPermission {
+		level.Permission = signer.Permission
+	}
+	return level
+}
+
+func resolveAction(action chain.Action, abis map[chain.Name]*chain.Abi, signer chain.PermissionLevel, resolveData bool) (*chain.Action, error) {
+	rv := action
+	rv.Authorization = make([]chain.PermissionLevel, len(action.Authorization))
+	authorizedBySigner := false
+	for i, level := range action.Authorization {
+		rv.Authorization[i] = resolvePermission(level, signer)
+		authorizedBySigner = authorizedBySigner || rv.Authorization[i] != level
+	}
+	if !resolveData {
+		return &rv, nil
+	}
+	// placeholders in the data can only be found using the abi, the caller can opt out with a nil abi
+	// except for actions authorized by the signer since their data is likely to reference the signer
+	a, ok := abis[action.Account]
+	if a == nil {
+		if !ok || authorizedBySigner {
+			return nil, fmt.Errorf("esr: missing abi for %s", action.Account)
+		}
+		return &rv, nil
+	}
+	decoded, err := a.DecodeAction(bytes.NewReader(action.Data), action.Name)
+	if err != nil {
+		return nil, err
+	}
+	resolved, changed := resolveValue(decoded, signer)
+	if !changed {
+		return &rv, nil
+	}
+	data := bytes.NewBuffer(nil)
+	err = a.EncodeAction(data, action.Name, resolved)
+	if err != nil {
+		return nil, err
+	}
+	rv.Data = data.Bytes()
+	return &rv, nil
+}
+
+// replace placeholder names in a decoded value, changed is true if any were found
+func resolveValue(v interface{}, signer chain.PermissionLevel) (rv interface{}, changed bool) {
+	switch v := v.(type) {
+	case chain.Name:
+		switch v {
+		case PlaceholderName:
+			return signer.Actor, true
+		case PlaceholderPermission:
+			return signer.Permission, true
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			var c bool
+			v[key], c = resolveValue(value, signer)
+			changed = changed || c
+		}
+	case []interface{}:
+		for i, value := range v {
+			var c bool
+			v[i], c = resolveValue(value, signer)
+			changed = changed || c
+		}
+	}
+	return v, changed
+}
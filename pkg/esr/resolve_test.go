@@ -0,0 +1,196 @@
+package esr_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/esr"
+)
+
+var testTokenAbi = `{
+	"version": "eosio::abi/1.1",
+	"structs": [{
+		"name": "transfer",
+		"base": "",
+		"fields": [
+			{"name": "from", "type": "name"},
+			{"name": "to", "type": "name"},
+			{"name": "quantity", "type": "asset"},
+			{"name": "memo", "type": "string"}
+		]
+	}],
+	"actions": [{"name": "transfer", "type": "transfer", "ricardian_contract": ""}]
+}`
+
+func testAbis() map[chain.Name]*chain.Abi {
+	var a chain.Abi
+	err := json.Unmarshal([]byte(testTokenAbi), &a)
+	if err != nil {
+		panic(err)
+	}
+	return map[chain.Name]*chain.Abi{chain.N("eosio.token"): &a}
+}
+
+func TestResolve(t *testing.T) {
+	signer := chain.PermissionLevel{Actor: chain.N("alice"), Permission: chain.N("active")}
+	tapos := chain.TransactionHeader{
+		Expiration:     chain.TimePointSec(1600000000),
+		RefBlockNum:    1234,
+		RefBlockPrefix: 56789,
+	}
+	req := testRequest()
+	assert.Equal(t, req.RequiredAbis(), []chain.Name{chain.N("eosio.token")})
+
+	resolved, err := req.Resolve(testAbis(), signer, tapos)
+	assert.NoError(t, err)
+	assert.Equal(t, resolved.Transaction, chain.Transaction{
+		TransactionHeader: tapos,
+		Actions: []chain.Action{{
+			Account:       chain.N("eosio.token"),
+			Name:          chain.N("transfer"),
+			Authorization: []chain.PermissionLevel{signer},
+			Data:          mustDecodeHex("0000000000855c34000000000000285d102700000000000004454f5300000000026869"),
+		}},
+	})
+	// the request is not modified
+	assert.Equal(t, req.Request.Action.Authorization[0].Actor, esr.PlaceholderName)
+
+	_, err = req.Resolve(testAbis(), signer, chain.TransactionHeader{})
+	assert.NotNil(t, err)
+	_, err = req.Resolve(nil, signer, tapos)
+	assert.NotNil(t, err)
+
+	// tapos of full transactions is kept
+	tx := chain.Transaction{
+		TransactionHeader: chain.TransactionHeader{Expiration: 1, RefBlockNum: 2, RefBlockPrefix: 3},
+		Actions: []chain.Action{{
+			Account:       chain.N("eosio.token"),
+			Name:          chain.N("transfer"),
+			Authorization: []chain.PermissionLevel{{Actor: chain.N("foo"), Permission: esr.PlaceholderPermission}},
+			Data:          mustDecodeHex("0000000000855c34000000000000285d102700000000000004454f5300000000026869"),
+		}},
+	}
+	req.Request = esr.RequestVariant{Transaction: &tx}
+	resolved, err = req.Resolve(testAbis(), signer, tapos)
+	assert.NoError(t, err)
+	assert.Equal(t, resolved.Transaction.TransactionHeader, tx.TransactionHeader)
+	assert.Equal(t, resolved.Transaction.Actions[0].Authorization[0], chain.PermissionLevel{Actor: chain.N("foo"), Permission: chain.N("active")})
+	assert.Equal(t, resolved.Transaction.Actions[0].Data, tx.Actions[0].Data)
+	// actions authorized by the signer require the abi, even when opted out
+	_, err = req.Resolve(nil, signer, tapos)
+	assert.NotNil(t, err)
+	_, err = req.Resolve(map[chain.Name]*chain.Abi{chain.N("eosio.token"): nil}, signer, tapos)
+	assert.NotNil(t, err)
+}
+
+func TestResolvePackedValues(t *testing.T) {
+	signer := chain.PermissionLevel{Actor: chain.N("alice"), Permission: chain.N("active")}
+	tapos := chain.TransactionHeader{Expiration: chain.TimePointSec(1600000000)}
+	// transfer of 0.0001 EOS, the amount packs the same as the placeholder name
+	data := mustDecodeHex("0000000000855c34000000000000285d010000000000000004454f5300000000026869")
+	alias := esr.ChainAliasEOS
+	req := esr.SigningRequest{
+		Version: 2,
+		ChainID: esr.ChainIDVariant{Alias: &alias},
+		Request: esr.RequestVariant{Action: &chain.Action{
+			Account:       chain.N("eosio.token"),
+			Name:          chain.N("transfer"),
+			Authorization: []chain.PermissionLevel{{Actor: chain.N("alice"), Permission: chain.N("active")}},
+			Data:          data,
+		}},
+	}
+	// the abi is required even without placeholders in the authorization
+	_, err := req.Resolve(nil, signer, tapos)
+	assert.NotNil(t, err)
+	// unless explicitly opted out, the data is then kept as is
+	resolved, err := req.Resolve(map[chain.Name]*chain.Abi{chain.N("eosio.token"): nil}, signer, tapos)
+	assert.NoError(t, err)
+	assert.Equal(t, resolved.Transaction.Actions[0].Data, chain.Bytes(data))
+	// and the abi finds no placeholders in the data
+	resolved, err = req.Resolve(testAbis(), signer, tapos)
+	assert.NoError(t, err)
+	assert.Equal(t, resolved.Transaction.Actions[0].Data, chain.Bytes(data))
+}
+
+func TestResolveIdentity(t *testing.T) {
+	signer := chain.PermissionLevel{Actor: chain.N("alice"), Permission: chain.N("active")}
+	for version, data := range map[uint8]string{
+		2: "010000000000855c3400000000a8ed3232",
+		3: "000000000000285d010000000000855c3400000000a8ed3232",
+	} {
+		alias := esr.ChainAliasEOS
+		req := esr.SigningRequest{
+			Version: version,
+			ChainID: esr.ChainIDVariant{Alias: &alias},
+			Request: esr.RequestVariant{Identity: &esr.Identity{Scope: chain.N("foo")}},
+		}
+		assert.True(t, req.RequiredAbis() == nil)
+		resolved, err := req.Resolve(nil, signer, chain.TransactionHeader{Expiration: 1, RefBlockNum: 2})
+		assert.NoError(t, err)
+		var expiration chain.TimePointSec
+		if version == 3 {
+			expiration = 1
+		}
+		assert.Equal(t, resolved.Transaction, chain.Transaction{
+			TransactionHeader: chain.TransactionHeader{Expiration: expiration},
+			Actions: []chain.Action{{
+				Account:       0,
+				Name:          chain.N("identity"),
+				Authorization: []chain.PermissionLevel{signer},
+				Data:          mustDecodeHex(data),
+			}},
+		})
+	}
+}
+
+func TestResolveIdentityExpiration(t *testing.T) {
+	signer := chain.PermissionLevel{Actor: chain.N("alice"), Permission: chain.N("active")}
+	alias := esr.ChainAliasEOS
+	req := esr.SigningRequest{
+		Version: 3,
+		ChainID: esr.ChainIDVariant{Alias: &alias},
+		Request: esr.RequestVariant{Identity: &esr.Identity{Scope: chain.N("foo")}},
+	}
+	_, err := req.Resolve(nil, signer, chain.TransactionHeader{})
+	assert.NotNil(t, err)
+	expiration := chain.NewTimePointSec(time.Date(2020, 9, 13, 12, 27, 40, 0, time.UTC))
+	resolved, err := req.Resolve(nil, signer, chain.TransactionHeader{Expiration: expiration})
+	assert.NoError(t, err)
+	assert.Equal(t, resolved.Transaction.Expiration, expiration)
+	// version 2 identity proofs don't expire
+	req.Version = 2
+	resolved, err = req.Resolve(nil, signer, chain.TransactionHeader{})
+	assert.NoError(t, err)
+	assert.Equal(t, resolved.Transaction.Expiration, chain.TimePointSec(0))
+}
+
+func TestCallback(t *testing.T) {
+	signer := chain.PermissionLevel{Actor: chain.N("alice"), Permission: chain.N("active")}
+	req := testRequest()
+	resolved, err := req.Resolve(testAbis(), signer, chain.TransactionHeader{Expiration: chain.TimePointSec(1600000000)})
+	assert.NoError(t, err)
+
+	sig := *chain.NewSignature(chain.K1, make([]byte, 65))
+	_, err = resolved.GetCallback(nil, 0)
+	assert.NotNil(t, err)
+	cb, err := resolved.GetCallback([]chain.Signature{sig, sig}, 100)
+	assert.NoError(t, err)
+	txID := resolved.Transaction.ID().String()
+	assert.Equal(t, cb.URL, "https://example.com/cb?sig="+sig.String()+"&tx="+txID+"&who=alice@active")
+	assert.True(t, !cb.Background)
+	assert.Equal(t, cb.Payload["sig1"], sig.String())
+	assert.Equal(t, cb.Payload["bn"], "100")
+	assert.Equal(t, cb.Payload["ex"], "2020-09-13T12:26:40")
+	decoded, err := esr.Decode(cb.Payload["req"])
+	assert.NoError(t, err)
+	assert.Equal(t, *decoded, req)
+
+	req.Callback = ""
+	resolved.Request = &req
+	cb, err = resolved.GetCallback([]chain.Signature{sig}, 0)
+	assert.NoError(t, err)
+	assert.True(t, cb == nil)
+}
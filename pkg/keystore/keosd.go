@@ -0,0 +1,192 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Decrypt the keys of a keosd wallet file (the JSON file holding cipher_keys).
+func ReadKeosdWallet(data []byte, password string) ([]chain.PrivateKey, error) {
+	var wallet keosdWallet
+	err := json.Unmarshal(data, &wallet)
+	if err != nil {
+		return nil, err
+	}
+	checksum := chain.Checksum512Digest([]byte(password))
+	plaintext, err := keosdDecrypt(checksum, wallet.CipherKeys)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	var keys keosdPlainKeys
+	err = chain.NewDecoder(bytes.NewReader(plaintext)).Decode(&keys)
+	if err != nil {
+		return nil, err
+	}
+	if keys.Checksum != checksum {
+		return nil, ErrInvalidPassword
+	}
+	rv := make([]chain.PrivateKey, len(keys.Keys))
+	for i, pair := range keys.Keys {
+		rv[i] = pair.Private
+	}
+	return rv, nil
+}
+
+// Create a keosd wallet file containing keys encrypted with password, only K1 keys are supported.
+func WriteKeosdWallet(keys []chain.PrivateKey, password string) ([]byte, error) {
+	plain := keosdPlainKeys{
+		Checksum: chain.Checksum512Digest([]byte(password)),
+		Keys:     make([]keosdKeyPair, len(keys)),
+	}
+	for i, key := range keys {
+		pub, err := key.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		plain.Keys[i] = keosdKeyPair{*pub, key}
+	}
+	// keys are stored in a std::map ordered by public key
+	sort.Slice(plain.Keys, func(i, j int) bool {
+		a, b := plain.Keys[i].Public, plain.Keys[j].Public
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return bytes.Compare(a.Data, b.Data) < 0
+	})
+	b := bytes.NewBuffer(nil)
+	err := chain.NewEncoder(b).Encode(plain)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := keosdEncrypt(plain.Checksum, b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(keosdWallet{CipherKeys: ciphertext})
+}
+
+// Import all keys from a keosd wallet file into the keystore, keys already in the keystore are skipped.
+func (ks *Keystore) ImportKeosdWallet(data []byte, password string) ([]chain.PublicKey, error) {
+	keys, err := ReadKeosdWallet(data, password)
+	if err != nil {
+		return nil, err
+	}
+	var rv []chain.PublicKey
+	for _, key := range keys {
+		pub, err := ks.ImportKey(key)
+		if err == ErrKeyExists {
+			continue
+		}
+		if err != nil {
+			return rv, err
+		}
+		rv = append(rv, *pub)
+	}
+	return rv, nil
+}
+
+// Export all keys in the keystore as a keosd wallet file.
+func (ks *Keystore) ExportKeosdWallet(password string) ([]byte, error) {
+	keys, err := ks.Keys()
+	if err != nil {
+		return nil, err
+	}
+	return WriteKeosdWallet(keys, password)
+}
+
+// helpers
+
+type keosdWallet struct {
+	CipherKeys chain.Bytes `json:"cipher_keys"`
+}
+
+type keosdKeyPair struct {
+	Public  chain.PublicKey
+	Private chain.PrivateKey
+}
+
+type keosdPlainKeys struct {
+	Checksum chain.Checksum512
+	Keys     []keosdKeyPair
+}
+
+func (pk keosdPlainKeys) MarshalABI(e *abi.Encoder) error {
+	err := pk.Checksum.MarshalABI(e)
+	if err != nil {
+		return err
+	}
+	err = e.WriteVaruint(uint(len(pk.Keys)))
+	if err != nil {
+		return err
+	}
+	for _, pair := range pk.Keys {
+		err = pair.Public.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+		err = pair.Private.MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pk *keosdPlainKeys) UnmarshalABI(d *abi.Decoder) error {
+	err := pk.Checksum.UnmarshalABI(d)
+	if err != nil {
+		return err
+	}
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	pk.Keys = make([]keosdKeyPair, l)
+	for i := range pk.Keys {
+		err = pk.Keys[i].Public.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+		err = pk.Keys[i].Private.UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fc::aes_encrypt, AES-256-CBC with the first 32 bytes of the sha512 as key and the following 16 as iv
+func keosdEncrypt(key chain.Checksum512, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	data := append(append([]byte(nil), plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, key[32:48]).CryptBlocks(data, data)
+	return data, nil
+}
+
+func keosdDecrypt(key chain.Checksum512, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:32])
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid ciphertext length")
+	}
+	data := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, key[32:48]).CryptBlocks(data, ciphertext)
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(data[len(data)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errors.New("invalid padding")
+	}
+	return data[:len(data)-padding], nil
+}
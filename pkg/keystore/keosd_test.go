@@ -0,0 +1,42 @@
+package keystore_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/keystore"
+)
+
+const keosdPassword = "PW5KCNkp5TCbxKG8NBkwZX2wZi4ZpEg1iXY7Pn5F6ttrFYqsJkCqe"
+
+// wallet containing the alice and bob keys
+const keosdWallet = `{"cipher_keys":"34fa871ddc85d7d86e02465772e2742b09f3e1a0571ae2457c94aa9d47275f2b98129f38b1693abad72ba1f2037f637b97290ba83c172c27399344ef78347d7133dc2295509c28a6b53544fe831d668640a63a9b40f61ef3c8afc5ccbce86027594f69a3ca30f4c60ae94902c0241ebff4b1a59961e1bf46cbf6a27c5a214adb7daa0fe7abd141c22212e705096ee08c6bc8782e7af6e2a11145d4e5bac6977e60e9194d60cb57f4dd3529849e67d655336bec6c16a452d9df39a4fe50dbf42b505a29429c2b35cf3c6fb2c84ff491bf"}`
+
+func TestKeosdWallet(t *testing.T) {
+	keys, err := keystore.ReadKeosdWallet([]byte(keosdWallet), keosdPassword)
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), 2)
+	assert.Equal(t, keys[0].String(), bobKey.String())
+	assert.Equal(t, keys[1].String(), aliceKey.String())
+
+	_, err = keystore.ReadKeosdWallet([]byte(keosdWallet), "PW5wrong")
+	assert.Equal(t, err, keystore.ErrInvalidPassword)
+
+	data, err := keystore.WriteKeosdWallet([]chain.PrivateKey{aliceKey, bobKey}, keosdPassword)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), keosdWallet)
+
+	ks, err := keystore.Create(filepath.Join(t.TempDir(), "keys.json"), "hunter2", testParams)
+	assert.NoError(t, err)
+	_, err = ks.ImportKey(aliceKey)
+	assert.NoError(t, err)
+	imported, err := ks.ImportKeosdWallet([]byte(keosdWallet), keosdPassword)
+	assert.NoError(t, err)
+	assert.Equal(t, len(imported), 1)
+	assert.Equal(t, imported[0].String(), "PUB_K1_5VE6Dgy9FUmd1mFotXwF88HkQN1KysCWLPqpVnDMjRvGLi8JKw")
+	exported, err := ks.ExportKeosdWallet(keosdPassword)
+	assert.NoError(t, err)
+	assert.Equal(t, string(exported), keosdWallet)
+}
@@ -0,0 +1,461 @@
+// Package keystore implements an encrypted file based key store that can be used as a signature provider.
+package keystore
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/signing"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	ErrLocked          = errors.New("keystore: locked")
+	ErrInvalidPassword = errors.New("keystore: invalid password")
+	ErrKeyExists       = errors.New("keystore: key already exists")
+	ErrKeyNotFound     = errors.New("keystore: key not found")
+)
+
+// Current version of the keystore file format.
+const Version = 1
+
+// Parameters for the scrypt key derivation function.
+type ScryptParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+var (
+	// Recommended parameters, uses 32MB of memory and takes around 100ms on a modern CPU.
+	DefaultScryptParams = ScryptParams{N: 1 << 15, R: 8, P: 1}
+	// Parameters for constrained environments, uses 4MB of memory.
+	LightScryptParams = ScryptParams{N: 1 << 12, R: 8, P: 1}
+)
+
+// Encrypted file based key store, the private keys are encrypted with AES-256-GCM using a key derived
+// from the password with scrypt. The keystore is locked when opened and can be configured to lock
+// automatically after a period of inactivity.
+type Keystore struct {
+	path    string
+	mu      sync.Mutex
+	file    keystoreFile
+	key     []byte             // derived encryption key, nil when locked
+	keys    []chain.PrivateKey // decrypted keys, nil when locked
+	timeout time.Duration
+	timer   *time.Timer
+	gen     uint64 // incremented on every touch and lock, stale timers check it before locking
+}
+
+// Create a new keystore file at path, fails if the file already exists. The returned keystore is unlocked.
+func Create(path string, password string, params ScryptParams) (*Keystore, error) {
+	salt := make([]byte, 32)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+	ks := &Keystore{
+		path: path,
+		file: keystoreFile{
+			Version: Version,
+			Scrypt:  params,
+			Salt:    salt,
+		},
+		keys: []chain.PrivateKey{},
+	}
+	ks.key, err = ks.file.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	// reserve the path so that an existing keystore is never overwritten
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	err = ks.save()
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Open an existing keystore file, the returned keystore is locked.
+func Open(path string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ks := &Keystore{path: path}
+	err = json.Unmarshal(data, &ks.file)
+	if err != nil {
+		return nil, err
+	}
+	if ks.file.Version != Version {
+		return nil, fmt.Errorf("keystore: unsupported version: %d", ks.file.Version)
+	}
+	return ks, nil
+}
+
+// Path of the keystore file.
+func (ks *Keystore) Path() string {
+	return ks.path
+}
+
+// Decrypt the keys using password.
+func (ks *Keystore) Unlock(password string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	key, err := ks.file.deriveKey(password)
+	if err != nil {
+		return err
+	}
+	keys, err := ks.file.decrypt(key)
+	if err != nil {
+		return err
+	}
+	ks.key = key
+	ks.keys = keys
+	ks.touch()
+	return nil
+}
+
+// Remove the decrypted keys from memory.
+func (ks *Keystore) Lock() {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.lock()
+}
+
+func (ks *Keystore) IsLocked() bool {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	return ks.key == nil
+}
+
+// Lock the keystore automatically when it hasn't been used for the duration, zero disables the timeout.
+func (ks *Keystore) SetTimeout(d time.Duration) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.timeout = d
+	if ks.key != nil {
+		ks.touch()
+	}
+}
+
+// Change the password of an unlocked keystore, the keys are re-encrypted with a new salt.
+func (ks *Keystore) SetPassword(password string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return ErrLocked
+	}
+	salt := make([]byte, 32)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return err
+	}
+	file := ks.file
+	file.Salt = salt
+	key, err := file.deriveKey(password)
+	if err != nil {
+		return err
+	}
+	prevFile, prevKey := ks.file, ks.key
+	ks.file, ks.key = file, key
+	err = ks.save()
+	if err != nil {
+		ks.file, ks.key = prevFile, prevKey
+		return err
+	}
+	ks.touch()
+	return nil
+}
+
+// Add a private key to the keystore, only K1 keys are supported.
+func (ks *Keystore) ImportKey(key chain.PrivateKey) (*chain.PublicKey, error) {
+	pub, err := key.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return nil, ErrLocked
+	}
+	if ks.indexOf(*pub) != -1 {
+		return nil, ErrKeyExists
+	}
+	prev := ks.keys
+	ks.keys = append(append([]chain.PrivateKey(nil), ks.keys...), cloneKey(key))
+	err = ks.save()
+	if err != nil {
+		ks.keys = prev
+		return nil, err
+	}
+	ks.touch()
+	return pub, nil
+}
+
+// Remove the private key for given public key from the keystore.
+func (ks *Keystore) RemoveKey(pub chain.PublicKey) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return ErrLocked
+	}
+	i := ks.indexOf(pub)
+	if i == -1 {
+		return ErrKeyNotFound
+	}
+	prev := ks.keys
+	ks.keys = append(append([]chain.PrivateKey(nil), ks.keys[:i]...), ks.keys[i+1:]...)
+	err := ks.save()
+	if err != nil {
+		ks.keys = prev
+		return err
+	}
+	ks.touch()
+	return nil
+}
+
+// Export the private key for given public key.
+func (ks *Keystore) PrivateKey(pub chain.PublicKey) (*chain.PrivateKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return nil, ErrLocked
+	}
+	i := ks.indexOf(pub)
+	if i == -1 {
+		return nil, ErrKeyNotFound
+	}
+	ks.touch()
+	key := cloneKey(ks.keys[i])
+	return &key, nil
+}
+
+// Export all private keys in the keystore.
+func (ks *Keystore) Keys() ([]chain.PrivateKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return nil, ErrLocked
+	}
+	ks.touch()
+	rv := make([]chain.PrivateKey, len(ks.keys))
+	for i, key := range ks.keys {
+		rv[i] = cloneKey(key)
+	}
+	return rv, nil
+}
+
+// signing.SignatureProvider conformance
+
+var _ signing.SignatureProvider = (*Keystore)(nil)
+
+func (ks *Keystore) AvailableKeys(ctx context.Context) ([]chain.PublicKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return nil, ErrLocked
+	}
+	ks.touch()
+	rv := make([]chain.PublicKey, len(ks.keys))
+	for i, key := range ks.keys {
+		pub, err := key.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		rv[i] = *pub
+	}
+	return rv, nil
+}
+
+// Signs with the held keys directly, no copies of the private keys are made.
+func (ks *Keystore) Sign(ctx context.Context, digest chain.Checksum256, requiredKeys []chain.PublicKey) ([]chain.Signature, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.key == nil {
+		return nil, ErrLocked
+	}
+	ks.touch()
+	var sigs []chain.Signature
+	for _, key := range ks.keys {
+		pub, err := key.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		if !signing.ContainsKey(requiredKeys, *pub) {
+			continue
+		}
+		sig, err := key.SignDigest(digest)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, *sig)
+	}
+	return sigs, nil
+}
+
+// helpers
+
+// on-disk format of the keystore, ciphertext is the abi encoded list of private keys
+type keystoreFile struct {
+	Version    int          `json:"version"`
+	Scrypt     ScryptParams `json:"scrypt"`
+	Salt       chain.Bytes  `json:"salt"`
+	Nonce      chain.Bytes  `json:"nonce"`
+	Ciphertext chain.Bytes  `json:"ciphertext"`
+}
+
+func (f keystoreFile) deriveKey(password string) ([]byte, error) {
+	return scrypt.Key([]byte(password), f.Salt, f.Scrypt.N, f.Scrypt.R, f.Scrypt.P, 32)
+}
+
+func (f keystoreFile) decrypt(key []byte) ([]chain.PrivateKey, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, f.Nonce, f.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	var keys []chain.PrivateKey
+	err = chain.NewDecoder(bytes.NewReader(plaintext)).Decode(&keys)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (f *keystoreFile) encrypt(key []byte, keys []chain.PrivateKey) error {
+	b := bytes.NewBuffer(nil)
+	err := chain.NewEncoder(b).Encode(keys)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	f.Nonce = nonce
+	f.Ciphertext = gcm.Seal(nil, nonce, b.Bytes(), nil)
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt the keys and write the file atomically, must be called with the mutex held
+func (ks *Keystore) save() error {
+	err := ks.file.encrypt(ks.key, ks.keys)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ks.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(ks.path), filepath.Base(ks.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), ks.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// zero the key material and stop the timer, must be called with the mutex held
+func (ks *Keystore) lock() {
+	for i := range ks.key {
+		ks.key[i] = 0
+	}
+	for _, key := range ks.keys {
+		for i := range key.Data {
+			key.Data[i] = 0
+		}
+	}
+	ks.key = nil
+	ks.keys = nil
+	ks.gen++
+	if ks.timer != nil {
+		ks.timer.Stop()
+		ks.timer = nil
+	}
+}
+
+// reset the inactivity timer, must be called with the mutex held
+func (ks *Keystore) touch() {
+	if ks.timer != nil {
+		ks.timer.Stop()
+		ks.timer = nil
+	}
+	ks.gen++
+	if ks.timeout > 0 {
+		gen := ks.gen
+		ks.timer = time.AfterFunc(ks.timeout, func() { ks.expire(gen) })
+	}
+}
+
+// lock the keystore from the inactivity timer, unless it was touched or locked since the timer was set,
+// Stop does not prevent a timer that already fired from waiting on the mutex
+func (ks *Keystore) expire(gen uint64) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.gen == gen {
+		ks.lock()
+	}
+}
+
+func (ks *Keystore) indexOf(pub chain.PublicKey) int {
+	for i, key := range ks.keys {
+		p, err := key.PublicKey()
+		if err == nil && p.Equal(pub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// copy of the key that doesn't share memory with the original, keys held by
+// the keystore are zeroed when it is locked
+func cloneKey(key chain.PrivateKey) chain.PrivateKey {
+	return chain.PrivateKey{
+		Type: key.Type,
+		Data: append([]byte(nil), key.Data...),
+	}
+}
@@ -0,0 +1,116 @@
+package keystore_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/keystore"
+)
+
+var testParams = keystore.ScryptParams{N: 1 << 10, R: 8, P: 1}
+
+func mustKey(s string) chain.PrivateKey {
+	k, err := chain.NewPrivateKeyFromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return *k
+}
+
+var (
+	aliceKey = mustKey("PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU")
+	bobKey   = mustKey("PVT_K1_z8KBsKppZhw8yhtTrGkkMiYiWqvWXTf6Z5tiM9ng6cqNFKmXy")
+)
+
+func TestKeystore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	ks, err := keystore.Create(path, "hunter2", testParams)
+	assert.NoError(t, err)
+	assert.Equal(t, ks.IsLocked(), false)
+
+	_, err = keystore.Create(path, "hunter2", testParams)
+	assert.NotNil(t, err)
+
+	pub, err := ks.ImportKey(aliceKey)
+	assert.NoError(t, err)
+	assert.Equal(t, pub.String(), "PUB_K1_7zsqi7QUAjTAdyynd6DVe8uv4K8gCTRHnAoMN9w9CA1xKwj6Qn")
+	_, err = ks.ImportKey(aliceKey)
+	assert.Equal(t, err, keystore.ErrKeyExists)
+	_, err = ks.ImportKey(bobKey)
+	assert.NoError(t, err)
+
+	// keys must not be stored in plain text
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, !strings.Contains(string(data), chain.Bytes(aliceKey.Data).Hex()))
+
+	ks.Lock()
+	assert.Equal(t, ks.IsLocked(), true)
+	_, err = ks.AvailableKeys(ctx)
+	assert.Equal(t, err, keystore.ErrLocked)
+	_, err = ks.ImportKey(aliceKey)
+	assert.Equal(t, err, keystore.ErrLocked)
+
+	ks, err = keystore.Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ks.IsLocked(), true)
+	assert.Equal(t, ks.Unlock("hunter3"), keystore.ErrInvalidPassword)
+	assert.NoError(t, ks.Unlock("hunter2"))
+	keys, err := ks.AvailableKeys(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), 2)
+	assert.Equal(t, keys[0].String(), pub.String())
+
+	digest := chain.Checksum256Digest([]byte("hello"))
+	sigs, err := ks.Sign(ctx, digest, []chain.PublicKey{*pub})
+	assert.NoError(t, err)
+	assert.Equal(t, len(sigs), 1)
+	recovered, err := sigs[0].RecoverDigest(digest)
+	assert.NoError(t, err)
+	assert.Equal(t, recovered.String(), pub.String())
+
+	exported, err := ks.PrivateKey(*pub)
+	assert.NoError(t, err)
+	assert.Equal(t, exported.String(), aliceKey.String())
+	ks.Lock()
+	// exported keys are not affected by locking
+	assert.Equal(t, exported.String(), aliceKey.String())
+	_, err = ks.Sign(ctx, digest, []chain.PublicKey{*pub})
+	assert.Equal(t, err, keystore.ErrLocked)
+
+	assert.NoError(t, ks.Unlock("hunter2"))
+	assert.NoError(t, ks.RemoveKey(*pub))
+	assert.Equal(t, ks.RemoveKey(*pub), keystore.ErrKeyNotFound)
+	assert.NoError(t, ks.SetPassword("correct horse"))
+
+	ks, err = keystore.Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, ks.Unlock("hunter2"), keystore.ErrInvalidPassword)
+	assert.NoError(t, ks.Unlock("correct horse"))
+	all, err := ks.Keys()
+	assert.NoError(t, err)
+	assert.Equal(t, len(all), 1)
+	assert.Equal(t, all[0].String(), bobKey.String())
+}
+
+func TestKeystoreTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	ks, err := keystore.Create(path, "hunter2", testParams)
+	assert.NoError(t, err)
+	ks.SetTimeout(50 * time.Millisecond)
+	assert.Equal(t, ks.IsLocked(), false)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, ks.IsLocked(), true)
+
+	assert.NoError(t, ks.Unlock("hunter2"))
+	ks.SetTimeout(0)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, ks.IsLocked(), false)
+}
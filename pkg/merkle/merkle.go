@@ -0,0 +1,212 @@
+// Package merkle implements the merkle tree algorithms used by EOSIO block producers, the pair-wise merkle
+// used for the transaction_mroot and action_mroot block header fields and the incremental merkle tree
+// tracked in the block header state.
+package merkle
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var ErrIndexOutOfRange = errors.New("merkle: leaf index out of range")
+
+// Incremental merkle tree, only the nodes needed to compute the root and append new leaves are kept.
+type IncrementalMerkle struct {
+	ActiveNodes []chain.Checksum256 `json:"_active_nodes"`
+	NodeCount   uint64              `json:"_node_count"`
+}
+
+// Compute the merkle root of given leaves, the zero checksum is returned when there are no leaves.
+func Root(leaves []chain.Checksum256) chain.Checksum256 {
+	if len(leaves) == 0 {
+		return chain.Checksum256{}
+	}
+	nodes := make([]chain.Checksum256, len(leaves))
+	copy(nodes, leaves)
+	for len(nodes) > 1 {
+		if len(nodes)%2 != 0 {
+			nodes = append(nodes, nodes[len(nodes)-1])
+		}
+		for i := 0; i < len(nodes)/2; i++ {
+			nodes[i] = hashPair(nodes[2*i], nodes[2*i+1])
+		}
+		nodes = nodes[:len(nodes)/2]
+	}
+	return nodes[0]
+}
+
+// Create a proof that the leaf at given index is part of the tree. The proof is
+// the list of sibling nodes from the leaf up to the root, each made canonical so
+// that its position can be inferred when verifying.
+func Proof(leaves []chain.Checksum256, index int) ([]chain.Checksum256, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, ErrIndexOutOfRange
+	}
+	var proof []chain.Checksum256
+	nodes := make([]chain.Checksum256, len(leaves))
+	copy(nodes, leaves)
+	for len(nodes) > 1 {
+		if len(nodes)%2 != 0 {
+			nodes = append(nodes, nodes[len(nodes)-1])
+		}
+		if index%2 == 0 {
+			proof = append(proof, makeCanonicalRight(nodes[index+1]))
+		} else {
+			proof = append(proof, makeCanonicalLeft(nodes[index-1]))
+		}
+		for i := 0; i < len(nodes)/2; i++ {
+			nodes[i] = hashPair(nodes[2*i], nodes[2*i+1])
+		}
+		nodes = nodes[:len(nodes)/2]
+		index /= 2
+	}
+	return proof, nil
+}
+
+// Verify that leaf is part of the tree with given root using a proof created by Proof.
+func Verify(leaf chain.Checksum256, proof []chain.Checksum256, root chain.Checksum256) bool {
+	node := leaf
+	for _, sibling := range proof {
+		if isCanonicalLeft(sibling) {
+			node = hashPair(sibling, node)
+		} else {
+			node = hashPair(node, sibling)
+		}
+	}
+	return node == root
+}
+
+// Append a leaf to the tree, returns the new root.
+func (m *IncrementalMerkle) Append(digest chain.Checksum256) chain.Checksum256 {
+	partial := false
+	maxDepth := calculateMaxDepth(m.NodeCount + 1)
+	currentDepth := maxDepth - 1
+	index := m.NodeCount
+	top := digest
+	activeIdx := 0
+	updated := make([]chain.Checksum256, 0, maxDepth)
+	for currentDepth > 0 {
+		if index&0x1 == 0 {
+			// collapsing from a "left" value and an implied "right" creating a partial node,
+			// we only need to keep the node if it is fully realized
+			if !partial {
+				updated = append(updated, top)
+			}
+			top = hashPair(top, top)
+			partial = true
+		} else {
+			// collapsing from a "right" value and a fully realized "left"
+			left := m.ActiveNodes[activeIdx]
+			activeIdx++
+			// future appends still need the left node if the right one is partial
+			if partial {
+				updated = append(updated, left)
+			}
+			top = hashPair(left, top)
+		}
+		currentDepth--
+		index >>= 1
+	}
+	updated = append(updated, top)
+	m.ActiveNodes = updated
+	m.NodeCount++
+	return top
+}
+
+// Current root of the tree, the zero checksum is returned for an empty tree.
+func (m IncrementalMerkle) Root() chain.Checksum256 {
+	if m.NodeCount == 0 || len(m.ActiveNodes) == 0 {
+		return chain.Checksum256{}
+	}
+	return m.ActiveNodes[len(m.ActiveNodes)-1]
+}
+
+// abi.Marshaler conformance
+
+func (m IncrementalMerkle) MarshalABI(e *abi.Encoder) error {
+	l := uint(len(m.ActiveNodes))
+	err := e.WriteVaruint(l)
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < l; i++ {
+		err = m.ActiveNodes[i].MarshalABI(e)
+		if err != nil {
+			return err
+		}
+	}
+	return e.WriteUint64(m.NodeCount)
+}
+
+// abi.Unmarshaler conformance
+
+func (m *IncrementalMerkle) UnmarshalABI(d *abi.Decoder) error {
+	l, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	m.ActiveNodes = make([]chain.Checksum256, l)
+	for i := 0; i < int(l); i++ {
+		err = m.ActiveNodes[i].UnmarshalABI(d)
+		if err != nil {
+			return err
+		}
+	}
+	m.NodeCount, err = d.ReadUint64()
+	return err
+}
+
+// helpers
+
+func makeCanonicalLeft(c chain.Checksum256) chain.Checksum256 {
+	c[0] &= 0x7f
+	return c
+}
+
+func makeCanonicalRight(c chain.Checksum256) chain.Checksum256 {
+	c[0] |= 0x80
+	return c
+}
+
+func isCanonicalLeft(c chain.Checksum256) bool {
+	return c[0]&0x80 == 0
+}
+
+func hashPair(left, right chain.Checksum256) chain.Checksum256 {
+	left = makeCanonicalLeft(left)
+	right = makeCanonicalRight(right)
+	h := sha256.New()
+	h.Write(left[:])
+	h.Write(right[:])
+	var rv chain.Checksum256
+	copy(rv[:], h.Sum(nil))
+	return rv
+}
+
+func calculateMaxDepth(nodeCount uint64) int {
+	if nodeCount == 0 {
+		return 0
+	}
+	implied := nextPowerOf2(nodeCount)
+	depth := 0
+	for implied > 1 {
+		implied >>= 1
+		depth++
+	}
+	return depth + 1
+}
+
+func nextPowerOf2(v uint64) uint64 {
+	v--
+	v |= v >> 1
+	v |= v >> 2
+	v |= v >> 4
+	v |= v >> 8
+	v |= v >> 16
+	v |= v >> 32
+	v++
+	return v
+}
@@ -0,0 +1,195 @@
+package merkle_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/merkle"
+)
+
+func testLeaves(n int) []chain.Checksum256 {
+	rv := make([]chain.Checksum256, n)
+	for i := 0; i < n; i++ {
+		rv[i] = chain.Checksum256Digest([]byte(fmt.Sprintf("leaf %d", i)))
+	}
+	return rv
+}
+
+func TestRoot(t *testing.T) {
+	leaves := testLeaves(10)
+	assert.Equal(t, merkle.Root(nil), chain.Checksum256{})
+	assert.Equal(t, merkle.Root(leaves[:1]).String(), "20e325f06280f9d0d193fed01a0eda5bef79063f2e602d93e3605cbe825d96ad")
+	assert.Equal(t, merkle.Root(leaves[:2]).String(), "06f4672c8871ec3b0085b38a1682a938005d5fa05ef1366bf23b5f9eb46ff543")
+	assert.Equal(t, merkle.Root(leaves[:3]).String(), "7c7706da80f0f705a9d3e99b713041f8d2294037a1c0fcf1bc514430de0a39f3")
+	assert.Equal(t, merkle.Root(leaves[:5]).String(), "6ec0c62fe0a6d889f2109ad2ae992d9523cf889c02f2f894477ecbb16e4f6294")
+	assert.Equal(t, merkle.Root(leaves[:8]).String(), "4c076b8db0dc18705819b16439cc0489add42add7f4f2cff2872251e09081bfd")
+	assert.Equal(t, merkle.Root(leaves).String(), "08b5702ae689fb9b800da2bd0809f3b57ac37c34f44263bb7c0e4436e0bc02c0")
+	// make sure the input is not modified
+	assert.Equal(t, leaves[0].String(), "20e325f06280f9d0d193fed01a0eda5bef79063f2e602d93e3605cbe825d96ad")
+}
+
+func TestProof(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := testLeaves(n)
+		root := merkle.Root(leaves)
+		for i := 0; i < n; i++ {
+			proof, err := merkle.Proof(leaves, i)
+			assert.NoError(t, err)
+			assert.True(t, merkle.Verify(leaves[i], proof, root))
+			// wrong leaf
+			assert.True(t, !merkle.Verify(chain.Checksum256Digest([]byte("nope")), proof, root))
+			if len(proof) > 0 {
+				// tampered proof
+				proof[0][31] ^= 0xff
+				assert.True(t, !merkle.Verify(leaves[i], proof, root))
+			}
+		}
+	}
+	_, err := merkle.Proof(testLeaves(2), 2)
+	assert.Equal(t, err, merkle.ErrIndexOutOfRange)
+}
+
+func TestIncrementalMerkle(t *testing.T) {
+	var m merkle.IncrementalMerkle
+	assert.Equal(t, m.Root(), chain.Checksum256{})
+	leaves := testLeaves(33)
+	for i, leaf := range leaves {
+		root := m.Append(leaf)
+		assert.Equal(t, root, merkle.Root(leaves[:i+1]))
+		assert.Equal(t, m.Root(), root)
+		assert.Equal(t, m.NodeCount, uint64(i+1))
+	}
+
+	m = merkle.IncrementalMerkle{}
+	for _, leaf := range leaves[:3] {
+		m.Append(leaf)
+	}
+	n1, n2 := merkle.Root(leaves[:2]), merkle.Root(leaves[:3])
+	expected := []byte{0x03}
+	expected = append(expected, leaves[2][:]...)
+	expected = append(expected, n1[:]...)
+	expected = append(expected, n2[:]...)
+	expected = append(expected, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	assert.ABICoding(t, m, expected)
+	assert.JSONCoding(t, m, `{
+		"_active_nodes": [
+			"bac57df66fe6368188d1d4521bcffaecee76a03a50ff297a13439f7164de0a5f",
+			"06f4672c8871ec3b0085b38a1682a938005d5fa05ef1366bf23b5f9eb46ff543",
+			"7c7706da80f0f705a9d3e99b713041f8d2294037a1c0fcf1bc514430de0a39f3"
+		],
+		"_node_count": 3
+	}`)
+
+	// resume from a serialized state
+	resumed := merkle.IncrementalMerkle{
+		ActiveNodes: append([]chain.Checksum256{}, m.ActiveNodes...),
+		NodeCount:   m.NodeCount,
+	}
+	for i, leaf := range leaves[3:] {
+		assert.Equal(t, resumed.Append(leaf), merkle.Root(leaves[:i+4]))
+	}
+}
+
+func TestTransactionMroot(t *testing.T) {
+	deferredID := chain.Checksum256Digest([]byte("deferred"))
+	receipt := chain.TransactionReceipt{
+		TransactionReceiptHeader: chain.TransactionReceiptHeader{
+			Status:     chain.TransactionStatusSoftFail,
+			CpuUsageUs: 200,
+		},
+		Trx: chain.TransactionReceiptTrx{ID: &deferredID},
+	}
+	assert.Equal(t, receipt.Digest().String(), "a84c98c71e4c7beda6534205905acee3b603f0e61d351a97b6a45bcf80f15006")
+	assert.Equal(t, merkle.Root([]chain.Checksum256{receipt.Digest()}), receipt.Digest())
+}
+
+func TestTransactionMrootMainnet(t *testing.T) {
+	// eos mainnet block 111162000
+	var block chain.SignedBlock
+	err := json.Unmarshal([]byte(`{
+		"timestamp": "2020-03-20T15:11:29.500",
+		"producer": "eoseouldotio",
+		"confirmed": 0,
+		"previous": "06a0328fab6ca53229be81ea93706ef20d1e9abbbc7d7f782edfbc024df6b4e8",
+		"transaction_mroot": "de0edd064ce71a92f21d37add7083d06d065a17ac0c7d541c49cfca84e58eb39",
+		"action_mroot": "a41ba57f9e7f84c3caf7684831f05f3b56669b8b78f0104bdd777af3677d90c0",
+		"schedule_version": 1673,
+		"header_extensions": [],
+		"producer_signature": "SIG_K1_KXpCBuVjGB3de9ZEfiAtv176tW3GoneSRp9dsMt8dEEkrKys1pWqjP3uuZGHCJTTG6dxmSrh1ekPhZQV1uziBn41XnsW9G",
+		"transactions": [
+			{
+				"status": "executed",
+				"cpu_usage_us": 13187,
+				"net_usage_words": 12,
+				"trx": {
+					"signatures": [
+						"SIG_K1_K3DgaEbsDHg16SSQY2nAgkbN41PaWzyFjoGLEqVK9J1X3sMn5VquavXUDMP87jjiGm5EMjRztdt1nRfs9NcVoUZKq1gu1H"
+					],
+					"compression": "none",
+					"packed_context_free_data": "",
+					"packed_trx": "3edd745e4031ff9ed5ca00000000012038a5425794a7ba000000000000a6be012038a5425794a7ba00000000a8ed32320000"
+				}
+			},
+			{
+				"status": "executed",
+				"cpu_usage_us": 479,
+				"net_usage_words": 21,
+				"trx": {
+					"signatures": [
+						"SIG_K1_KgoLDYwfcKzARC2XgnUBBVbTXa6LJeMo68UrzZuhtUk59b2Cy838zBdkUwbYKzAkpjdQCkN6D61HfZ7Ri3oyueQ8kYU5pw",
+						"SIG_K1_KVg7bQ91uFFUsCNbPcFaa1jcTrmczG9hv8iUKr6p6yVngYv7Cw2vBey7uvM9kNZ6ea95C1Y4RNPLfWsnU864haucFs2X7h"
+					],
+					"compression": "none",
+					"packed_context_free_data": "",
+					"packed_trx": "56dd745e8132f5a24481000000000100a6823403ea3055000000572d3ccdcd020040cd204677320e00000000a8ed3232503330fb35c48b1d00000000a8ed32322a503330fb35c48b1dd06f4d95569fa6412c0100000000000004454f5300000000096368616e6e656c3a3100"
+				}
+			},
+			{
+				"status": "executed",
+				"cpu_usage_us": 6534,
+				"net_usage_words": 12,
+				"trx": {
+					"signatures": [
+						"SIG_K1_JxZSdKyirNcZRb9RG32WdGahsno4GXeyuUJ1aNRbumzbRJw8aotpLExnZ3BHkzK6oJjBP4xNgqYy4hd6RQvCvmUvf138h7"
+					],
+					"compression": "none",
+					"packed_context_free_data": "",
+					"packed_trx": "7aea745e2131e10915190000000001c0a88fca546773ad00000000000000900120a0453a87b367e90000000000a0a693010000"
+				}
+			},
+			{
+				"status": "executed",
+				"cpu_usage_us": 6334,
+				"net_usage_words": 12,
+				"trx": {
+					"signatures": [
+						"SIG_K1_K2SCaLR4ifzJgcVm5MkcBJKaVa3oEywYSQFUK2gYZQRGAhs3sxj4mq8ufXKak6XxV6WnLPozVmGqmKZdT4WukdNTxsHVvw"
+					],
+					"compression": "none",
+					"packed_context_free_data": "",
+					"packed_trx": "7aea745e2131e10915190000000001c0a88fca546773ad000000000000009001504e8d1687f99f490000000000a0a693010000"
+				}
+			}
+		],
+		"block_extensions": []
+	}`), &block)
+	assert.NoError(t, err)
+	assert.Equal(t, block.ID().String(), "06a03290c65b86d696661aa39bfe7cc1952307184a86e45772b2667a4d6e6af1")
+	ids := []string{
+		"ce03809deafd21d56d5a061ddfc3cd978e32fa0fa9f0b65f234698725339d8d4",
+		"c815ef0bcddd15b7850e09bf75fd9ea93c29c1ced062c14cfec78a457442c3bf",
+		"d553dccced12afa30e303d141d58581b59f530bdf86cd91ac63efe8f93c11ec1",
+		"0068468de44760b9b22f05e85f7786cb4df8d0140d9e9ac691bb049e9b90d4ac",
+	}
+	digests := make([]chain.Checksum256, len(block.Transactions))
+	for i, receipt := range block.Transactions {
+		id, err := receipt.Trx.Packed.ID()
+		assert.NoError(t, err)
+		assert.Equal(t, id.String(), ids[i])
+		digests[i] = receipt.Digest()
+	}
+	assert.Equal(t, merkle.Root(digests), block.TransactionMroot)
+}
@@ -0,0 +1,250 @@
+// Package msig contains types and helpers for the eosio.msig contract.
+package msig
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Account the eosio.msig contract is deployed to on most chains.
+var Contract = chain.N("eosio.msig")
+
+// Action names of the eosio.msig contract.
+var (
+	ActionPropose   = chain.N("propose")
+	ActionApprove   = chain.N("approve")
+	ActionUnapprove = chain.N("unapprove")
+	ActionCancel    = chain.N("cancel")
+	ActionExec      = chain.N("exec")
+)
+
+// Table names of the eosio.msig contract.
+var (
+	TableProposal   = chain.N("proposal")
+	TableApprovals2 = chain.N("approvals2")
+)
+
+// The propose action, creates a new proposal.
+type Propose struct {
+	Proposer     chain.Name              `json:"proposer"`
+	ProposalName chain.Name              `json:"proposal_name"`
+	Requested    []chain.PermissionLevel `json:"requested"`
+	Trx          chain.Transaction       `json:"trx"`
+}
+
+// The approve action, adds an approval to a proposal.
+type Approve struct {
+	Proposer     chain.Name            `json:"proposer"`
+	ProposalName chain.Name            `json:"proposal_name"`
+	Level        chain.PermissionLevel `json:"level"`
+	// Optional, when set the approval is only valid for a proposal with a matching hash.
+	ProposalHash *chain.Checksum256 `json:"proposal_hash,omitempty"`
+}
+
+// The unapprove action, revokes a previously given approval.
+type Unapprove struct {
+	Proposer     chain.Name            `json:"proposer"`
+	ProposalName chain.Name            `json:"proposal_name"`
+	Level        chain.PermissionLevel `json:"level"`
+}
+
+// The cancel action, removes a proposal.
+type Cancel struct {
+	Proposer     chain.Name `json:"proposer"`
+	ProposalName chain.Name `json:"proposal_name"`
+	Canceler     chain.Name `json:"canceler"`
+}
+
+// The exec action, executes a proposal that has received enough approvals.
+type Exec struct {
+	Proposer     chain.Name `json:"proposer"`
+	ProposalName chain.Name `json:"proposal_name"`
+	Executer     chain.Name `json:"executer"`
+}
+
+// Row in the proposal table, scoped by proposer.
+type Proposal struct {
+	ProposalName      chain.Name  `json:"proposal_name"`
+	PackedTransaction chain.Bytes `json:"packed_transaction"`
+	// Binary extension, only present on contracts that support it and nil if never set.
+	EarliestExecTime *chain.TimePoint `json:"earliest_exec_time,omitempty"`
+}
+
+// An approval entry in the approvals2 table.
+type Approval struct {
+	Level chain.PermissionLevel `json:"level"`
+	Time  chain.TimePoint       `json:"time"`
+}
+
+// Row in the approvals2 table, scoped by proposer.
+type ApprovalsInfo struct {
+	Version            uint8      `json:"version"`
+	ProposalName       chain.Name `json:"proposal_name"`
+	RequestedApprovals []Approval `json:"requested_approvals"`
+	ProvidedApprovals  []Approval `json:"provided_approvals"`
+}
+
+// Create a new proposal row by packing given transaction.
+func NewProposal(name chain.Name, tx chain.Transaction) (*Proposal, error) {
+	packed, err := encode(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &Proposal{ProposalName: name, PackedTransaction: packed}, nil
+}
+
+// Hash of the packed transaction, used as the proposal_hash of the approve action.
+func (p Proposal) Hash() chain.Checksum256 {
+	return chain.Checksum256Digest(p.PackedTransaction)
+}
+
+// Decode the packed transaction of the proposal.
+func (p Proposal) Transaction() (*chain.Transaction, error) {
+	var tx chain.Transaction
+	err := chain.NewDecoder(bytes.NewReader(p.PackedTransaction)).Decode(&tx)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// Compute the proposal hash of given transaction.
+func ProposalHash(tx chain.Transaction) (chain.Checksum256, error) {
+	packed, err := encode(tx)
+	if err != nil {
+		return chain.Checksum256{}, err
+	}
+	return chain.Checksum256Digest(packed), nil
+}
+
+// Create eosio.msig::propose action with given authorization.
+func (p Propose) Action(auth []chain.PermissionLevel) (*chain.Action, error) {
+	return newAction(ActionPropose, auth, p)
+}
+
+// Create eosio.msig::approve action with given authorization.
+func (a Approve) Action(auth []chain.PermissionLevel) (*chain.Action, error) {
+	return newAction(ActionApprove, auth, a)
+}
+
+// Create eosio.msig::unapprove action with given authorization.
+func (u Unapprove) Action(auth []chain.PermissionLevel) (*chain.Action, error) {
+	return newAction(ActionUnapprove, auth, u)
+}
+
+// Create eosio.msig::cancel action with given authorization.
+func (c Cancel) Action(auth []chain.PermissionLevel) (*chain.Action, error) {
+	return newAction(ActionCancel, auth, c)
+}
+
+// Create eosio.msig::exec action with given authorization.
+func (e Exec) Action(auth []chain.PermissionLevel) (*chain.Action, error) {
+	return newAction(ActionExec, auth, e)
+}
+
+// abi.Marshaler conformance
+
+func (a Approve) MarshalABI(e *abi.Encoder) error {
+	var err error
+	if err = a.Proposer.MarshalABI(e); err != nil {
+		return err
+	}
+	if err = a.ProposalName.MarshalABI(e); err != nil {
+		return err
+	}
+	if err = a.Level.MarshalABI(e); err != nil {
+		return err
+	}
+	if a.ProposalHash != nil {
+		err = a.ProposalHash.MarshalABI(e)
+	}
+	return err
+}
+
+func (p Proposal) MarshalABI(e *abi.Encoder) error {
+	var err error
+	if err = p.ProposalName.MarshalABI(e); err != nil {
+		return err
+	}
+	if err = p.PackedTransaction.MarshalABI(e); err != nil {
+		return err
+	}
+	if p.EarliestExecTime != nil {
+		if err = e.WriteBool(true); err != nil {
+			return err
+		}
+		err = p.EarliestExecTime.MarshalABI(e)
+	}
+	return err
+}
+
+// abi.Unmarshaler conformance
+
+func (a *Approve) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	if err = a.Proposer.UnmarshalABI(d); err != nil {
+		return err
+	}
+	if err = a.ProposalName.UnmarshalABI(d); err != nil {
+		return err
+	}
+	if err = a.Level.UnmarshalABI(d); err != nil {
+		return err
+	}
+	var hash chain.Checksum256
+	err = hash.UnmarshalABI(d)
+	if err == io.EOF {
+		a.ProposalHash = nil
+		return nil
+	}
+	if err == nil {
+		a.ProposalHash = &hash
+	}
+	return err
+}
+
+func (p *Proposal) UnmarshalABI(d *abi.Decoder) error {
+	var err error
+	if err = p.ProposalName.UnmarshalABI(d); err != nil {
+		return err
+	}
+	if err = p.PackedTransaction.UnmarshalABI(d); err != nil {
+		return err
+	}
+	p.EarliestExecTime = nil
+	exists, err := d.ReadBool()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil || !exists {
+		return err
+	}
+	var tp chain.TimePoint
+	err = tp.UnmarshalABI(d)
+	if err == nil {
+		p.EarliestExecTime = &tp
+	}
+	return err
+}
+
+// helpers
+
+func encode(v interface{}) (chain.Bytes, error) {
+	buf := bytes.NewBuffer(nil)
+	err := chain.NewEncoder(buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func newAction(name chain.Name, auth []chain.PermissionLevel, v interface{}) (*chain.Action, error) {
+	data, err := encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return chain.NewAction(Contract, name, auth, data), nil
+}
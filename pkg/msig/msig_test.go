@@ -0,0 +1,144 @@
+package msig_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/msig"
+)
+
+var testTx = chain.Transaction{
+	TransactionHeader: chain.TransactionHeader{
+		Expiration:       chain.TimePointSec(1234567890),
+		RefBlockNum:      11,
+		RefBlockPrefix:   22,
+		MaxNetUsageWords: 33,
+		MaxCpuUsageMs:    44,
+		DelaySec:         55,
+	},
+	ContextFreeActions: []chain.Action{},
+	Actions: []chain.Action{
+		{
+			Account: chain.N("foo"),
+			Name:    chain.N("bar"),
+			Authorization: []chain.PermissionLevel{
+				{Actor: chain.N("baz"), Permission: chain.N("qux")},
+				{Actor: chain.N("quux"), Permission: chain.N("quuz")},
+			},
+			Data: []byte{0xde, 0xad, 0xbe, 0xef},
+		},
+	},
+	Extensions: []chain.TransactionExtension{},
+}
+
+const testTxHex = "d20296490b0016000000212c370001000000000000285d000000000000ae3902000000000000be39000000000000bab60000000000d0b5b60000000000f0b5b604deadbeef00"
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestProposal(t *testing.T) {
+	p, err := msig.NewProposal(chain.N("myproposal"), testTx)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(p.PackedTransaction), mustHex(testTxHex))
+	assert.Equal(t, p.Hash().String(), "caf894990b257a26f3f638a2371f625cf2b3d21e23ef9c67fbbb5c46ef3136f6")
+
+	hash, err := msig.ProposalHash(testTx)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, p.Hash())
+
+	tx, err := p.Transaction()
+	assert.NoError(t, err)
+	assert.Equal(t, *tx, testTx)
+
+	assert.ABICoding(t, *p, mustHex("00403498567aab9746"+testTxHex))
+	assert.JSONCoding(t, *p, `{"proposal_name":"myproposal","packed_transaction":"`+testTxHex+`"}`)
+
+	tp := chain.TimePoint(1519257492222000)
+	p.EarliestExecTime = &tp
+	assert.ABICoding(t, *p, mustHex("00403498567aab9746"+testTxHex+"01"+"307025b3c1650500"))
+	assert.JSONCoding(t, *p, `{"proposal_name":"myproposal","packed_transaction":"`+testTxHex+`","earliest_exec_time":"2018-02-21T23:58:12.222"}`)
+}
+
+func TestApprovals(t *testing.T) {
+	info := msig.ApprovalsInfo{
+		Version:      1,
+		ProposalName: chain.N("myproposal"),
+		RequestedApprovals: []msig.Approval{
+			{Level: chain.PermissionLevel{Actor: chain.N("alice"), Permission: chain.N("active")}, Time: 0},
+		},
+		ProvidedApprovals: []msig.Approval{
+			{Level: chain.PermissionLevel{Actor: chain.N("bob"), Permission: chain.N("active")}, Time: 1519257492222000},
+		},
+	}
+	assert.ABICoding(t, info, mustHex("0100403498567aab97"+
+		"01"+"0000000000855c34"+"00000000a8ed3232"+"0000000000000000"+
+		"01"+"0000000000000e3d"+"00000000a8ed3232"+"307025b3c1650500"))
+	assert.JSONCoding(t, info, `{
+		"version": 1,
+		"proposal_name": "myproposal",
+		"requested_approvals": [{"level": {"actor": "alice", "permission": "active"}, "time": "1970-01-01T00:00:00.000"}],
+		"provided_approvals": [{"level": {"actor": "bob", "permission": "active"}, "time": "2018-02-21T23:58:12.222"}]
+	}`)
+}
+
+func TestActions(t *testing.T) {
+	auth := []chain.PermissionLevel{{Actor: chain.N("alice"), Permission: chain.N("active")}}
+
+	propose, err := msig.Propose{
+		Proposer:     chain.N("alice"),
+		ProposalName: chain.N("myproposal"),
+		Requested:    []chain.PermissionLevel{{Actor: chain.N("bob"), Permission: chain.N("active")}},
+		Trx:          testTx,
+	}.Action(auth)
+	assert.NoError(t, err)
+	assert.Equal(t, propose.Account, msig.Contract)
+	assert.Equal(t, propose.Name, msig.ActionPropose)
+	assert.Equal(t, propose.Authorization, auth)
+	assert.Equal(t, []byte(propose.Data), append(mustHex("0000000000855c34"+"00403498567aab97"+
+		"01"+"0000000000000e3d"+"00000000a8ed3232"), mustHex(testTxHex)...))
+	var decoded msig.Propose
+	assert.NoError(t, propose.DecodeInto(&decoded))
+	assert.Equal(t, decoded.Trx, testTx)
+
+	hash, err := msig.ProposalHash(testTx)
+	assert.NoError(t, err)
+	approve := msig.Approve{
+		Proposer:     chain.N("alice"),
+		ProposalName: chain.N("myproposal"),
+		Level:        chain.PermissionLevel{Actor: chain.N("bob"), Permission: chain.N("active")},
+	}
+	assert.ABICoding(t, approve, mustHex("0000000000855c34"+"00403498567aab97"+"0000000000000e3d"+"00000000a8ed3232"))
+	approve.ProposalHash = &hash
+	assert.ABICoding(t, approve, mustHex("0000000000855c34"+"00403498567aab97"+"0000000000000e3d"+"00000000a8ed3232"+hash.String()))
+	assert.JSONCoding(t, approve, `{
+		"proposer": "alice",
+		"proposal_name": "myproposal",
+		"level": {"actor": "bob", "permission": "active"},
+		"proposal_hash": "`+hash.String()+`"
+	}`)
+	act, err := approve.Action(auth)
+	assert.NoError(t, err)
+	assert.Equal(t, act.Name, msig.ActionApprove)
+
+	act, err = msig.Unapprove{chain.N("alice"), chain.N("myproposal"), auth[0]}.Action(auth)
+	assert.NoError(t, err)
+	assert.Equal(t, act.Name, msig.ActionUnapprove)
+	assert.Equal(t, []byte(act.Data), mustHex("0000000000855c34"+"00403498567aab97"+"0000000000855c34"+"00000000a8ed3232"))
+
+	act, err = msig.Cancel{chain.N("alice"), chain.N("myproposal"), chain.N("alice")}.Action(auth)
+	assert.NoError(t, err)
+	assert.Equal(t, act.Name, msig.ActionCancel)
+	assert.Equal(t, []byte(act.Data), mustHex("0000000000855c34"+"00403498567aab97"+"0000000000855c34"))
+
+	act, err = msig.Exec{chain.N("alice"), chain.N("myproposal"), chain.N("bob")}.Action(auth)
+	assert.NoError(t, err)
+	assert.Equal(t, act.Name, msig.ActionExec)
+	assert.Equal(t, []byte(act.Data), mustHex("0000000000855c34"+"00403498567aab97"+"0000000000000e3d"))
+}
@@ -0,0 +1,262 @@
+package ship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Number of results in flight used when the request does not specify one.
+const DefaultMaxMessagesInFlight = 10
+
+var ErrUnexpectedResult = errors.New("ship: unexpected result type")
+
+// Message based connection to a state history node, e.g. a websocket connection.
+type Conn interface {
+	// Read the next message, blocking until one is available.
+	ReadMessage() ([]byte, error)
+	// Write a binary message.
+	WriteMessage(data []byte) error
+	Close() error
+}
+
+// State history client, not safe for concurrent use.
+type Client struct {
+	conn Conn
+	abi  *chain.Abi
+}
+
+// Stream of blocks started with Client.GetBlocks.
+type BlockStream struct {
+	client    *Client
+	unacked   uint32
+	head      BlockPosition
+	lib       BlockPosition
+	positions []BlockPosition
+}
+
+// Block received on a BlockStream.
+type StreamBlock struct {
+	GetBlocksResultV1
+	// Previously streamed blocks replaced by this block and its successors, highest first.
+	// Set when the node switched to another fork, consumers should undo any changes they made for these blocks.
+	Forked []BlockPosition
+}
+
+// Connect to a state history node websocket endpoint, e.g. ws://127.0.0.1:8080.
+func Dial(ctx context.Context, url string) (*Client, error) {
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewClient(&wsConn{ws})
+	if err != nil {
+		ws.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Create a client using given connection, reads the protocol ABI sent by the node when connecting.
+func NewClient(conn Conn) (*Client, error) {
+	msg, err := conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	var a chain.Abi
+	err = json.Unmarshal(msg, &a)
+	if err != nil {
+		return nil, fmt.Errorf("ship: invalid protocol abi: %w", err)
+	}
+	err = checkVariant(&a, "request", requestTypes)
+	if err != nil {
+		return nil, err
+	}
+	err = checkVariant(&a, "result", resultTypes)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, abi: &a}, nil
+}
+
+// The protocol ABI sent by the node, used to decode the chain state table rows.
+func (c *Client) Abi() *chain.Abi {
+	return c.abi
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Get the status of the node, must not be called while a block stream is active.
+func (c *Client) GetStatus() (*GetStatusResultV0, error) {
+	err := c.Send(Request{GetStatusV0: &GetStatusRequestV0{}})
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.Receive()
+	if err != nil {
+		return nil, err
+	}
+	if res.GetStatusV0 == nil {
+		return nil, ErrUnexpectedResult
+	}
+	return res.GetStatusV0, nil
+}
+
+// Start streaming blocks, the v1 request is only sent if finality data is requested so that
+// nodes that do not support it can be used otherwise.
+func (c *Client) GetBlocks(req GetBlocksRequestV1) (*BlockStream, error) {
+	if req.MaxMessagesInFlight == 0 {
+		req.MaxMessagesInFlight = DefaultMaxMessagesInFlight
+	}
+	var err error
+	if req.FetchFinalityData {
+		err = c.Send(Request{GetBlocksV1: &req})
+	} else {
+		err = c.Send(Request{GetBlocksV0: &req.GetBlocksRequestV0})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &BlockStream{
+		client:    c,
+		positions: append([]BlockPosition{}, req.HavePositions...),
+	}, nil
+}
+
+// Send a raw request to the node.
+func (c *Client) Send(req Request) error {
+	idx, _ := variantValue(req)
+	if idx == -1 {
+		return errors.New("ship: empty request")
+	}
+	if v := c.abi.GetVariant("request"); idx >= len(v.Types) {
+		return fmt.Errorf("ship: %s not supported by node", requestTypes[idx])
+	}
+	b := bytes.NewBuffer(nil)
+	err := chain.NewEncoder(b).Encode(req)
+	if err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(b.Bytes())
+}
+
+// Receive a raw result from the node.
+func (c *Client) Receive() (*Result, error) {
+	msg, err := c.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	var res Result
+	err = chain.NewDecoder(bytes.NewReader(msg)).Decode(&res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Wait for the next block, acknowledging the previously returned one. Results without a block
+// position, sent when the node has nothing new, only update the head and irreversible positions.
+func (s *BlockStream) Next() (*StreamBlock, error) {
+	for {
+		if s.unacked > 0 {
+			err := s.client.Send(Request{GetBlocksAckV0: &GetBlocksAckRequestV0{NumMessages: s.unacked}})
+			if err != nil {
+				return nil, err
+			}
+			s.unacked = 0
+		}
+		res, err := s.client.Receive()
+		if err != nil {
+			return nil, err
+		}
+		s.unacked++
+		var block StreamBlock
+		switch {
+		case res.GetBlocksV0 != nil:
+			block.GetBlocksResultV0 = *res.GetBlocksV0
+		case res.GetBlocksV1 != nil:
+			block.GetBlocksResultV1 = *res.GetBlocksV1
+		default:
+			return nil, ErrUnexpectedResult
+		}
+		s.head = block.Head
+		s.lib = block.LastIrreversible
+		if block.ThisBlock == nil {
+			continue
+		}
+		block.Forked = s.track(*block.ThisBlock)
+		return &block, nil
+	}
+}
+
+// Head block of the node as of the last received result.
+func (s *BlockStream) Head() BlockPosition {
+	return s.head
+}
+
+// Last irreversible block of the node as of the last received result.
+func (s *BlockStream) LastIrreversible() BlockPosition {
+	return s.lib
+}
+
+// Streamed blocks that are not yet irreversible, lowest first. Use as HavePositions when
+// resuming the stream on a new connection so that the node can detect forks that happened in between.
+func (s *BlockStream) Positions() []BlockPosition {
+	return append([]BlockPosition{}, s.positions...)
+}
+
+// helpers
+
+// Record a newly streamed block, returns the previously streamed blocks it replaces.
+func (s *BlockStream) track(pos BlockPosition) []BlockPosition {
+	var forked []BlockPosition
+	for i := len(s.positions) - 1; i >= 0 && s.positions[i].BlockNum >= pos.BlockNum; i-- {
+		forked = append(forked, s.positions[i])
+	}
+	s.positions = append(s.positions[:len(s.positions)-len(forked)], pos)
+	// irreversible blocks can no longer be forked out
+	n := 0
+	for n < len(s.positions) && s.positions[n].BlockNum <= s.lib.BlockNum {
+		n++
+	}
+	s.positions = s.positions[n:]
+	return forked
+}
+
+// Make sure the variant in the protocol ABI is compatible with the types known to this package.
+func checkVariant(a *chain.Abi, name string, types []string) error {
+	v := a.GetVariant(name)
+	if v == nil {
+		return fmt.Errorf("ship: protocol abi is missing the %s variant", name)
+	}
+	for i, t := range v.Types {
+		if i < len(types) && t != types[i] {
+			return fmt.Errorf("ship: unsupported protocol, expected %s at %s variant index %d, got %s", types[i], name, i, t)
+		}
+	}
+	return nil
+}
+
+type wsConn struct {
+	ws *websocket.Conn
+}
+
+func (c *wsConn) ReadMessage() ([]byte, error) {
+	_, msg, err := c.ws.ReadMessage()
+	return msg, err
+}
+
+func (c *wsConn) WriteMessage(data []byte) error {
+	return c.ws.WriteMessage(websocket.BinaryMessage, data)
+}
+
+func (c *wsConn) Close() error {
+	return c.ws.Close()
+}
@@ -0,0 +1,216 @@
+package ship_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/ship"
+)
+
+const testProtocolAbi = `{
+	"version": "eosio::abi/1.1",
+	"variants": [
+		{"name": "request", "types": ["get_status_request_v0", "get_blocks_request_v0", "get_blocks_ack_request_v0", "get_blocks_request_v1"]},
+		{"name": "result", "types": ["get_status_result_v0", "get_blocks_result_v0", "get_blocks_result_v1"]}
+	]
+}`
+
+const testLegacyProtocolAbi = `{
+	"version": "eosio::abi/1.1",
+	"variants": [
+		{"name": "request", "types": ["get_status_request_v0", "get_blocks_request_v0", "get_blocks_ack_request_v0"]},
+		{"name": "result", "types": ["get_status_result_v0", "get_blocks_result_v0"]}
+	]
+}`
+
+func testPosition(num uint32, fork byte) ship.BlockPosition {
+	var id chain.BlockID
+	id[0], id[1], id[2], id[3] = byte(num>>24), byte(num>>16), byte(num>>8), byte(num)
+	id[31] = fork
+	return ship.BlockPosition{BlockNum: num, BlockID: id}
+}
+
+func testBlocksResult(this *ship.BlockPosition, lib ship.BlockPosition) ship.Result {
+	return ship.Result{GetBlocksV0: &ship.GetBlocksResultV0{
+		Head:             testPosition(3, 'b'),
+		LastIrreversible: lib,
+		ThisBlock:        this,
+	}}
+}
+
+// Serves the given results to the first get_blocks request, respecting max_messages_in_flight,
+// and reports the total number of acknowledged messages once all results are acknowledged.
+func testServer(t *testing.T, results []ship.Result, acked chan<- uint32) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer ws.Close()
+		err = ws.WriteMessage(websocket.TextMessage, []byte(testProtocolAbi))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		send := func(res ship.Result) {
+			err := ws.WriteMessage(websocket.BinaryMessage, encode(res))
+			if err != nil {
+				t.Error(err)
+			}
+		}
+		var credits, total uint32
+		for {
+			_, msg, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req ship.Request
+			err = chain.NewDecoder(bytes.NewReader(msg)).Decode(&req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			switch {
+			case req.GetStatusV0 != nil:
+				send(ship.Result{GetStatusV0: &ship.GetStatusResultV0{Head: testPosition(3, 'a')}})
+			case req.GetBlocksV0 != nil:
+				credits = req.GetBlocksV0.MaxMessagesInFlight
+			case req.GetBlocksAckV0 != nil:
+				credits += req.GetBlocksAckV0.NumMessages
+				total += req.GetBlocksAckV0.NumMessages
+			default:
+				t.Error("unexpected request")
+				return
+			}
+			for credits > 0 && len(results) > 0 {
+				send(results[0])
+				results = results[1:]
+				credits--
+			}
+			if total == uint32(cap(acked)) {
+				acked <- total
+				return
+			}
+		}
+	}))
+}
+
+func TestClient(t *testing.T) {
+	p1a, p2a, p3a := testPosition(1, 'a'), testPosition(2, 'a'), testPosition(3, 'a')
+	p2b, p3b := testPosition(2, 'b'), testPosition(3, 'b')
+	results := []ship.Result{
+		testBlocksResult(&p1a, ship.BlockPosition{}),
+		testBlocksResult(&p2a, p1a),
+		testBlocksResult(&p3a, p1a),
+		testBlocksResult(nil, p1a),
+		testBlocksResult(&p2b, p1a),
+		testBlocksResult(&p3b, p2b),
+	}
+	acked := make(chan uint32, len(results))
+	server := testServer(t, results, acked)
+	defer server.Close()
+
+	client, err := ship.Dial(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"))
+	assert.NoError(t, err)
+	defer client.Close()
+	assert.NotNil(t, client.Abi().GetVariant("result"))
+
+	status, err := client.GetStatus()
+	assert.NoError(t, err)
+	assert.Equal(t, status.Head, p3a)
+
+	stream, err := client.GetBlocks(ship.GetBlocksRequestV1{
+		GetBlocksRequestV0: ship.GetBlocksRequestV0{
+			StartBlockNum:       1,
+			EndBlockNum:         0xffffffff,
+			MaxMessagesInFlight: 2,
+		},
+	})
+	assert.NoError(t, err)
+
+	expected := []struct {
+		this   ship.BlockPosition
+		forked []ship.BlockPosition
+	}{
+		{p1a, nil},
+		{p2a, nil},
+		{p3a, nil},
+		{p2b, []ship.BlockPosition{p3a, p2a}},
+		{p3b, nil},
+	}
+	for _, e := range expected {
+		block, err := stream.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, *block.ThisBlock, e.this)
+		assert.Equal(t, block.Forked, e.forked)
+	}
+	assert.Equal(t, stream.Head(), p3b)
+	assert.Equal(t, stream.LastIrreversible(), p2b)
+	assert.Equal(t, stream.Positions(), []ship.BlockPosition{p3b})
+
+	// acknowledges the last block and then fails when the server hangs up
+	_, err = stream.Next()
+	assert.NotNil(t, err)
+	assert.Equal(t, <-acked, uint32(len(results)))
+}
+
+type testConn struct {
+	messages [][]byte
+	written  [][]byte
+}
+
+func (c *testConn) ReadMessage() ([]byte, error) {
+	if len(c.messages) == 0 {
+		return nil, errors.New("connection closed")
+	}
+	msg := c.messages[0]
+	c.messages = c.messages[1:]
+	return msg, nil
+}
+
+func (c *testConn) WriteMessage(data []byte) error {
+	c.written = append(c.written, data)
+	return nil
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func TestClientProtocol(t *testing.T) {
+	_, err := ship.NewClient(&testConn{messages: [][]byte{[]byte(`{"version": "eosio::abi/1.1"}`)}})
+	assert.NotNil(t, err)
+	_, err = ship.NewClient(&testConn{messages: [][]byte{[]byte(strings.Replace(testProtocolAbi, "get_blocks_ack_request_v0", "get_blocks_ack_request_v1", 1))}})
+	assert.NotNil(t, err)
+
+	p1 := testPosition(1, 'a')
+	res := testBlocksResult(&p1, ship.BlockPosition{})
+	conn := &testConn{messages: [][]byte{[]byte(testLegacyProtocolAbi), encode(res)}}
+	client, err := ship.NewClient(conn)
+	assert.NoError(t, err)
+
+	req := ship.GetBlocksRequestV1{FetchFinalityData: true}
+	_, err = client.GetBlocks(req)
+	assert.NotNil(t, err)
+
+	// finality data not requested, falls back to the v0 request
+	req.FetchFinalityData = false
+	stream, err := client.GetBlocks(req)
+	assert.NoError(t, err)
+	assert.Equal(t, conn.written[0], []byte(encode(ship.Request{GetBlocksV0: &ship.GetBlocksRequestV0{
+		MaxMessagesInFlight: ship.DefaultMaxMessagesInFlight,
+	}})))
+	block, err := stream.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, *block.ThisBlock, p1)
+}
@@ -0,0 +1,89 @@
+package ship
+
+import (
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var tableDeltaTypes = []string{"table_delta_v0", "table_delta_v1"}
+
+// Variant of the table delta versions, only one of the fields is set.
+type TableDelta struct {
+	V0 *TableDeltaV0
+	V1 *TableDeltaV1
+}
+
+type RowV0 struct {
+	// False if the row was removed.
+	Present bool `json:"present"`
+	// Packed variant of the table row type, e.g. contract_row.
+	Data chain.Bytes `json:"data"`
+}
+
+type RowV1 struct {
+	// Zero if the row was removed.
+	Present uint8 `json:"present"`
+	// Packed variant of the table row type, e.g. contract_row.
+	Data chain.Bytes `json:"data"`
+}
+
+// Changes to the rows of one of the chain state tables, e.g. account or contract_row.
+type TableDeltaV0 struct {
+	Name string  `json:"name"`
+	Rows []RowV0 `json:"rows"`
+}
+
+type TableDeltaV1 struct {
+	Name string  `json:"name"`
+	Rows []RowV1 `json:"rows"`
+}
+
+// Name of the chain state table the delta applies to.
+func (td TableDelta) Name() string {
+	switch {
+	case td.V0 != nil:
+		return td.V0.Name
+	case td.V1 != nil:
+		return td.V1.Name
+	}
+	return ""
+}
+
+// The rows of the delta in the version independent v0 form.
+func (td TableDelta) Rows() []RowV0 {
+	switch {
+	case td.V0 != nil:
+		return td.V0.Rows
+	case td.V1 != nil:
+		rows := make([]RowV0, len(td.V1.Rows))
+		for i, row := range td.V1.Rows {
+			rows[i] = RowV0{Present: row.Present != 0, Data: row.Data}
+		}
+		return rows
+	}
+	return nil
+}
+
+// abi.Marshaler conformance
+
+func (td TableDelta) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, td)
+}
+
+// abi.Unmarshaler conformance
+
+func (td *TableDelta) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, td, tableDeltaTypes)
+}
+
+// json.Marshaler conformance
+
+func (td TableDelta) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(td, tableDeltaTypes)
+}
+
+// json.Unmarshaler conformance
+
+func (td *TableDelta) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, td, tableDeltaTypes)
+}
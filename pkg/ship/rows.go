@@ -0,0 +1,299 @@
+package ship
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var (
+	accountTypes     = []string{"account_v0"}
+	contractRowTypes = []string{"contract_row_v0"}
+)
+
+// Variant of the account table row versions, only one of the fields is set.
+type Account struct {
+	V0 *AccountV0
+}
+
+// Variant of the contract_row table row versions, only one of the fields is set.
+type ContractRow struct {
+	V0 *ContractRowV0
+}
+
+type AccountV0 struct {
+	Name         chain.Name           `json:"name"`
+	CreationDate chain.BlockTimestamp `json:"creation_date"`
+	// Packed ABI of the contract, empty if no ABI is set.
+	Abi chain.Bytes `json:"abi"`
+}
+
+type ContractRowV0 struct {
+	Code       chain.Name  `json:"code"`
+	Scope      chain.Name  `json:"scope"`
+	Table      chain.Name  `json:"table"`
+	PrimaryKey uint64      `json:"primary_key"`
+	Payer      chain.Name  `json:"payer"`
+	Value      chain.Bytes `json:"value"`
+}
+
+type RowEventType uint8
+
+const (
+	RowInsert RowEventType = iota
+	RowUpdate
+	RowDelete
+)
+
+// Change to a contract table row decoded by a DeltaDecoder.
+type RowEvent struct {
+	Type       RowEventType
+	Code       chain.Name
+	Scope      chain.Name
+	Table      chain.Name
+	PrimaryKey chain.Name
+	Payer      chain.Name
+	// Packed row, for deleted rows this is the last value of the row.
+	Data chain.Bytes
+	// Row decoded using the ABI of the contract, nil if the contract has no ABI or it does not describe the table.
+	Value interface{}
+}
+
+// Decodes contract table deltas into row events, keeping track of the contract ABIs as they are updated.
+//
+// The state history node does not tell inserts and updates apart, rows are reported as inserted
+// the first time the decoder sees them and updated after that. Rows that existed before the
+// decoder started are thus reported as inserted the first time they change. The key of every
+// row seen and not deleted since is kept, use Filter to limit this to the contracts of interest.
+type DeltaDecoder struct {
+	// Optional, only rows of the contracts for which this returns true are decoded and tracked.
+	Filter func(code chain.Name) bool
+
+	abis map[chain.Name]*chain.Abi
+	rows map[rowKey]struct{}
+}
+
+type rowKey struct {
+	code, scope, table chain.Name
+	primaryKey         uint64
+}
+
+type setabi struct {
+	Account chain.Name
+	Abi     chain.Bytes
+}
+
+func NewDeltaDecoder() *DeltaDecoder {
+	return &DeltaDecoder{
+		abis: make(map[chain.Name]*chain.Abi),
+		rows: make(map[rowKey]struct{}),
+	}
+}
+
+// ABI currently known for given contract, nil if none.
+func (d *DeltaDecoder) Abi(contract chain.Name) *chain.Abi {
+	return d.abis[contract]
+}
+
+// Set the ABI of a contract, e.g. one fetched from an API node before streaming. Passing nil removes it.
+func (d *DeltaDecoder) SetAbi(contract chain.Name, a *chain.Abi) {
+	if a == nil {
+		delete(d.abis, contract)
+	} else {
+		d.abis[contract] = a
+	}
+}
+
+// Update the known ABIs from the eosio::setabi actions in given traces.
+func (d *DeltaDecoder) ApplyTraces(traces []TransactionTrace) error {
+	for _, trace := range traces {
+		if trace.V0 == nil || trace.V0.Status != chain.TransactionStatusExecuted {
+			continue
+		}
+		for _, at := range trace.V0.ActionTraces {
+			at := at.Common()
+			if at == nil || at.Receiver != chain.N("eosio") || at.Act.Account != chain.N("eosio") || at.Act.Name != chain.N("setabi") {
+				continue
+			}
+			var sa setabi
+			err := at.Act.DecodeInto(&sa)
+			if err != nil {
+				return err
+			}
+			err = d.updateAbi(sa.Account, sa.Abi)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Decode the contract rows in given deltas, the ABIs are first updated from the account deltas.
+func (d *DeltaDecoder) Decode(deltas []TableDelta) ([]RowEvent, error) {
+	for _, delta := range deltas {
+		if delta.Name() != "account" {
+			continue
+		}
+		for _, row := range delta.Rows() {
+			if !row.Present {
+				continue
+			}
+			var account Account
+			err := chain.NewDecoder(bytes.NewReader(row.Data)).Decode(&account)
+			if err != nil {
+				return nil, err
+			}
+			if account.V0 == nil {
+				continue
+			}
+			err = d.updateAbi(account.V0.Name, account.V0.Abi)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	var events []RowEvent
+	for _, delta := range deltas {
+		if delta.Name() != "contract_row" {
+			continue
+		}
+		for _, row := range delta.Rows() {
+			var cr ContractRow
+			err := chain.NewDecoder(bytes.NewReader(row.Data)).Decode(&cr)
+			if err != nil {
+				return nil, err
+			}
+			if cr.V0 == nil || (d.Filter != nil && !d.Filter(cr.V0.Code)) {
+				continue
+			}
+			event, err := d.decodeRow(cr.V0, row.Present)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, *event)
+		}
+	}
+	return events, nil
+}
+
+// Apply the traces and decode the deltas of a block received from the state history node.
+func (d *DeltaDecoder) DecodeResult(r GetBlocksResultV0) ([]RowEvent, error) {
+	traces, err := r.TransactionTraces()
+	if err != nil {
+		return nil, err
+	}
+	err = d.ApplyTraces(traces)
+	if err != nil {
+		return nil, err
+	}
+	deltas, err := r.TableDeltas()
+	if err != nil {
+		return nil, err
+	}
+	return d.Decode(deltas)
+}
+
+func (t RowEventType) String() string {
+	switch t {
+	case RowInsert:
+		return "insert"
+	case RowUpdate:
+		return "update"
+	case RowDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+// abi.Marshaler conformance
+
+func (a Account) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, a)
+}
+
+func (cr ContractRow) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, cr)
+}
+
+// abi.Unmarshaler conformance
+
+func (a *Account) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, a, accountTypes)
+}
+
+func (cr *ContractRow) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, cr, contractRowTypes)
+}
+
+// json.Marshaler conformance
+
+func (a Account) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(a, accountTypes)
+}
+
+func (cr ContractRow) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(cr, contractRowTypes)
+}
+
+// json.Unmarshaler conformance
+
+func (a *Account) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, a, accountTypes)
+}
+
+func (cr *ContractRow) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, cr, contractRowTypes)
+}
+
+// helpers
+
+func (d *DeltaDecoder) updateAbi(contract chain.Name, data chain.Bytes) error {
+	if len(data) == 0 {
+		d.SetAbi(contract, nil)
+		return nil
+	}
+	var a chain.Abi
+	err := chain.NewDecoder(bytes.NewReader(data)).Decode(&a)
+	if err != nil {
+		return fmt.Errorf("ship: invalid abi for %s: %w", contract, err)
+	}
+	d.SetAbi(contract, &a)
+	return nil
+}
+
+func (d *DeltaDecoder) decodeRow(row *ContractRowV0, present bool) (*RowEvent, error) {
+	event := RowEvent{
+		Code:       row.Code,
+		Scope:      row.Scope,
+		Table:      row.Table,
+		PrimaryKey: chain.Name(row.PrimaryKey),
+		Payer:      row.Payer,
+		Data:       row.Value,
+	}
+	key := rowKey{row.Code, row.Scope, row.Table, row.PrimaryKey}
+	_, known := d.rows[key]
+	switch {
+	case !present:
+		event.Type = RowDelete
+		delete(d.rows, key)
+	case known:
+		event.Type = RowUpdate
+	default:
+		event.Type = RowInsert
+		d.rows[key] = struct{}{}
+	}
+	if a := d.abis[row.Code]; a != nil {
+		if table := a.GetTable(row.Table); table != nil {
+			value, err := a.Decode(bytes.NewReader(row.Value), table.Type)
+			if err != nil {
+				return nil, fmt.Errorf("ship: unable to decode %s row in %s: %w", row.Table, row.Code, err)
+			}
+			event.Value = value
+		}
+	}
+	return &event, nil
+}
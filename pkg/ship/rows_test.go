@@ -0,0 +1,129 @@
+package ship_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/ship"
+)
+
+var testTokenAbi = `{
+	"version": "eosio::abi/1.1",
+	"structs": [{"name": "account", "base": "", "fields": [{"name": "balance", "type": "asset"}]}],
+	"tables": [{"name": "accounts", "index_type": "i64", "key_names": [], "key_types": [], "type": "account"}]
+}`
+
+func testAbiBytes(v string) chain.Bytes {
+	var a chain.Abi
+	err := json.Unmarshal([]byte(v), &a)
+	if err != nil {
+		panic(err)
+	}
+	return encode(a)
+}
+
+func testContractRow(code string, primaryKey string, present bool, value chain.Bytes) ship.RowV0 {
+	return ship.RowV0{
+		Present: present,
+		Data: encode(ship.ContractRow{V0: &ship.ContractRowV0{
+			Code:       chain.N(code),
+			Scope:      chain.N("alice"),
+			Table:      chain.N("accounts"),
+			PrimaryKey: uint64(chain.N(primaryKey)),
+			Payer:      chain.N("alice"),
+			Value:      value,
+		}}),
+	}
+}
+
+func TestDeltaDecoder(t *testing.T) {
+	balance := encode(*chain.A("1.0000 EOS"))
+	decoder := ship.NewDeltaDecoder()
+	decoder.Filter = func(code chain.Name) bool {
+		return code != chain.N("ignored")
+	}
+
+	events, err := decoder.Decode([]ship.TableDelta{
+		{V0: &ship.TableDeltaV0{Name: "contract_row", Rows: []ship.RowV0{
+			testContractRow("eosio.token", "eos", true, balance),
+			testContractRow("ignored", "eos", true, balance),
+		}}},
+		// abi updates apply to the rows in the same block
+		{V0: &ship.TableDeltaV0{Name: "account", Rows: []ship.RowV0{
+			{Present: true, Data: encode(ship.Account{V0: &ship.AccountV0{
+				Name: chain.N("eosio.token"),
+				Abi:  testAbiBytes(testTokenAbi),
+			}})},
+		}}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0], ship.RowEvent{
+		Type:       ship.RowInsert,
+		Code:       chain.N("eosio.token"),
+		Scope:      chain.N("alice"),
+		Table:      chain.N("accounts"),
+		PrimaryKey: chain.N("eos"),
+		Payer:      chain.N("alice"),
+		Data:       balance,
+		Value:      map[string]interface{}{"balance": *chain.A("1.0000 EOS")},
+	})
+	assert.NotNil(t, decoder.Abi(chain.N("eosio.token")))
+
+	events, err = decoder.Decode([]ship.TableDelta{
+		{V1: &ship.TableDeltaV1{Name: "contract_row", Rows: []ship.RowV1{
+			{Present: 1, Data: testContractRow("eosio.token", "eos", true, balance).Data},
+			{Present: 0, Data: testContractRow("eosio.token", "eos", false, balance).Data},
+			{Present: 1, Data: testContractRow("other", "foo", true, chain.Bytes{0x01}).Data},
+		}}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, len(events), 3)
+	assert.Equal(t, events[0].Type, ship.RowUpdate)
+	assert.Equal(t, events[1].Type, ship.RowDelete)
+	assert.Equal(t, events[1].Value, map[string]interface{}{"balance": *chain.A("1.0000 EOS")})
+	assert.Equal(t, events[2].Type, ship.RowInsert)
+	assert.True(t, events[2].Value == nil)
+
+	// abi removed
+	_, err = decoder.Decode([]ship.TableDelta{
+		{V0: &ship.TableDeltaV0{Name: "account", Rows: []ship.RowV0{
+			{Present: true, Data: encode(ship.Account{V0: &ship.AccountV0{Name: chain.N("eosio.token")}})},
+		}}},
+	})
+	assert.NoError(t, err)
+	assert.True(t, decoder.Abi(chain.N("eosio.token")) == nil)
+
+	// abi set by action
+	setabi := chain.NewAction(chain.N("eosio"), chain.N("setabi"), nil, encode(struct {
+		Account chain.Name
+		Abi     chain.Bytes
+	}{chain.N("other"), testAbiBytes(testTokenAbi)}))
+	traces := []ship.TransactionTrace{{V0: &ship.TransactionTraceV0{
+		Status: chain.TransactionStatusExecuted,
+		ActionTraces: []ship.ActionTrace{
+			{V1: &ship.ActionTraceV1{ActionTraceV0: ship.ActionTraceV0{Receiver: chain.N("eosio"), Act: *setabi}}},
+		},
+	}}}
+	events, err = decoder.DecodeResult(ship.GetBlocksResultV0{
+		Traces: encode(traces),
+		Deltas: encode([]ship.TableDelta{
+			{V0: &ship.TableDeltaV0{Name: "contract_row", Rows: []ship.RowV0{
+				testContractRow("other", "foo", true, balance),
+			}}},
+		}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, events[0].Type, ship.RowUpdate)
+	assert.Equal(t, events[0].Value, map[string]interface{}{"balance": *chain.A("1.0000 EOS")})
+
+	// invalid row for the abi
+	_, err = decoder.Decode([]ship.TableDelta{
+		{V0: &ship.TableDeltaV0{Name: "contract_row", Rows: []ship.RowV0{
+			testContractRow("other", "foo", true, chain.Bytes{0x01}),
+		}}},
+	})
+	assert.NotNil(t, err)
+}
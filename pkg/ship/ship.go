@@ -0,0 +1,178 @@
+// Package ship implements the state history protocol served by the nodeos state_history_plugin,
+// commonly referred to as SHiP, and a client to stream blocks, traces and table deltas over it.
+package ship
+
+import (
+	"bytes"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Type names of the request variant, in protocol order.
+var requestTypes = []string{
+	"get_status_request_v0",
+	"get_blocks_request_v0",
+	"get_blocks_ack_request_v0",
+	"get_blocks_request_v1",
+}
+
+// Type names of the result variant, in protocol order.
+var resultTypes = []string{
+	"get_status_result_v0",
+	"get_blocks_result_v0",
+	"get_blocks_result_v1",
+}
+
+// Request sent to the state history node, only one of the fields should be set.
+type Request struct {
+	GetStatusV0    *GetStatusRequestV0
+	GetBlocksV0    *GetBlocksRequestV0
+	GetBlocksAckV0 *GetBlocksAckRequestV0
+	GetBlocksV1    *GetBlocksRequestV1
+}
+
+// Result sent by the state history node, only one of the fields is set.
+type Result struct {
+	GetStatusV0 *GetStatusResultV0
+	GetBlocksV0 *GetBlocksResultV0
+	GetBlocksV1 *GetBlocksResultV1
+}
+
+// Number and id of a block.
+type BlockPosition struct {
+	BlockNum uint32        `json:"block_num"`
+	BlockID  chain.BlockID `json:"block_id"`
+}
+
+// Request the status of the node, answered with a GetStatusResultV0.
+type GetStatusRequestV0 struct{}
+
+// Request a stream of blocks, answered with one GetBlocksResultV0 per block.
+type GetBlocksRequestV0 struct {
+	StartBlockNum uint32 `json:"start_block_num"`
+	// Block number to stop at (exclusive), use 0xffffffff to stream forever.
+	EndBlockNum uint32 `json:"end_block_num"`
+	// Number of results the node sends before waiting for an acknowledgement.
+	MaxMessagesInFlight uint32 `json:"max_messages_in_flight"`
+	// Blocks the client already has, the node resumes from the first one it disagrees with.
+	HavePositions    []BlockPosition `json:"have_positions"`
+	IrreversibleOnly bool            `json:"irreversible_only"`
+	FetchBlock       bool            `json:"fetch_block"`
+	FetchTraces      bool            `json:"fetch_traces"`
+	FetchDeltas      bool            `json:"fetch_deltas"`
+}
+
+// Acknowledge that results have been processed so the node can send more.
+type GetBlocksAckRequestV0 struct {
+	NumMessages uint32 `json:"num_messages"`
+}
+
+// Request a stream of blocks including finality data, answered with one GetBlocksResultV1 per block.
+type GetBlocksRequestV1 struct {
+	GetBlocksRequestV0
+	FetchFinalityData bool `json:"fetch_finality_data"`
+}
+
+type GetStatusResultV0 struct {
+	Head                 BlockPosition `json:"head"`
+	LastIrreversible     BlockPosition `json:"last_irreversible"`
+	TraceBeginBlock      uint32        `json:"trace_begin_block"`
+	TraceEndBlock        uint32        `json:"trace_end_block"`
+	ChainStateBeginBlock uint32        `json:"chain_state_begin_block"`
+	ChainStateEndBlock   uint32        `json:"chain_state_end_block"`
+	// Binary extension, zero when the node does not send it.
+	ChainID chain.Checksum256 `json:"chain_id" eosio:"extension"`
+}
+
+type GetBlocksResultV0 struct {
+	Head             BlockPosition  `json:"head"`
+	LastIrreversible BlockPosition  `json:"last_irreversible"`
+	ThisBlock        *BlockPosition `json:"this_block" eosio:"optional"`
+	PrevBlock        *BlockPosition `json:"prev_block" eosio:"optional"`
+	// Packed signed_block, empty unless requested with FetchBlock.
+	Block chain.Bytes `json:"block" eosio:"optional"`
+	// Packed transaction_trace[], empty unless requested with FetchTraces.
+	Traces chain.Bytes `json:"traces" eosio:"optional"`
+	// Packed table_delta[], empty unless requested with FetchDeltas.
+	Deltas chain.Bytes `json:"deltas" eosio:"optional"`
+}
+
+type GetBlocksResultV1 struct {
+	GetBlocksResultV0
+	// Packed finality_data, empty unless requested with FetchFinalityData.
+	FinalityData chain.Bytes `json:"finality_data" eosio:"optional"`
+}
+
+// Decode the signed block, returns nil if the result has no block.
+func (r GetBlocksResultV0) SignedBlock() (*chain.SignedBlock, error) {
+	if len(r.Block) == 0 {
+		return nil, nil
+	}
+	var rv chain.SignedBlock
+	err := chain.NewDecoder(bytes.NewReader(r.Block)).Decode(&rv)
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// Decode the transaction traces, returns nil if the result has no traces.
+func (r GetBlocksResultV0) TransactionTraces() ([]TransactionTrace, error) {
+	if len(r.Traces) == 0 {
+		return nil, nil
+	}
+	var rv []TransactionTrace
+	err := chain.NewDecoder(bytes.NewReader(r.Traces)).Decode(&rv)
+	return rv, err
+}
+
+// Decode the table deltas, returns nil if the result has no deltas.
+func (r GetBlocksResultV0) TableDeltas() ([]TableDelta, error) {
+	if len(r.Deltas) == 0 {
+		return nil, nil
+	}
+	var rv []TableDelta
+	err := chain.NewDecoder(bytes.NewReader(r.Deltas)).Decode(&rv)
+	return rv, err
+}
+
+// abi.Marshaler conformance
+
+func (r Request) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, r)
+}
+
+func (r Result) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, r)
+}
+
+// abi.Unmarshaler conformance
+
+func (r *Request) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, r, requestTypes)
+}
+
+func (r *Result) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, r, resultTypes)
+}
+
+// json.Marshaler conformance
+
+func (r Request) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(r, requestTypes)
+}
+
+func (r Result) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(r, resultTypes)
+}
+
+// json.Unmarshaler conformance
+
+func (r *Request) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, r, requestTypes)
+}
+
+func (r *Result) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, r, resultTypes)
+}
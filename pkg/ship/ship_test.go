@@ -0,0 +1,175 @@
+package ship_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/ship"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func encode(v interface{}) chain.Bytes {
+	b := bytes.NewBuffer(nil)
+	err := chain.NewEncoder(b).Encode(v)
+	if err != nil {
+		panic(err)
+	}
+	return b.Bytes()
+}
+
+func TestRequest(t *testing.T) {
+	req := ship.Request{GetBlocksV0: &ship.GetBlocksRequestV0{
+		StartBlockNum:       1,
+		EndBlockNum:         0xffffffff,
+		MaxMessagesInFlight: 5,
+		FetchBlock:          true,
+		FetchTraces:         true,
+	}}
+	assert.ABICoding(t, req, mustDecodeHex("0101000000ffffffff050000000000010100"))
+	assert.JSONCoding(t, req, `[
+		"get_blocks_request_v0",
+		{
+			"start_block_num": 1,
+			"end_block_num": 4294967295,
+			"max_messages_in_flight": 5,
+			"have_positions": null,
+			"irreversible_only": false,
+			"fetch_block": true,
+			"fetch_traces": true,
+			"fetch_deltas": false
+		}
+	]`)
+
+	req = ship.Request{GetBlocksV1: &ship.GetBlocksRequestV1{
+		GetBlocksRequestV0: ship.GetBlocksRequestV0{
+			StartBlockNum: 2,
+			EndBlockNum:   3,
+			HavePositions: []ship.BlockPosition{{BlockNum: 1}},
+		},
+		FetchFinalityData: true,
+	}}
+	assert.ABICoding(t, req, mustDecodeHex(
+		"03020000000300000000000000010100000000000000000000000000000000000000000000000000000000000000000000000000000001",
+	))
+
+	assert.ABICoding(t, ship.Request{GetStatusV0: &ship.GetStatusRequestV0{}}, []byte{0x00})
+	assert.ABICoding(t, ship.Request{GetBlocksAckV0: &ship.GetBlocksAckRequestV0{NumMessages: 2}}, []byte{0x02, 0x02, 0x00, 0x00, 0x00})
+
+	err := chain.NewEncoder(bytes.NewBuffer(nil)).Encode(ship.Request{})
+	assert.NotNil(t, err)
+	var res ship.Result
+	err = chain.NewDecoder(bytes.NewReader([]byte{0x03})).Decode(&res)
+	assert.NotNil(t, err)
+}
+
+func TestStatusResult(t *testing.T) {
+	var id chain.BlockID
+	copy(id[:], mustDecodeHex("0000000a"))
+	res := ship.Result{GetStatusV0: &ship.GetStatusResultV0{
+		Head:                 ship.BlockPosition{BlockNum: 10, BlockID: id},
+		LastIrreversible:     ship.BlockPosition{BlockNum: 10, BlockID: id},
+		TraceBeginBlock:      1,
+		TraceEndBlock:        11,
+		ChainStateBeginBlock: 1,
+		ChainStateEndBlock:   11,
+		ChainID:              chain.Checksum256Digest([]byte("chain")),
+	}}
+	data := encode(res)
+	assert.Equal(t, len(data), 1+2*36+4*4+32)
+
+	// nodes that do not send the chain id extension
+	var decoded ship.Result
+	err := chain.NewDecoder(bytes.NewReader(data[:len(data)-32])).Decode(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded.GetStatusV0.ChainID, chain.Checksum256{})
+	assert.Equal(t, decoded.GetStatusV0.TraceEndBlock, uint32(11))
+}
+
+func TestBlocksResult(t *testing.T) {
+	block := chain.SignedBlock{
+		SignedBlockHeader: chain.SignedBlockHeader{
+			BlockHeader: chain.BlockHeader{
+				Timestamp: chain.BlockTimestamp(1),
+				Producer:  chain.N("eosio"),
+			},
+			ProducerSignature: *chain.NewSignature(chain.K1, make([]byte, 65)),
+		},
+	}
+	errorCode := uint64(42)
+	traces := []ship.TransactionTrace{{V0: &ship.TransactionTraceV0{
+		ID:            chain.Checksum256Digest([]byte("trx")),
+		Status:        chain.TransactionStatusExecuted,
+		CpuUsageUs:    100,
+		NetUsageWords: 12,
+		ActionTraces: []ship.ActionTrace{
+			{V0: &ship.ActionTraceV0{
+				ActionOrdinal: 1,
+				Receipt: &ship.ActionReceipt{V0: &ship.ActionReceiptV0{
+					Receiver:       chain.N("eosio.token"),
+					GlobalSequence: 1,
+					AuthSequence:   []ship.AccountAuthSequence{{Account: chain.N("alice"), Sequence: 1}},
+				}},
+				Receiver: chain.N("eosio.token"),
+				Act:      *chain.NewAction(chain.N("eosio.token"), chain.N("transfer"), nil, chain.Bytes{0x01}),
+			}},
+			{V1: &ship.ActionTraceV1{
+				ActionTraceV0: ship.ActionTraceV0{
+					ActionOrdinal:        2,
+					CreatorActionOrdinal: 1,
+					Receiver:             chain.N("alice"),
+					Act:                  *chain.NewAction(chain.N("eosio.token"), chain.N("transfer"), nil, chain.Bytes{0x01}),
+					ErrorCode:            &errorCode,
+				},
+				ReturnValue: chain.Bytes{0xff},
+			}},
+		},
+	}}}
+	deltas := []ship.TableDelta{
+		{V0: &ship.TableDeltaV0{Name: "account", Rows: []ship.RowV0{{Present: true, Data: chain.Bytes{0x00}}}}},
+		{V1: &ship.TableDeltaV1{Name: "contract_row", Rows: []ship.RowV1{{Present: 0, Data: chain.Bytes{0x00}}}}},
+	}
+	res := ship.Result{GetBlocksV0: &ship.GetBlocksResultV0{
+		ThisBlock: &ship.BlockPosition{BlockNum: 2},
+		Block:     encode(block),
+		Traces:    encode(traces),
+		Deltas:    encode(deltas),
+	}}
+	var decoded ship.Result
+	err := chain.NewDecoder(bytes.NewReader(encode(res))).Decode(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded, res)
+
+	decodedBlock, err := decoded.GetBlocksV0.SignedBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, decodedBlock.Producer, chain.N("eosio"))
+
+	decodedTraces, err := decoded.GetBlocksV0.TransactionTraces()
+	assert.NoError(t, err)
+	assert.Equal(t, decodedTraces, traces)
+	assert.Equal(t, decodedTraces[0].V0.ActionTraces[1].Common().ActionOrdinal, uint(2))
+	assert.Equal(t, *decodedTraces[0].V0.ActionTraces[1].Common().ErrorCode, uint64(42))
+	assert.Equal(t, decodedTraces[0].V0.ActionTraces[0].Common().Receipt.V0.GlobalSequence, uint64(1))
+
+	decodedDeltas, err := decoded.GetBlocksV0.TableDeltas()
+	assert.NoError(t, err)
+	assert.Equal(t, decodedDeltas[0].Name(), "account")
+	assert.Equal(t, decodedDeltas[1].Name(), "contract_row")
+	assert.Equal(t, decodedDeltas[1].Rows(), []ship.RowV0{{Present: false, Data: chain.Bytes{0x00}}})
+
+	assert.JSONCoding(t, deltas[0], `["table_delta_v0", {"name": "account", "rows": [{"present": true, "data": "00"}]}]`)
+
+	empty := ship.GetBlocksResultV0{}
+	b, err := empty.SignedBlock()
+	assert.NoError(t, err)
+	assert.True(t, b == nil)
+}
@@ -0,0 +1,179 @@
+package ship
+
+import (
+	"github.com/greymass/go-eosio/pkg/abi"
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var (
+	actionReceiptTypes      = []string{"action_receipt_v0"}
+	actionTraceTypes        = []string{"action_trace_v0", "action_trace_v1"}
+	partialTransactionTypes = []string{"partial_transaction_v0"}
+	transactionTraceTypes   = []string{"transaction_trace_v0"}
+)
+
+// Variant of the action receipt versions, only one of the fields is set.
+type ActionReceipt struct {
+	V0 *ActionReceiptV0
+}
+
+// Variant of the action trace versions, only one of the fields is set.
+type ActionTrace struct {
+	V0 *ActionTraceV0
+	V1 *ActionTraceV1
+}
+
+// Variant of the partial transaction versions, only one of the fields is set.
+type PartialTransaction struct {
+	V0 *PartialTransactionV0
+}
+
+// Variant of the transaction trace versions, only one of the fields is set.
+type TransactionTrace struct {
+	V0 *TransactionTraceV0
+}
+
+type AccountAuthSequence struct {
+	Account  chain.Name `json:"account"`
+	Sequence uint64     `json:"sequence"`
+}
+
+type AccountDelta = chain.AccountDelta
+
+type ActionReceiptV0 struct {
+	Receiver       chain.Name            `json:"receiver"`
+	ActDigest      chain.Checksum256     `json:"act_digest"`
+	GlobalSequence uint64                `json:"global_sequence"`
+	RecvSequence   uint64                `json:"recv_sequence"`
+	AuthSequence   []AccountAuthSequence `json:"auth_sequence"`
+	CodeSequence   uint                  `json:"code_sequence"`
+	AbiSequence    uint                  `json:"abi_sequence"`
+}
+
+type ActionTraceV0 struct {
+	ActionOrdinal        uint           `json:"action_ordinal"`
+	CreatorActionOrdinal uint           `json:"creator_action_ordinal"`
+	Receipt              *ActionReceipt `json:"receipt" eosio:"optional"`
+	Receiver             chain.Name     `json:"receiver"`
+	Act                  chain.Action   `json:"act"`
+	ContextFree          bool           `json:"context_free"`
+	Elapsed              int64          `json:"elapsed"`
+	Console              string         `json:"console"`
+	AccountRamDeltas     []AccountDelta `json:"account_ram_deltas"`
+	Except               *string        `json:"except" eosio:"optional"`
+	ErrorCode            *uint64        `json:"error_code" eosio:"optional"`
+}
+
+type ActionTraceV1 struct {
+	ActionTraceV0
+	ReturnValue chain.Bytes `json:"return_value"`
+}
+
+type PartialTransactionV0 struct {
+	Expiration            chain.TimePointSec           `json:"expiration"`
+	RefBlockNum           uint16                       `json:"ref_block_num"`
+	RefBlockPrefix        uint32                       `json:"ref_block_prefix"`
+	MaxNetUsageWords      uint                         `json:"max_net_usage_words"`
+	MaxCpuUsageMs         uint8                        `json:"max_cpu_usage_ms"`
+	DelaySec              uint                         `json:"delay_sec"`
+	TransactionExtensions []chain.TransactionExtension `json:"transaction_extensions"`
+	Signatures            []chain.Signature            `json:"signatures"`
+	ContextFreeData       []chain.Bytes                `json:"context_free_data"`
+}
+
+type TransactionTraceV0 struct {
+	ID              chain.Checksum256       `json:"id"`
+	Status          chain.TransactionStatus `json:"status"`
+	CpuUsageUs      uint32                  `json:"cpu_usage_us"`
+	NetUsageWords   uint                    `json:"net_usage_words"`
+	Elapsed         int64                   `json:"elapsed"`
+	NetUsage        uint64                  `json:"net_usage"`
+	Scheduled       bool                    `json:"scheduled"`
+	ActionTraces    []ActionTrace           `json:"action_traces"`
+	AccountRamDelta *AccountDelta           `json:"account_ram_delta" eosio:"optional"`
+	Except          *string                 `json:"except" eosio:"optional"`
+	ErrorCode       *uint64                 `json:"error_code" eosio:"optional"`
+	// Trace of the failed deferred transaction, set for onerror handlers.
+	FailedDtrxTrace *TransactionTrace   `json:"failed_dtrx_trace" eosio:"optional"`
+	Partial         *PartialTransaction `json:"partial" eosio:"optional"`
+}
+
+// The version independent fields of the action trace.
+func (at ActionTrace) Common() *ActionTraceV0 {
+	if at.V1 != nil {
+		return &at.V1.ActionTraceV0
+	}
+	return at.V0
+}
+
+// abi.Marshaler conformance
+
+func (ar ActionReceipt) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, ar)
+}
+
+func (at ActionTrace) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, at)
+}
+
+func (pt PartialTransaction) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, pt)
+}
+
+func (tt TransactionTrace) MarshalABI(e *abi.Encoder) error {
+	return marshalVariant(e, tt)
+}
+
+// abi.Unmarshaler conformance
+
+func (ar *ActionReceipt) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, ar, actionReceiptTypes)
+}
+
+func (at *ActionTrace) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, at, actionTraceTypes)
+}
+
+func (pt *PartialTransaction) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, pt, partialTransactionTypes)
+}
+
+func (tt *TransactionTrace) UnmarshalABI(d *abi.Decoder) error {
+	return unmarshalVariant(d, tt, transactionTraceTypes)
+}
+
+// json.Marshaler conformance
+
+func (ar ActionReceipt) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(ar, actionReceiptTypes)
+}
+
+func (at ActionTrace) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(at, actionTraceTypes)
+}
+
+func (pt PartialTransaction) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(pt, partialTransactionTypes)
+}
+
+func (tt TransactionTrace) MarshalJSON() ([]byte, error) {
+	return marshalVariantJSON(tt, transactionTraceTypes)
+}
+
+// json.Unmarshaler conformance
+
+func (ar *ActionReceipt) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, ar, actionReceiptTypes)
+}
+
+func (at *ActionTrace) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, at, actionTraceTypes)
+}
+
+func (pt *PartialTransaction) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, pt, partialTransactionTypes)
+}
+
+func (tt *TransactionTrace) UnmarshalJSON(b []byte) error {
+	return unmarshalVariantJSON(b, tt, transactionTraceTypes)
+}
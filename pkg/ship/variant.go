@@ -0,0 +1,87 @@
+package ship
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/greymass/go-eosio/pkg/abi"
+)
+
+// The variant types in this package are structs with one pointer field per variant type,
+// in the same order as the types are listed in the state history protocol ABI.
+// Exactly one of the fields should be set when encoding.
+
+func variantValue(v interface{}) (int, interface{}) {
+	rv := reflect.ValueOf(v)
+	for i := 0; i < rv.NumField(); i++ {
+		if f := rv.Field(i); !f.IsNil() {
+			return i, f.Interface()
+		}
+	}
+	return -1, nil
+}
+
+func marshalVariant(e *abi.Encoder, v interface{}) error {
+	idx, value := variantValue(v)
+	if idx == -1 {
+		return errors.New("ship: unable to encode empty variant")
+	}
+	err := e.WriteVaruint(uint(idx))
+	if err != nil {
+		return err
+	}
+	return e.Encode(value)
+}
+
+func unmarshalVariant(d *abi.Decoder, v interface{}, names []string) error {
+	idx, err := d.ReadVaruint()
+	if err != nil {
+		return err
+	}
+	if int(idx) >= len(names) {
+		return fmt.Errorf("ship: invalid variant index %d, expected max %d", idx, len(names)-1)
+	}
+	field := setVariantField(v, int(idx))
+	return d.Decode(field)
+}
+
+func marshalVariantJSON(v interface{}, names []string) ([]byte, error) {
+	idx, value := variantValue(v)
+	if idx == -1 {
+		return []byte("null"), nil
+	}
+	return json.Marshal([]interface{}{names[idx], value})
+}
+
+func unmarshalVariantJSON(b []byte, v interface{}, names []string) error {
+	var pair []json.RawMessage
+	err := json.Unmarshal(b, &pair)
+	if err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return errors.New("ship: variant must be a [type, value] pair")
+	}
+	var name string
+	err = json.Unmarshal(pair[0], &name)
+	if err != nil {
+		return err
+	}
+	for idx, n := range names {
+		if n == name {
+			return json.Unmarshal(pair[1], setVariantField(v, idx))
+		}
+	}
+	return fmt.Errorf("ship: unknown variant type %s", name)
+}
+
+// Clear all fields of the variant and allocate the field at idx, returns a pointer to the new value.
+func setVariantField(v interface{}, idx int) interface{} {
+	rv := reflect.ValueOf(v).Elem()
+	rv.Set(reflect.Zero(rv.Type()))
+	f := rv.Field(idx)
+	f.Set(reflect.New(f.Type().Elem()))
+	return f.Interface()
+}
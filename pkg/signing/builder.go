@@ -0,0 +1,190 @@
+package signing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Default time until a built transaction expires.
+const DefaultExpiration = 2 * time.Minute
+
+var (
+	ErrNoReferenceBlock = errors.New("signing: transaction has no reference block")
+	ErrNoChainID        = errors.New("signing: transaction has no chain id")
+)
+
+// Fluent builder for transactions, e.g.
+//
+//	stx, err := signing.NewTransactionBuilder().
+//		WithChainInfo(info).
+//		AddActionData(contract, chain.N("transfer"), auth, transfer).
+//		Sign(ctx, provider)
+//
+// The first error encountered is retained and returned by Build and Sign.
+type TransactionBuilder struct {
+	tx       chain.Transaction
+	chainID  *chain.Checksum256
+	hasRef   bool
+	headTime time.Time
+	expireIn time.Duration
+	expires  *chain.TimePointSec
+	err      error
+}
+
+func NewTransactionBuilder() *TransactionBuilder {
+	return &TransactionBuilder{
+		tx: chain.Transaction{
+			ContextFreeActions: []chain.Action{},
+			Actions:            []chain.Action{},
+			Extensions:         []chain.TransactionExtension{},
+		},
+		expireIn: DefaultExpiration,
+	}
+}
+
+// Use the chain id, last irreversible block as reference and head block time as the base for the expiration.
+func (b *TransactionBuilder) WithChainInfo(info chain.ChainInfo) *TransactionBuilder {
+	return b.WithChainID(info.ChainID).
+		WithReferenceBlock(info.LastIrreversibleBlockID).
+		WithHeadTime(info.HeadBlockTime.Time())
+}
+
+// Set the chain id used when signing.
+func (b *TransactionBuilder) WithChainID(id chain.Checksum256) *TransactionBuilder {
+	b.chainID = &id
+	return b
+}
+
+// Set the TAPOS reference block, the transaction is only valid on chains that include the block.
+func (b *TransactionBuilder) WithReferenceBlock(id chain.BlockID) *TransactionBuilder {
+	b.tx.SetReferenceBlock(id)
+	b.hasRef = true
+	return b
+}
+
+// Set the head block time the expiration is relative to, defaults to the current time.
+func (b *TransactionBuilder) WithHeadTime(t time.Time) *TransactionBuilder {
+	b.headTime = t
+	return b
+}
+
+// Set the transaction to expire the duration after the head block time.
+func (b *TransactionBuilder) ExpireIn(d time.Duration) *TransactionBuilder {
+	b.expireIn = d
+	b.expires = nil
+	return b
+}
+
+// Set an absolute expiration time.
+func (b *TransactionBuilder) WithExpiration(t chain.TimePointSec) *TransactionBuilder {
+	b.expires = &t
+	return b
+}
+
+// Set the resource limits of the transaction, zero means no limit.
+func (b *TransactionBuilder) WithResourceLimits(maxNetUsageWords uint, maxCpuUsageMs uint8) *TransactionBuilder {
+	b.tx.MaxNetUsageWords = maxNetUsageWords
+	b.tx.MaxCpuUsageMs = maxCpuUsageMs
+	return b
+}
+
+func (b *TransactionBuilder) WithDelay(d time.Duration) *TransactionBuilder {
+	b.tx.DelaySec = uint(d / time.Second)
+	return b
+}
+
+func (b *TransactionBuilder) AddAction(actions ...chain.Action) *TransactionBuilder {
+	b.tx.Actions = append(b.tx.Actions, actions...)
+	return b
+}
+
+func (b *TransactionBuilder) AddContextFreeAction(actions ...chain.Action) *TransactionBuilder {
+	b.tx.ContextFreeActions = append(b.tx.ContextFreeActions, actions...)
+	return b
+}
+
+// Add an action with data encoded from a typed value, either an abi.Marshaler or a struct
+// with fields matching the action definition.
+func (b *TransactionBuilder) AddActionData(account chain.Name, name chain.Name, auth []chain.PermissionLevel, data interface{}) *TransactionBuilder {
+	buf := bytes.NewBuffer(nil)
+	err := chain.NewEncoder(buf).Encode(data)
+	if err != nil {
+		return b.fail(fmt.Errorf("signing: unable to encode %s::%s: %w", account, name, err))
+	}
+	return b.AddAction(chain.Action{
+		Account:       account,
+		Name:          name,
+		Authorization: auth,
+		Data:          buf.Bytes(),
+	})
+}
+
+// Add an action with data encoded using the contract ABI, e.g. from a map[string]interface{}.
+func (b *TransactionBuilder) AddActionABI(abi *chain.Abi, account chain.Name, name chain.Name, auth []chain.PermissionLevel, data interface{}) *TransactionBuilder {
+	buf := bytes.NewBuffer(nil)
+	err := abi.EncodeAction(buf, name, data)
+	if err != nil {
+		return b.fail(fmt.Errorf("signing: unable to encode %s::%s: %w", account, name, err))
+	}
+	return b.AddAction(chain.Action{
+		Account:       account,
+		Name:          name,
+		Authorization: auth,
+		Data:          buf.Bytes(),
+	})
+}
+
+func (b *TransactionBuilder) AddExtension(ext chain.TransactionExtension) *TransactionBuilder {
+	b.tx.Extensions = append(b.tx.Extensions, ext)
+	return b
+}
+
+// Return the built transaction.
+func (b *TransactionBuilder) Build() (*chain.Transaction, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if !b.hasRef {
+		return nil, ErrNoReferenceBlock
+	}
+	tx := b.tx
+	if b.expires != nil {
+		tx.Expiration = *b.expires
+	} else {
+		head := b.headTime
+		if head.IsZero() {
+			head = time.Now()
+		}
+		tx.Expiration = chain.NewTimePointSec(head.Add(b.expireIn))
+	}
+	tx.ContextFreeActions = append([]chain.Action{}, tx.ContextFreeActions...)
+	tx.Actions = append([]chain.Action{}, tx.Actions...)
+	tx.Extensions = append([]chain.TransactionExtension{}, tx.Extensions...)
+	return &tx, nil
+}
+
+// Build and sign the transaction using the provider, see SignTransaction.
+func (b *TransactionBuilder) Sign(ctx context.Context, p SignatureProvider, requiredKeys ...chain.PublicKey) (*chain.SignedTransaction, error) {
+	tx, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	if b.chainID == nil {
+		return nil, ErrNoChainID
+	}
+	return SignTransaction(ctx, p, *tx, *b.chainID, requiredKeys)
+}
+
+// helpers
+
+func (b *TransactionBuilder) fail(err error) *TransactionBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
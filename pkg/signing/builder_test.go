@@ -0,0 +1,93 @@
+package signing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/signing"
+)
+
+var tokenAbi = &chain.Abi{
+	Version: "eosio::abi/1.1",
+	Structs: []chain.AbiStruct{
+		{
+			Name: "transfer",
+			Fields: []chain.AbiField{
+				{Name: "from", Type: "name"},
+				{Name: "to", Type: "name"},
+				{Name: "quantity", Type: "asset"},
+				{Name: "memo", Type: "string"},
+			},
+		},
+	},
+	Actions: []chain.AbiAction{{Name: chain.N("transfer"), Type: "transfer"}},
+}
+
+type transfer struct {
+	From     chain.Name
+	To       chain.Name
+	Quantity *chain.Asset
+	Memo     string
+}
+
+func TestTransactionBuilder(t *testing.T) {
+	ctx := context.Background()
+	var ref chain.BlockID
+	assert.NoError(t, ref.UnmarshalText([]byte("0b9cd4a7d3d0c7c6ba1d4f2aa3b8fd6d8a73b1e3ab54dd01d4d54f7e9a04ad30")))
+	auth := []chain.PermissionLevel{{Actor: chain.N("alice"), Permission: chain.N("active")}}
+	head := time.Date(2021, 6, 28, 14, 31, 35, 500000000, time.UTC)
+
+	tx, err := signing.NewTransactionBuilder().
+		WithReferenceBlock(ref).
+		WithHeadTime(head).
+		ExpireIn(30*time.Second).
+		AddActionData(chain.N("eosio.token"), chain.N("transfer"), auth, transfer{
+			chain.N("alice"), chain.N("bob"), chain.A("1.0000 EOS"), "hi",
+		}).
+		AddActionABI(tokenAbi, chain.N("eosio.token"), chain.N("transfer"), auth, map[string]interface{}{
+			"from": chain.N("alice"), "to": chain.N("bob"), "quantity": *chain.A("1.0000 EOS"), "memo": "hi",
+		}).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, tx.RefBlockNum, uint16(54439))
+	assert.Equal(t, tx.RefBlockPrefix, uint32(709828026))
+	assert.Equal(t, tx.Expiration.String(), "2021-06-28T14:32:05")
+	assert.Equal(t, len(tx.Actions), 2)
+	assert.Equal(t, tx.Actions[0].Data, tx.Actions[1].Data)
+	decoded, err := tx.Actions[0].Decode(tokenAbi)
+	assert.NoError(t, err)
+	assert.Equal(t, decoded["to"], chain.N("bob"))
+
+	_, err = signing.NewTransactionBuilder().Build()
+	assert.Equal(t, err, signing.ErrNoReferenceBlock)
+	_, err = signing.NewTransactionBuilder().
+		WithReferenceBlock(ref).
+		AddActionABI(tokenAbi, chain.N("eosio.token"), chain.N("transfer"), auth, map[string]interface{}{"from": chain.N("alice")}).
+		Build()
+	assert.NotNil(t, err)
+
+	keys, _ := signing.NewKeyProviderFromStrings(aliceKey)
+	_, err = signing.NewTransactionBuilder().WithReferenceBlock(ref).Sign(ctx, keys)
+	assert.Equal(t, err, signing.ErrNoChainID)
+
+	info := chain.ChainInfo{
+		HeadBlockTime:           chain.NewTimePoint(head),
+		LastIrreversibleBlockID: ref,
+	}
+	info.ChainID[0] = 1
+	expiration := chain.TimePointSec(1624891000)
+	stx, err := signing.NewTransactionBuilder().
+		WithChainInfo(info).
+		WithExpiration(expiration).
+		AddAction(tx.Actions[0]).
+		Sign(ctx, keys)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Expiration, expiration)
+	assert.Equal(t, stx.RefBlockPrefix, uint32(709828026))
+	recovered, err := stx.Signatures[0].RecoverDigest(stx.SigningDigest(info.ChainID))
+	assert.NoError(t, err)
+	assert.Equal(t, recovered.String(), alicePub)
+}
@@ -0,0 +1,99 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+// Combine policies, all of them must allow the request.
+func Policies(policies ...Policy) Policy {
+	return func(ctx context.Context, req *SignRequest) error {
+		for _, policy := range policies {
+			err := policy(ctx, req)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Policy that calls fn for every action in the transaction, including context free actions.
+func ActionPolicy(fn func(ctx context.Context, action chain.Action) error) Policy {
+	return func(ctx context.Context, req *SignRequest) error {
+		if req.Transaction == nil {
+			return nil
+		}
+		tx := req.Transaction.Transaction
+		for _, actions := range [][]chain.Action{tx.ContextFreeActions, tx.Actions} {
+			for _, action := range actions {
+				err := fn(ctx, action)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// Policy only allowing transactions with actions on given contracts.
+func AllowContracts(contracts ...chain.Name) Policy {
+	return ActionPolicy(func(ctx context.Context, action chain.Action) error {
+		for _, contract := range contracts {
+			if action.Account == contract {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: contract %s not allowed", ErrRejected, action.Account)
+	})
+}
+
+// Policy limiting the total quantity of token transfers with the same symbol code on given contract,
+// transfers are summed across the transaction regardless of their precision. The transfer action data
+// is expected to be in the eosio.token format.
+func MaxTransfer(contract chain.Name, max chain.Asset) Policy {
+	return func(ctx context.Context, req *SignRequest) error {
+		if req.Transaction == nil {
+			return nil
+		}
+		total := new(big.Rat)
+		decimals := max.Symbol.Decimals()
+		tx := req.Transaction.Transaction
+		for _, actions := range [][]chain.Action{tx.ContextFreeActions, tx.Actions} {
+			for _, action := range actions {
+				if action.Account != contract || action.Name != chain.N("transfer") {
+					continue
+				}
+				var transfer tokenTransfer
+				err := action.DecodeInto(&transfer)
+				if err != nil {
+					return fmt.Errorf("%w: unable to decode transfer: %v", ErrRejected, err)
+				}
+				if transfer.Quantity.Symbol.Code() != max.Symbol.Code() {
+					continue
+				}
+				total.Add(total, transfer.Quantity.Rat())
+				if d := transfer.Quantity.Symbol.Decimals(); d > decimals {
+					decimals = d
+				}
+			}
+		}
+		if total.Cmp(max.Rat()) > 0 {
+			return fmt.Errorf("%w: transfers of %s %s exceed maximum of %s", ErrRejected, total.FloatString(decimals), max.Symbol.Name(), max.String())
+		}
+		return nil
+	}
+}
+
+// helpers
+
+type tokenTransfer struct {
+	From     chain.Name  `json:"from"`
+	To       chain.Name  `json:"to"`
+	Quantity chain.Asset `json:"quantity"`
+	Memo     string      `json:"memo"`
+}
@@ -0,0 +1,52 @@
+package signing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/signing"
+)
+
+func TestPolicies(t *testing.T) {
+	ctx := context.Background()
+	policy := signing.Policies(
+		signing.AllowContracts(chain.N("eosio.token"), chain.N("eosio.null")),
+		signing.MaxTransfer(chain.N("eosio.token"), *chain.A("10.0000 EOS")),
+	)
+	tx := transferTx("100.0000 EOS")
+	req := &signing.SignRequest{Transaction: &chain.SignedTransaction{Transaction: tx}}
+	assert.True(t, errors.Is(policy(ctx, req), signing.ErrRejected))
+
+	// other symbols are not limited
+	tx = transferTx("100.0000 JUNGLE")
+	req.Transaction.Transaction = tx
+	assert.NoError(t, policy(ctx, req))
+
+	// transfers are summed across the transaction
+	req.Transaction.Transaction = transferTx("6.0000 EOS", "4.0000 EOS")
+	assert.NoError(t, policy(ctx, req))
+	req.Transaction.Transaction = transferTx("6.0000 EOS", "4.0000 EOS", "0.0001 EOS")
+	err := policy(ctx, req)
+	assert.True(t, errors.Is(err, signing.ErrRejected))
+	assert.Equal(t, err.Error(), "signing: request rejected: transfers of 10.0001 EOS exceed maximum of 10.0000 EOS")
+
+	// symbols with the same code but another precision count towards the maximum
+	req.Transaction.Transaction = transferTx("100 EOS")
+	assert.True(t, errors.Is(policy(ctx, req), signing.ErrRejected))
+	req.Transaction.Transaction = transferTx("9.99999999 EOS", "0.00000002 EOS")
+	assert.True(t, errors.Is(policy(ctx, req), signing.ErrRejected))
+	req.Transaction.Transaction = transferTx("9.99999999 EOS", "0.0000 EOS")
+	assert.NoError(t, policy(ctx, req))
+
+	tx = transferTx("100.0000 JUNGLE")
+	// context free actions are checked as well
+	tx.ContextFreeActions = []chain.Action{{Account: chain.N("other"), Name: chain.N("nonce")}}
+	req.Transaction.Transaction = tx
+	assert.True(t, errors.Is(policy(ctx, req), signing.ErrRejected))
+
+	// digest requests have no actions to inspect
+	assert.NoError(t, policy(ctx, &signing.SignRequest{Digest: &chain.Checksum256{}}))
+}
@@ -0,0 +1,259 @@
+package signing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var (
+	ErrRejected           = errors.New("signing: request rejected")
+	ErrDigestNotAllowed   = errors.New("signing: signing bare digests is not allowed")
+	ErrInvalidSignRequest = errors.New("signing: request must contain either a transaction or a digest")
+)
+
+// Request sent to a remote signer, either a transaction or a bare digest.
+type SignRequest struct {
+	ChainID      chain.Checksum256        `json:"chain_id"`
+	Transaction  *chain.SignedTransaction `json:"transaction,omitempty"`
+	Digest       *chain.Checksum256       `json:"digest,omitempty"`
+	RequiredKeys []chain.PublicKey        `json:"required_keys"`
+}
+
+// Response from a remote signer.
+type SignResponse struct {
+	Signatures []chain.Signature `json:"signatures"`
+}
+
+// Response listing the keys available to a remote signer.
+type KeysResponse struct {
+	Keys []chain.PublicKey `json:"keys"`
+}
+
+// Error returned by a remote signer.
+type RemoteError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"error"`
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("signing: remote signer error (%d): %s", e.StatusCode, e.Message)
+}
+
+// Inspects a sign request before it is signed, returning an error rejects the request.
+type Policy func(ctx context.Context, req *SignRequest) error
+
+// Record of a sign request handled by the server.
+type AuditEntry struct {
+	Time       time.Time
+	RemoteAddr string
+	Request    *SignRequest
+	Digest     chain.Checksum256
+	Signatures []chain.Signature
+	Err        error
+}
+
+// Maximum size of a sign request body accepted by the server, leaves room for the hex encoded
+// action data of transactions at the default max_transaction_net_usage of nodeos.
+const MaxRequestSize = 4 << 20
+
+// HTTP server exposing a signature provider to remote clients.
+//
+//	GET  /v1/keys - list available keys
+//	POST /v1/sign - sign a transaction or digest
+type Server struct {
+	// Provider used to create the signatures.
+	Provider SignatureProvider
+	// Policy applied to all requests, nil allows everything.
+	Policy Policy
+	// Called for every sign request after it has been handled, including rejected requests.
+	Audit func(entry AuditEntry)
+	// Allow signing bare digests, the policy can not inspect what is being signed for these requests.
+	AllowDigest bool
+}
+
+func NewServer(provider SignatureProvider, policy Policy) *Server {
+	return &Server{
+		Provider: provider,
+		Policy:   policy,
+	}
+}
+
+// http.Handler conformance
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/v1/keys":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		keys, err := s.Provider.AvailableKeys(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, KeysResponse{Keys: keys})
+	case "/v1/sign":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		var req SignRequest
+		err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestSize)).Decode(&req)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		entry := AuditEntry{
+			Time:       time.Now(),
+			RemoteAddr: r.RemoteAddr,
+			Request:    &req,
+		}
+		status := http.StatusOK
+		entry.Digest, entry.Signatures, status, entry.Err = s.sign(r.Context(), &req)
+		if s.Audit != nil {
+			s.Audit(entry)
+		}
+		if entry.Err != nil {
+			writeError(w, status, entry.Err)
+			return
+		}
+		writeJSON(w, http.StatusOK, SignResponse{Signatures: entry.Signatures})
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// Client for a remote signer, implements the SignatureProvider and TransactionSigner interfaces.
+type RemoteProvider struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewRemoteProvider(url string) *RemoteProvider {
+	return &RemoteProvider{
+		URL:    strings.TrimSuffix(url, "/"),
+		Client: http.DefaultClient,
+	}
+}
+
+// SignatureProvider conformance
+
+func (p *RemoteProvider) AvailableKeys(ctx context.Context) ([]chain.PublicKey, error) {
+	var res KeysResponse
+	err := p.do(ctx, http.MethodGet, "/v1/keys", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Keys, nil
+}
+
+func (p *RemoteProvider) Sign(ctx context.Context, digest chain.Checksum256, requiredKeys []chain.PublicKey) ([]chain.Signature, error) {
+	return p.send(ctx, &SignRequest{
+		Digest:       &digest,
+		RequiredKeys: requiredKeys,
+	})
+}
+
+// TransactionSigner conformance
+
+func (p *RemoteProvider) SignTransaction(ctx context.Context, tx chain.SignedTransaction, chainID chain.Checksum256, requiredKeys []chain.PublicKey) ([]chain.Signature, error) {
+	return p.send(ctx, &SignRequest{
+		ChainID:      chainID,
+		Transaction:  &tx,
+		RequiredKeys: requiredKeys,
+	})
+}
+
+// helpers
+
+func (s *Server) sign(ctx context.Context, req *SignRequest) (chain.Checksum256, []chain.Signature, int, error) {
+	var digest chain.Checksum256
+	switch {
+	case req.Transaction != nil && req.Digest == nil:
+		digest = req.Transaction.SigningDigest(req.ChainID)
+	case req.Digest != nil && req.Transaction == nil:
+		if !s.AllowDigest {
+			return digest, nil, http.StatusForbidden, ErrDigestNotAllowed
+		}
+		digest = *req.Digest
+	default:
+		return digest, nil, http.StatusBadRequest, ErrInvalidSignRequest
+	}
+	if s.Policy != nil {
+		err := s.Policy(ctx, req)
+		if err != nil {
+			return digest, nil, http.StatusForbidden, err
+		}
+	}
+	sigs, err := s.Provider.Sign(ctx, digest, req.RequiredKeys)
+	if err != nil {
+		return digest, nil, http.StatusInternalServerError, err
+	}
+	return digest, sigs, http.StatusOK, nil
+}
+
+func (p *RemoteProvider) send(ctx context.Context, req *SignRequest) ([]chain.Signature, error) {
+	if req.RequiredKeys == nil {
+		req.RequiredKeys = []chain.PublicKey{}
+	}
+	var res SignResponse
+	err := p.do(ctx, http.MethodPost, "/v1/sign", req, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Signatures, nil
+}
+
+func (p *RemoteProvider) do(ctx context.Context, method string, path string, body interface{}, v interface{}) error {
+	var b bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&b).Encode(body)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.URL+path, &b)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		rerr := &RemoteError{StatusCode: res.StatusCode}
+		err = json.NewDecoder(res.Body).Decode(rerr)
+		if err != nil || rerr.Message == "" {
+			rerr.Message = http.StatusText(res.StatusCode)
+		}
+		return rerr
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, RemoteError{Message: err.Error()})
+}
@@ -0,0 +1,106 @@
+package signing_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/signing"
+)
+
+func transferTx(quantities ...string) chain.Transaction {
+	tx := chain.Transaction{
+		TransactionHeader: chain.TransactionHeader{Expiration: chain.TimePointSec(1234567890)},
+	}
+	for _, quantity := range quantities {
+		b := bytes.NewBuffer(nil)
+		err := chain.NewEncoder(b).Encode(struct {
+			From     chain.Name
+			To       chain.Name
+			Quantity *chain.Asset
+			Memo     string
+		}{chain.N("alice"), chain.N("bob"), chain.A(quantity), "hi"})
+		if err != nil {
+			panic(err)
+		}
+		tx.Actions = append(tx.Actions, chain.Action{
+			Account:       chain.N("eosio.token"),
+			Name:          chain.N("transfer"),
+			Authorization: []chain.PermissionLevel{{Actor: chain.N("alice"), Permission: chain.N("active")}},
+			Data:          b.Bytes(),
+		})
+	}
+	return tx
+}
+
+func TestRemoteSigner(t *testing.T) {
+	ctx := context.Background()
+	keys, _ := signing.NewKeyProviderFromStrings(aliceKey)
+	var audit []signing.AuditEntry
+	server := signing.NewServer(keys, signing.Policies(
+		signing.AllowContracts(chain.N("eosio.token")),
+		signing.MaxTransfer(chain.N("eosio.token"), *chain.A("10.0000 EOS")),
+	))
+	server.Audit = func(entry signing.AuditEntry) {
+		audit = append(audit, entry)
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	var remote signing.TransactionSigner = signing.NewRemoteProvider(ts.URL)
+	available, err := remote.AvailableKeys(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(available), 1)
+	assert.Equal(t, available[0].String(), alicePub)
+
+	var chainID chain.Checksum256
+	chainID[0] = 1
+	tx := transferTx("1.0000 EOS")
+	stx, err := signing.SignTransaction(ctx, remote, tx, chainID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, len(stx.Signatures), 1)
+	recovered, err := stx.Signatures[0].RecoverDigest(tx.SigningDigest(chainID))
+	assert.NoError(t, err)
+	assert.Equal(t, recovered.String(), alicePub)
+	assert.Equal(t, len(audit), 1)
+	assert.NoError(t, audit[0].Err)
+	assert.Equal(t, audit[0].Digest, tx.SigningDigest(chainID))
+	assert.Equal(t, audit[0].Request.ChainID, chainID)
+
+	_, err = signing.SignTransaction(ctx, remote, transferTx("10.0001 EOS"), chainID, nil)
+	var rerr *signing.RemoteError
+	assert.True(t, errors.As(err, &rerr))
+	assert.Equal(t, rerr.StatusCode, 403)
+	assert.Equal(t, rerr.Message, "signing: request rejected: transfers of 10.0001 EOS exceed maximum of 10.0000 EOS")
+	assert.Equal(t, len(audit), 2)
+	assert.True(t, errors.Is(audit[1].Err, signing.ErrRejected))
+
+	other := transferTx("1.0000 EOS")
+	other.Actions[0].Account = chain.N("fake.token")
+	_, err = signing.SignTransaction(ctx, remote, other, chainID, nil)
+	assert.True(t, errors.As(err, &rerr))
+	assert.Equal(t, rerr.Message, "signing: request rejected: contract fake.token not allowed")
+
+	digest := chain.Checksum256Digest([]byte("hello"))
+	_, err = remote.Sign(ctx, digest, available)
+	assert.True(t, errors.As(err, &rerr))
+	assert.Equal(t, rerr.Message, signing.ErrDigestNotAllowed.Error())
+
+	server.AllowDigest = true
+	sigs, err := remote.Sign(ctx, digest, available)
+	assert.NoError(t, err)
+	assert.Equal(t, len(sigs), 1)
+	assert.Equal(t, len(audit), 5)
+
+	// oversized requests are rejected before reaching the policy
+	large := transferTx("1.0000 EOS")
+	large.Actions[0].Data = make([]byte, signing.MaxRequestSize/2)
+	_, err = signing.SignTransaction(ctx, remote, large, chainID, nil)
+	assert.True(t, errors.As(err, &rerr))
+	assert.Equal(t, rerr.StatusCode, 400)
+	assert.Equal(t, len(audit), 5)
+}
@@ -0,0 +1,123 @@
+// Package signing contains the signature provider interface used to authorize transactions.
+package signing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/greymass/go-eosio/pkg/chain"
+)
+
+var ErrNoMatchingKeys = errors.New("signing: no available key matches the required keys")
+
+// Provides signatures for a set of public keys, implemented by in-memory key sets,
+// encrypted keystores and remote or hardware signers.
+type SignatureProvider interface {
+	// Public keys the provider is able to sign with.
+	AvailableKeys(ctx context.Context) ([]chain.PublicKey, error)
+	// Sign digest with all available keys that are also in the required keys.
+	Sign(ctx context.Context, digest chain.Checksum256, requiredKeys []chain.PublicKey) ([]chain.Signature, error)
+}
+
+// Signature provider that signs whole transactions instead of digests, allowing the
+// signer to inspect what it is signing, e.g. a remote signer enforcing a policy.
+type TransactionSigner interface {
+	SignatureProvider
+	// Sign transaction for given chain with all available keys that are also in the required keys.
+	SignTransaction(ctx context.Context, tx chain.SignedTransaction, chainID chain.Checksum256, requiredKeys []chain.PublicKey) ([]chain.Signature, error)
+}
+
+// Sign transaction for given chain using the provider, if no required keys are
+// given all keys available to the provider are used.
+func SignTransaction(ctx context.Context, p SignatureProvider, tx chain.Transaction, chainID chain.Checksum256, requiredKeys []chain.PublicKey) (*chain.SignedTransaction, error) {
+	var err error
+	if requiredKeys == nil {
+		requiredKeys, err = p.AvailableKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	stx := &chain.SignedTransaction{
+		Transaction:     tx,
+		Signatures:      []chain.Signature{},
+		ContextFreeData: []chain.Bytes{},
+	}
+	var sigs []chain.Signature
+	if ts, ok := p.(TransactionSigner); ok {
+		sigs, err = ts.SignTransaction(ctx, *stx, chainID, requiredKeys)
+	} else {
+		sigs, err = p.Sign(ctx, stx.SigningDigest(chainID), requiredKeys)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(sigs) == 0 {
+		return nil, ErrNoMatchingKeys
+	}
+	stx.Signatures = sigs
+	return stx, nil
+}
+
+// Signature provider holding private keys in memory.
+type KeyProvider struct {
+	keys []chain.PrivateKey
+	pubs []chain.PublicKey
+}
+
+// Create a signature provider for given private keys, only K1 keys are supported.
+func NewKeyProvider(keys ...chain.PrivateKey) (*KeyProvider, error) {
+	p := &KeyProvider{}
+	for _, key := range keys {
+		pub, err := key.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		p.keys = append(p.keys, key)
+		p.pubs = append(p.pubs, *pub)
+	}
+	return p, nil
+}
+
+// Create a signature provider from private key strings, e.g. PVT_K1_... or legacy WIF keys.
+func NewKeyProviderFromStrings(keys ...string) (*KeyProvider, error) {
+	var pks []chain.PrivateKey
+	for _, s := range keys {
+		key, err := chain.NewPrivateKeyFromString(s)
+		if err != nil {
+			return nil, err
+		}
+		pks = append(pks, *key)
+	}
+	return NewKeyProvider(pks...)
+}
+
+// SignatureProvider conformance
+
+func (p *KeyProvider) AvailableKeys(ctx context.Context) ([]chain.PublicKey, error) {
+	return append([]chain.PublicKey(nil), p.pubs...), nil
+}
+
+func (p *KeyProvider) Sign(ctx context.Context, digest chain.Checksum256, requiredKeys []chain.PublicKey) ([]chain.Signature, error) {
+	var sigs []chain.Signature
+	for i, pub := range p.pubs {
+		if !ContainsKey(requiredKeys, pub) {
+			continue
+		}
+		sig, err := p.keys[i].SignDigest(digest)
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, *sig)
+	}
+	return sigs, nil
+}
+
+// Check if key is in the list of keys.
+func ContainsKey(keys []chain.PublicKey, key chain.PublicKey) bool {
+	for _, k := range keys {
+		if k.Equal(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package signing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greymass/go-eosio/internal/assert"
+	"github.com/greymass/go-eosio/pkg/chain"
+	"github.com/greymass/go-eosio/pkg/signing"
+)
+
+const (
+	aliceKey = "PVT_K1_LJw5aNdCDEW3WTta2nCqm5GkeiXHYw79iefcFzgSJRRRwEPkU"
+	alicePub = "PUB_K1_7zsqi7QUAjTAdyynd6DVe8uv4K8gCTRHnAoMN9w9CA1xKwj6Qn"
+	bobKey   = "PVT_K1_z8KBsKppZhw8yhtTrGkkMiYiWqvWXTf6Z5tiM9ng6cqNFKmXy"
+	bobPub   = "PUB_K1_5VE6Dgy9FUmd1mFotXwF88HkQN1KysCWLPqpVnDMjRvGLi8JKw"
+)
+
+func TestKeyProvider(t *testing.T) {
+	ctx := context.Background()
+	p, err := signing.NewKeyProviderFromStrings(aliceKey, bobKey)
+	assert.NoError(t, err)
+	keys, err := p.AvailableKeys(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(keys), 2)
+	assert.Equal(t, keys[0].String(), alicePub)
+	assert.Equal(t, keys[1].String(), bobPub)
+
+	digest := chain.Checksum256Digest([]byte("hello"))
+	sigs, err := p.Sign(ctx, digest, []chain.PublicKey{keys[1]})
+	assert.NoError(t, err)
+	assert.Equal(t, len(sigs), 1)
+	recovered, err := sigs[0].RecoverDigest(digest)
+	assert.NoError(t, err)
+	assert.Equal(t, recovered.String(), bobPub)
+
+	sigs, err = p.Sign(ctx, digest, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, len(sigs), 0)
+
+	_, err = signing.NewKeyProviderFromStrings("PVT_K1_invalid")
+	assert.NotNil(t, err)
+}
+
+func TestSignTransaction(t *testing.T) {
+	ctx := context.Background()
+	p, _ := signing.NewKeyProviderFromStrings(aliceKey)
+	tx := chain.Transaction{
+		TransactionHeader: chain.TransactionHeader{Expiration: chain.TimePointSec(1234567890)},
+		Actions: []chain.Action{
+			{
+				Account:       chain.N("eosio.token"),
+				Name:          chain.N("transfer"),
+				Authorization: []chain.PermissionLevel{{Actor: chain.N("alice"), Permission: chain.N("active")}},
+				Data:          []byte{},
+			},
+		},
+	}
+	var chainID chain.Checksum256
+	chainID[0] = 1
+
+	stx, err := signing.SignTransaction(ctx, p, tx, chainID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, stx.Transaction, tx)
+	assert.Equal(t, len(stx.Signatures), 1)
+	recovered, err := stx.Signatures[0].RecoverDigest(tx.SigningDigest(chainID))
+	assert.NoError(t, err)
+	assert.Equal(t, recovered.String(), alicePub)
+
+	other, _ := chain.NewPublicKeyFromString(bobPub)
+	_, err = signing.SignTransaction(ctx, p, tx, chainID, []chain.PublicKey{*other})
+	assert.Equal(t, err, signing.ErrNoMatchingKeys)
+}